@@ -14,6 +14,7 @@ import (
 	"github.com/fox-one/mixin-sdk-go"
 	"github.com/fox-one/pkg/qrcode"
 	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
 	"github.com/spf13/cobra"
 )
 
@@ -125,6 +126,12 @@ var removeAllowListCmd = &cobra.Command{
 type BuildMemoFunc func(ctx context.Context, clientID, traceID uuid.UUID) ([]byte, error)
 
 func buildProposalTransfer(cmd *cobra.Command, f BuildMemoFunc) {
+	system := provideSystem()
+	buildMemberTransfer(cmd, system.VoteAsset, system.VoteAmount, f)
+}
+
+// buildMemberTransfer build the payment of the member action signed by the member, carrying the asset
+func buildMemberTransfer(cmd *cobra.Command, assetID string, amount decimal.Decimal, f BuildMemoFunc) {
 	ctx := cmd.Context()
 	system := provideSystem()
 	dapp := provideDapp()
@@ -141,8 +148,8 @@ func buildProposalTransfer(cmd *cobra.Command, f BuildMemoFunc) {
 	memo = mtg.Pack(memo, sign)
 
 	input := mixin.TransferInput{
-		AssetID: system.VoteAsset,
-		Amount:  system.VoteAmount,
+		AssetID: assetID,
+		Amount:  amount,
 		TraceID: traceID.String(),
 		Memo:    base64.StdEncoding.EncodeToString(memo),
 	}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"compound/core"
+	"compound/core/proposal"
+	"compound/pkg/mtg"
+	"context"
+	"strings"
+
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+// governing command for reserve auctions
+var startAuctionCmd = &cobra.Command{
+	Use:     "start-auction",
+	Aliases: []string{"sa"},
+	Short:   "start the auction of the market reserves",
+	Long:    "s for symbol, a for amount of reserves, ba for bid asset, o for opponent receiving the proceeds, t for type(1: english, 2: dutch), sp for start price, ep for end price, d for duration in seconds",
+	Run: func(cmd *cobra.Command, args []string) {
+		buildProposalTransfer(cmd, func(ctx context.Context, clientID, traceID uuid.UUID) ([]byte, error) {
+			req := proposal.StartAuctionReq{}
+
+			symbol, e := cmd.Flags().GetString("s")
+			if e != nil || symbol == "" {
+				panic("invalid symbol")
+			}
+			req.Symbol = strings.ToUpper(symbol)
+
+			flag, e := cmd.Flags().GetString("a")
+			if e != nil {
+				panic("invalid flag")
+			}
+			amount, e := decimal.NewFromString(flag)
+			if e != nil || amount.LessThanOrEqual(decimal.Zero) {
+				panic("invalid amount")
+			}
+			req.Amount = amount
+
+			bidAsset, e := cmd.Flags().GetString("ba")
+			if e != nil {
+				panic("invalid flag")
+			}
+			if _, e = uuid.FromString(bidAsset); e != nil {
+				panic("invalid bid asset")
+			}
+			req.BidAsset = bidAsset
+
+			opponent, e := cmd.Flags().GetString("o")
+			if e != nil {
+				panic("invalid flag")
+			}
+			if _, e = uuid.FromString(opponent); e != nil {
+				panic("invalid opponent")
+			}
+			req.Opponent = opponent
+
+			auctionType, e := cmd.Flags().GetInt("t")
+			if e != nil || (core.AuctionType(auctionType) != core.AuctionTypeEnglish && core.AuctionType(auctionType) != core.AuctionTypeDutch) {
+				panic("invalid auction type")
+			}
+			req.Type = auctionType
+
+			flag, e = cmd.Flags().GetString("sp")
+			if e != nil {
+				panic("invalid flag")
+			}
+			sp, e := decimal.NewFromString(flag)
+			if e != nil || sp.LessThan(decimal.Zero) {
+				panic("invalid start price")
+			}
+			req.StartPrice = sp
+
+			flag, e = cmd.Flags().GetString("ep")
+			if e != nil {
+				panic("invalid flag")
+			}
+			ep, e := decimal.NewFromString(flag)
+			if e != nil || ep.LessThanOrEqual(decimal.Zero) {
+				panic("invalid end price")
+			}
+			req.EndPrice = ep
+
+			duration, e := cmd.Flags().GetInt64("d")
+			if e != nil || duration <= 0 {
+				panic("invalid duration")
+			}
+			req.Duration = duration
+
+			return mtg.Encode(clientID, traceID, int(core.ActionTypeProposalStartAuction), req)
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(startAuctionCmd)
+
+	startAuctionCmd.Flags().String("s", "", "market symbol")
+	startAuctionCmd.Flags().String("a", "", "amount of reserves to auction")
+	startAuctionCmd.Flags().String("ba", "", "bid asset id")
+	startAuctionCmd.Flags().String("o", "", "opponent id receiving the proceeds")
+	startAuctionCmd.Flags().Int("t", int(core.AuctionTypeEnglish), "auction type, 1: english, 2: dutch")
+	startAuctionCmd.Flags().String("sp", "0", "start price of the dutch auction, bid asset amount for the whole lot")
+	startAuctionCmd.Flags().String("ep", "", "end price of the dutch auction or the minimum bid of the english auction")
+	startAuctionCmd.Flags().Int64("d", 86400, "auction duration in seconds")
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"compound/core"
+	"compound/core/proposal"
+	"compound/pkg/mtg"
+	"context"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/gofrs/uuid"
+	"github.com/spf13/cobra"
+)
+
+// the proposal requests could be batched
+var batchRequests = map[core.ActionType]func() encoding.BinaryMarshaler{
+	core.ActionTypeProposalAddMarket:               func() encoding.BinaryMarshaler { return &proposal.AddMarketReq{} },
+	core.ActionTypeProposalUpdateMarket:            func() encoding.BinaryMarshaler { return &proposal.UpdateMarketReq{} },
+	core.ActionTypeProposalUpdateMarketAdvance:     func() encoding.BinaryMarshaler { return &proposal.UpdateMarketAdvanceReq{} },
+	core.ActionTypeProposalUpdateInterestRateModel: func() encoding.BinaryMarshaler { return &proposal.UpdateInterestRateModelReq{} },
+	core.ActionTypeProposalUpdateIsolation:         func() encoding.BinaryMarshaler { return &proposal.UpdateIsolationReq{} },
+	core.ActionTypeProposalUpdateCategory:          func() encoding.BinaryMarshaler { return &proposal.UpdateCategoryReq{} },
+	core.ActionTypeProposalSetMarketCategory:       func() encoding.BinaryMarshaler { return &proposal.MarketCategoryReq{} },
+	core.ActionTypeProposalUpdateRewardSpeed:       func() encoding.BinaryMarshaler { return &proposal.UpdateRewardSpeedReq{} },
+	core.ActionTypeProposalUpdateLiquidationMode:   func() encoding.BinaryMarshaler { return &proposal.UpdateLiquidationModeReq{} },
+	core.ActionTypeProposalUpdatePriceGuard:        func() encoding.BinaryMarshaler { return &proposal.UpdatePriceGuardReq{} },
+	core.ActionTypeProposalUpdatePriceConsensus:    func() encoding.BinaryMarshaler { return &proposal.UpdatePriceConsensusReq{} },
+	core.ActionTypeProposalCloseMarket:             func() encoding.BinaryMarshaler { return &proposal.MarketStatusReq{} },
+	core.ActionTypeProposalOpenMarket:              func() encoding.BinaryMarshaler { return &proposal.MarketStatusReq{} },
+}
+
+// governing command for executing the proposal requests atomically
+var batchCmd = &cobra.Command{
+	Use:   "batch",
+	Short: "execute the proposal requests in order atomically, voted once",
+	Long:  "f for the json file of the requests, like [{\"action\":\"ProposalAddMarket\",\"content\":{\"symbol\":\"BTC\"}}]",
+	Run: func(cmd *cobra.Command, args []string) {
+		buildProposalTransfer(cmd, func(ctx context.Context, clientID, traceID uuid.UUID) ([]byte, error) {
+			file, e := cmd.Flags().GetString("f")
+			if e != nil || file == "" {
+				panic("invalid file")
+			}
+
+			data, e := ioutil.ReadFile(file)
+			if e != nil {
+				panic(e)
+			}
+
+			var requests []struct {
+				Action  string          `json:"action"`
+				Content json.RawMessage `json:"content"`
+			}
+			if e = json.Unmarshal(data, &requests); e != nil {
+				panic(e)
+			}
+
+			req := proposal.BatchReq{}
+			for _, r := range requests {
+				action, ok := core.ParseActionType(r.Action)
+				if !ok {
+					panic(fmt.Errorf("invalid action %s", r.Action))
+				}
+
+				newReq, ok := batchRequests[action]
+				if !ok {
+					panic(fmt.Errorf("action %s not batchable", r.Action))
+				}
+
+				content := newReq()
+				if e = json.Unmarshal(r.Content, content); e != nil {
+					panic(e)
+				}
+
+				item, e := proposal.NewBatchItem(action, content)
+				if e != nil {
+					panic(e)
+				}
+				req.Items = append(req.Items, item)
+			}
+
+			return mtg.Encode(clientID, traceID, int(core.ActionTypeProposalBatch), req)
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(batchCmd)
+	batchCmd.Flags().String("f", "", "json file of the proposal requests")
+}
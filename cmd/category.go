@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"compound/core"
+	"compound/core/proposal"
+	"compound/pkg/mtg"
+	"context"
+	"strings"
+
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+// governing command for e-mode category
+var updateCategoryCmd = &cobra.Command{
+	Use:     "update-category",
+	Aliases: []string{"uc"},
+	Short:   "create or update e-mode category",
+	Long:    "n for category name, cf for collateral_factor, li for liquidation_incentive of the category",
+	Run: func(cmd *cobra.Command, args []string) {
+		buildProposalTransfer(cmd, func(ctx context.Context, clientID, traceID uuid.UUID) ([]byte, error) {
+			req := proposal.UpdateCategoryReq{}
+
+			name, e := cmd.Flags().GetString("n")
+			if e != nil || name == "" {
+				panic("invalid category name")
+			}
+			req.Name = strings.ToUpper(name)
+
+			flag, e := cmd.Flags().GetString("cf")
+			if e != nil {
+				panic("invalid flag")
+			}
+			cf, e := decimal.NewFromString(flag)
+			if e != nil {
+				panic("invalid collateral factor")
+			}
+			req.CollateralFactor = cf
+
+			flag, e = cmd.Flags().GetString("li")
+			if e != nil {
+				panic("invalid flag")
+			}
+			li, e := decimal.NewFromString(flag)
+			if e != nil {
+				panic("invalid liquidation incentive")
+			}
+			req.LiquidationIncentive = li
+
+			return mtg.Encode(clientID, traceID, int(core.ActionTypeProposalUpdateCategory), req)
+		})
+	},
+}
+
+// governing command for the e-mode category of market
+var setMarketCategoryCmd = &cobra.Command{
+	Use:     "set-market-category",
+	Aliases: []string{"smc"},
+	Short:   "set the e-mode category of market",
+	Long:    "s for symbol, c for category name, empty category to remove the market from its category",
+	Run: func(cmd *cobra.Command, args []string) {
+		buildProposalTransfer(cmd, func(ctx context.Context, clientID, traceID uuid.UUID) ([]byte, error) {
+			req := proposal.MarketCategoryReq{}
+
+			symbol, e := cmd.Flags().GetString("s")
+			if e != nil || symbol == "" {
+				panic("invalid symbol")
+			}
+			req.Symbol = strings.ToUpper(symbol)
+
+			category, e := cmd.Flags().GetString("c")
+			if e != nil {
+				panic("invalid flag")
+			}
+			req.Category = strings.ToUpper(category)
+
+			return mtg.Encode(clientID, traceID, int(core.ActionTypeProposalSetMarketCategory), req)
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(updateCategoryCmd)
+	rootCmd.AddCommand(setMarketCategoryCmd)
+
+	updateCategoryCmd.Flags().String("n", "", "category name")
+	updateCategoryCmd.Flags().String("cf", "", "collateral factor of the category")
+	updateCategoryCmd.Flags().String("li", "", "liquidation incentive of the category")
+
+	setMarketCategoryCmd.Flags().String("s", "", "market symbol")
+	setMarketCategoryCmd.Flags().String("c", "", "category name")
+}
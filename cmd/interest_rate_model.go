@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"compound/core"
+	"compound/core/proposal"
+	"compound/pkg/mtg"
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+// governing command for market
+var updateInterestRateModelCmd = &cobra.Command{
+	Use:     "update-interest-rate-model",
+	Aliases: []string{"uirm"},
+	Short:   "switch the interest rate model of the market",
+	Long:    "s for symbol, model for jumprate, linear, fixed or dualkink, br for base_rate, m for multiplier, jm for jump_multiplier, k for kink, jm2 for second_jump_multiplier, k2 for second_kink",
+	Run: func(cmd *cobra.Command, args []string) {
+		buildProposalTransfer(cmd, func(ctx context.Context, clientID, traceID uuid.UUID) ([]byte, error) {
+			req := proposal.UpdateInterestRateModelReq{}
+
+			symbol, e := cmd.Flags().GetString("s")
+			if e != nil || symbol == "" {
+				panic("invalid symbol")
+			}
+			req.Symbol = strings.ToUpper(symbol)
+
+			name, e := cmd.Flags().GetString("model")
+			if e != nil {
+				panic("invalid flag")
+			}
+			model, e := parseInterestRateModel(name)
+			if e != nil {
+				panic(e)
+			}
+			req.Model = int(model)
+
+			for flag, value := range map[string]*decimal.Decimal{
+				"br":  &req.BaseRate,
+				"m":   &req.Multiplier,
+				"jm":  &req.JumpMultiplier,
+				"k":   &req.Kink,
+				"jm2": &req.SecondJumpMultiplier,
+				"k2":  &req.SecondKink,
+			} {
+				v, e := cmd.Flags().GetString(flag)
+				if e != nil {
+					panic("invalid flag")
+				}
+				*value, _ = decimal.NewFromString(v)
+			}
+
+			return mtg.Encode(clientID, traceID, int(core.ActionTypeProposalUpdateInterestRateModel), req)
+		})
+	},
+}
+
+func parseInterestRateModel(name string) (core.InterestRateModelType, error) {
+	for m := core.InterestRateModelJumpRate; m.IsValid(); m++ {
+		if strings.EqualFold(m.String(), name) {
+			return m, nil
+		}
+	}
+
+	return 0, errors.New("invalid interest rate model")
+}
+
+func init() {
+	rootCmd.AddCommand(updateInterestRateModelCmd)
+
+	updateInterestRateModelCmd.Flags().String("s", "", "market symbol")
+	updateInterestRateModelCmd.Flags().String("model", "", "interest rate model: jumprate, linear, fixed, dualkink")
+	updateInterestRateModelCmd.Flags().String("br", "", "base rate")
+	updateInterestRateModelCmd.Flags().String("m", "", "multiplier")
+	updateInterestRateModelCmd.Flags().String("jm", "", "jump multiplier")
+	updateInterestRateModelCmd.Flags().String("k", "", "kink")
+	updateInterestRateModelCmd.Flags().String("jm2", "", "second jump multiplier")
+	updateInterestRateModelCmd.Flags().String("k2", "", "second kink")
+}
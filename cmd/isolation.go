@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"compound/core"
+	"compound/core/proposal"
+	"compound/pkg/mtg"
+	"context"
+	"strings"
+
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+var isolationModes = map[string]core.IsolationMode{
+	"none":       core.IsolationModeNone,
+	"collateral": core.IsolationModeCollateral,
+	"borrowable": core.IsolationModeBorrowable,
+}
+
+// governing command for market
+var updateIsolationCmd = &cobra.Command{
+	Use:     "update-isolation",
+	Aliases: []string{"ui"},
+	Short:   "update market isolation mode",
+	Long:    "s for symbol, mode for none, collateral or borrowable, dc for debt_ceiling of the isolated collateral",
+	Run: func(cmd *cobra.Command, args []string) {
+		buildProposalTransfer(cmd, func(ctx context.Context, clientID, traceID uuid.UUID) ([]byte, error) {
+			req := proposal.UpdateIsolationReq{}
+
+			symbol, e := cmd.Flags().GetString("s")
+			if e != nil || symbol == "" {
+				panic("invalid symbol")
+			}
+			req.Symbol = strings.ToUpper(symbol)
+
+			flag, e := cmd.Flags().GetString("mode")
+			if e != nil {
+				panic("invalid flag")
+			}
+			mode, ok := isolationModes[strings.ToLower(flag)]
+			if !ok {
+				panic("invalid isolation mode")
+			}
+			req.IsolationMode = int(mode)
+
+			flag, e = cmd.Flags().GetString("dc")
+			if e != nil {
+				panic("invalid flag")
+			}
+			dc, _ := decimal.NewFromString(flag)
+			req.DebtCeiling = dc
+
+			return mtg.Encode(clientID, traceID, int(core.ActionTypeProposalUpdateIsolation), req)
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(updateIsolationCmd)
+
+	updateIsolationCmd.Flags().String("s", "", "market symbol")
+	updateIsolationCmd.Flags().String("mode", "", "isolation mode: none, collateral, borrowable")
+	updateIsolationCmd.Flags().String("dc", "", "debt ceiling")
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"compound/core"
+	"compound/core/proposal"
+	"compound/pkg/mtg"
+	"context"
+	"strings"
+
+	"github.com/gofrs/uuid"
+	"github.com/spf13/cobra"
+)
+
+var liquidationModes = map[string]core.LiquidationMode{
+	"incentive": core.LiquidationModeIncentive,
+	"auction":   core.LiquidationModeAuction,
+}
+
+// governing command for market
+var updateLiquidationModeCmd = &cobra.Command{
+	Use:     "update-liquidation-mode",
+	Aliases: []string{"ulm"},
+	Short:   "update market liquidation mode",
+	Long:    "s for symbol, mode for incentive or auction, w for the window in seconds the auction discount reaches the liquidation incentive",
+	Run: func(cmd *cobra.Command, args []string) {
+		buildProposalTransfer(cmd, func(ctx context.Context, clientID, traceID uuid.UUID) ([]byte, error) {
+			req := proposal.UpdateLiquidationModeReq{}
+
+			symbol, e := cmd.Flags().GetString("s")
+			if e != nil || symbol == "" {
+				panic("invalid symbol")
+			}
+			req.Symbol = strings.ToUpper(symbol)
+
+			flag, e := cmd.Flags().GetString("mode")
+			if e != nil {
+				panic("invalid flag")
+			}
+			mode, ok := liquidationModes[strings.ToLower(flag)]
+			if !ok {
+				panic("invalid liquidation mode")
+			}
+			req.Mode = int(mode)
+
+			window, e := cmd.Flags().GetInt64("w")
+			if e != nil || (mode == core.LiquidationModeAuction && window <= 0) {
+				panic("invalid window")
+			}
+			req.Window = window
+
+			return mtg.Encode(clientID, traceID, int(core.ActionTypeProposalUpdateLiquidationMode), req)
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(updateLiquidationModeCmd)
+
+	updateLiquidationModeCmd.Flags().String("s", "", "market symbol")
+	updateLiquidationModeCmd.Flags().String("mode", "", "liquidation mode: incentive, auction")
+	updateLiquidationModeCmd.Flags().Int64("w", 0, "auction window in seconds")
+}
@@ -165,7 +165,7 @@ var updateMarketAdvanceCmd = &cobra.Command{
 	Use:     "update-market-advance",
 	Aliases: []string{"uma"},
 	Short:   "update market advance parameters",
-	Long:    "s for symbol, bc for borrow_cap, clf for close_factor, m for multiplier, jm for jump_multiplier, k for kink",
+	Long:    "s for symbol, bc for borrow_cap, clf for close_factor, m for multiplier, jm for jump_multiplier, k for kink, flf for flash_loan_fee, sc for supply_cap, tw for twap_window, mpa for max_price_age",
 	Run: func(cmd *cobra.Command, args []string) {
 		ctx := cmd.Context()
 		system := provideSystem()
@@ -218,6 +218,32 @@ var updateMarketAdvanceCmd = &cobra.Command{
 		k, _ := decimal.NewFromString(flag)
 		updateMarketReq.Kink = k
 
+		flag, e = cmd.Flags().GetString("flf")
+		if e != nil {
+			panic("invalid flag")
+		}
+		flf, _ := decimal.NewFromString(flag)
+		updateMarketReq.FlashLoanFee = flf
+
+		flag, e = cmd.Flags().GetString("sc")
+		if e != nil {
+			panic("invalid flag")
+		}
+		sc, _ := decimal.NewFromString(flag)
+		updateMarketReq.SupplyCap = sc
+
+		tw, e := cmd.Flags().GetInt64("tw")
+		if e != nil {
+			panic("invalid flag")
+		}
+		updateMarketReq.TwapWindow = tw
+
+		mpa, e := cmd.Flags().GetInt64("mpa")
+		if e != nil {
+			panic("invalid flag")
+		}
+		updateMarketReq.MaxPriceAge = mpa
+
 		memo, err := mtg.Encode(clientID, traceID, int(core.ActionTypeProposalUpdateMarketAdvance), updateMarketReq)
 		if err != nil {
 			panic(err)
@@ -384,6 +410,10 @@ func init() {
 	updateMarketAdvanceCmd.Flags().String("m", "", "multiplier")
 	updateMarketAdvanceCmd.Flags().String("jm", "", "jump multiplier")
 	updateMarketAdvanceCmd.Flags().String("k", "", "kink")
+	updateMarketAdvanceCmd.Flags().String("flf", "-1", "flash loan fee, unchanged if negative")
+	updateMarketAdvanceCmd.Flags().String("sc", "-1", "supply cap, 0 for no cap, unchanged if negative")
+	updateMarketAdvanceCmd.Flags().Int64("tw", -1, "twap window of collateral valuation in seconds, 0 for current price")
+	updateMarketAdvanceCmd.Flags().Int64("mpa", -1, "max price age in seconds, 0 for no limit")
 
 	closeMarketCmd.Flags().String("asset", "", "asset id")
 
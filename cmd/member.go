@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"compound/core"
+	"compound/core/proposal"
+	"compound/pkg/mtg"
+	"context"
+
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+// governing command for adding the member to the multisig group
+var addMemberCmd = &cobra.Command{
+	Use:     "add-member",
+	Aliases: []string{"amb"},
+	Short:   "add the member to the multisig group",
+	Long:    "id for the client id of the member, key for the base64 encoded verify key, w for the price weight, 0 for the default",
+	Run: func(cmd *cobra.Command, args []string) {
+		buildProposalTransfer(cmd, func(ctx context.Context, clientID, traceID uuid.UUID) ([]byte, error) {
+			req := proposal.AddMemberReq{}
+
+			flag, e := cmd.Flags().GetString("id")
+			if e != nil {
+				panic("invalid flag")
+			}
+			if _, e = uuid.FromString(flag); e != nil {
+				panic("invalid client id")
+			}
+			req.ClientID = flag
+
+			flag, e = cmd.Flags().GetString("key")
+			if e != nil {
+				panic("invalid flag")
+			}
+			if _, e = mtg.DecodePublicKey(flag); e != nil {
+				panic("invalid verify key")
+			}
+			req.VerifyKey = flag
+
+			flag, e = cmd.Flags().GetString("w")
+			if e != nil {
+				panic("invalid flag")
+			}
+			weight, e := decimal.NewFromString(flag)
+			if e != nil || weight.LessThan(decimal.Zero) {
+				panic("invalid weight")
+			}
+			req.Weight = weight
+
+			return mtg.Encode(clientID, traceID, int(core.ActionTypeProposalAddMember), req)
+		})
+	},
+}
+
+// governing command for removing the member from the multisig group
+var removeMemberCmd = &cobra.Command{
+	Use:     "remove-member",
+	Aliases: []string{"rmb"},
+	Short:   "remove the member from the multisig group",
+	Long:    "id for the client id of the member",
+	Run: func(cmd *cobra.Command, args []string) {
+		buildProposalTransfer(cmd, func(ctx context.Context, clientID, traceID uuid.UUID) ([]byte, error) {
+			req := proposal.RemoveMemberReq{}
+
+			flag, e := cmd.Flags().GetString("id")
+			if e != nil {
+				panic("invalid flag")
+			}
+			if _, e = uuid.FromString(flag); e != nil {
+				panic("invalid client id")
+			}
+			req.ClientID = flag
+
+			return mtg.Encode(clientID, traceID, int(core.ActionTypeProposalRemoveMember), req)
+		})
+	},
+}
+
+// governing command for updating the threshold of the multisig group
+var updateThresholdCmd = &cobra.Command{
+	Use:     "update-threshold",
+	Aliases: []string{"ut"},
+	Short:   "update the threshold of the multisig group",
+	Long:    "t for the threshold, less than the member count",
+	Run: func(cmd *cobra.Command, args []string) {
+		buildProposalTransfer(cmd, func(ctx context.Context, clientID, traceID uuid.UUID) ([]byte, error) {
+			req := proposal.UpdateThresholdReq{}
+
+			threshold, e := cmd.Flags().GetInt("t")
+			if e != nil || threshold < 1 {
+				panic("invalid threshold")
+			}
+			req.Threshold = threshold
+
+			return mtg.Encode(clientID, traceID, int(core.ActionTypeProposalUpdateThreshold), req)
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(addMemberCmd)
+	addMemberCmd.Flags().String("id", "", "member client id")
+	addMemberCmd.Flags().String("key", "", "member verify key")
+	addMemberCmd.Flags().String("w", "0", "member price weight")
+
+	rootCmd.AddCommand(removeMemberCmd)
+	removeMemberCmd.Flags().String("id", "", "member client id")
+
+	rootCmd.AddCommand(updateThresholdCmd)
+	updateThresholdCmd.Flags().Int("t", 0, "threshold")
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"compound/core"
+	"compound/core/proposal"
+	"compound/pkg/mtg"
+	"context"
+	"strings"
+
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+var priceConsensuses = map[string]core.PriceConsensus{
+	"adjacency":      core.PriceConsensusAdjacency,
+	"trimmedmedian":  core.PriceConsensusTrimmedMedian,
+	"weightedmedian": core.PriceConsensusWeightedMedian,
+}
+
+// governing command for market price
+var updatePriceGuardCmd = &cobra.Command{
+	Use:     "update-price-guard",
+	Aliases: []string{"upg"},
+	Short:   "update the max price change of the market per price block",
+	Long:    "s for symbol, mc for the max price change ratio, 0 for no limit",
+	Run: func(cmd *cobra.Command, args []string) {
+		buildProposalTransfer(cmd, func(ctx context.Context, clientID, traceID uuid.UUID) ([]byte, error) {
+			req := proposal.UpdatePriceGuardReq{}
+
+			symbol, e := cmd.Flags().GetString("s")
+			if e != nil || symbol == "" {
+				panic("invalid symbol")
+			}
+			req.Symbol = strings.ToUpper(symbol)
+
+			flag, e := cmd.Flags().GetString("mc")
+			if e != nil {
+				panic("invalid flag")
+			}
+			maxChange, e := decimal.NewFromString(flag)
+			if e != nil || maxChange.LessThan(decimal.Zero) {
+				panic("invalid max price change")
+			}
+			req.MaxChange = maxChange
+
+			return mtg.Encode(clientID, traceID, int(core.ActionTypeProposalUpdatePriceGuard), req)
+		})
+	},
+}
+
+// governing command for the pending market price
+var confirmPriceCmd = &cobra.Command{
+	Use:     "confirm-price",
+	Aliases: []string{"cp"},
+	Short:   "apply the pending market price held by the price guard",
+	Long:    "s for symbol",
+	Run: func(cmd *cobra.Command, args []string) {
+		buildProposalTransfer(cmd, func(ctx context.Context, clientID, traceID uuid.UUID) ([]byte, error) {
+			req := proposal.ConfirmPriceReq{}
+
+			symbol, e := cmd.Flags().GetString("s")
+			if e != nil || symbol == "" {
+				panic("invalid symbol")
+			}
+			req.Symbol = strings.ToUpper(symbol)
+
+			return mtg.Encode(clientID, traceID, int(core.ActionTypeProposalConfirmPrice), req)
+		})
+	},
+}
+
+// governing command for market price consensus
+var updatePriceConsensusCmd = &cobra.Command{
+	Use:     "update-price-consensus",
+	Aliases: []string{"upc"},
+	Short:   "update the price consensus of the market",
+	Long:    "s for symbol, consensus for adjacency, trimmedmedian or weightedmedian, t for the tolerance, 0 for the default",
+	Run: func(cmd *cobra.Command, args []string) {
+		buildProposalTransfer(cmd, func(ctx context.Context, clientID, traceID uuid.UUID) ([]byte, error) {
+			req := proposal.UpdatePriceConsensusReq{}
+
+			symbol, e := cmd.Flags().GetString("s")
+			if e != nil || symbol == "" {
+				panic("invalid symbol")
+			}
+			req.Symbol = strings.ToUpper(symbol)
+
+			flag, e := cmd.Flags().GetString("consensus")
+			if e != nil {
+				panic("invalid flag")
+			}
+			consensus, ok := priceConsensuses[strings.ToLower(flag)]
+			if !ok {
+				panic("invalid price consensus")
+			}
+			req.Consensus = int(consensus)
+
+			flag, e = cmd.Flags().GetString("t")
+			if e != nil {
+				panic("invalid flag")
+			}
+			tolerance, e := decimal.NewFromString(flag)
+			if e != nil || tolerance.LessThan(decimal.Zero) || tolerance.GreaterThanOrEqual(decimal.NewFromInt(1)) {
+				panic("invalid tolerance")
+			}
+			req.Tolerance = tolerance
+
+			return mtg.Encode(clientID, traceID, int(core.ActionTypeProposalUpdatePriceConsensus), req)
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(updatePriceGuardCmd)
+	updatePriceGuardCmd.Flags().String("s", "", "market symbol")
+	updatePriceGuardCmd.Flags().String("mc", "", "max price change ratio")
+
+	rootCmd.AddCommand(confirmPriceCmd)
+	confirmPriceCmd.Flags().String("s", "", "market symbol")
+
+	rootCmd.AddCommand(updatePriceConsensusCmd)
+	updatePriceConsensusCmd.Flags().String("s", "", "market symbol")
+	updatePriceConsensusCmd.Flags().String("consensus", "", "price consensus: adjacency, trimmedmedian, weightedmedian")
+	updatePriceConsensusCmd.Flags().String("t", "0", "tolerance ratio")
+}
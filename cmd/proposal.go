@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"compound/core"
+	"compound/core/proposal"
+	"compound/pkg/mtg"
+	"context"
+
+	"github.com/gofrs/uuid"
+	"github.com/spf13/cobra"
+)
+
+// governing command for proposal
+var cancelProposalCmd = &cobra.Command{
+	Use:     "cancel-proposal",
+	Aliases: []string{"cpp"},
+	Short:   "cancel the proposal not passed yet, only by the creator",
+	Long:    "id for the trace id of the proposal",
+	Run: func(cmd *cobra.Command, args []string) {
+		buildProposalTransfer(cmd, func(ctx context.Context, clientID, traceID uuid.UUID) ([]byte, error) {
+			flag, e := cmd.Flags().GetString("id")
+			if e != nil {
+				panic("invalid flag")
+			}
+
+			proposalID, e := uuid.FromString(flag)
+			if e != nil {
+				panic("invalid proposal id")
+			}
+
+			return mtg.Encode(clientID, proposalID, int(core.ActionTypeProposalCancel))
+		})
+	},
+}
+
+// governing command for the queued proposal
+var vetoCmd = &cobra.Command{
+	Use:   "veto",
+	Short: "veto the passed proposal queued by the timelock",
+	Long:  "id for the trace id of the queued proposal",
+	Run: func(cmd *cobra.Command, args []string) {
+		buildProposalTransfer(cmd, func(ctx context.Context, clientID, traceID uuid.UUID) ([]byte, error) {
+			req := proposal.VetoReq{}
+
+			flag, e := cmd.Flags().GetString("id")
+			if e != nil {
+				panic("invalid flag")
+			}
+
+			if _, e = uuid.FromString(flag); e != nil {
+				panic("invalid proposal id")
+			}
+			req.Proposal = flag
+
+			return mtg.Encode(clientID, traceID, int(core.ActionTypeProposalVeto), req)
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(cancelProposalCmd)
+	cancelProposalCmd.Flags().String("id", "", "proposal trace id")
+
+	rootCmd.AddCommand(vetoCmd)
+	vetoCmd.Flags().String("id", "", "queued proposal trace id")
+}
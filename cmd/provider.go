@@ -11,26 +11,36 @@ import (
 	operationservice "compound/service/operation"
 	oracle "compound/service/oracle"
 	proposalservice "compound/service/proposal"
+	rewardservice "compound/service/reward"
 	supplyservice "compound/service/supply"
 	walletservice "compound/service/wallet"
+	"compound/store/auction"
 	"compound/store/borrow"
+	"compound/store/category"
+	"compound/store/delegation"
+	"compound/store/flashloan"
+	"compound/store/liquidationauction"
 	"compound/store/market"
+	"compound/store/membership"
 	"compound/store/message"
 	"compound/store/operation"
 	"compound/store/outputarchive"
 	"compound/store/price"
 	"compound/store/proposal"
+	"compound/store/reward"
 	"compound/store/supply"
 	"compound/store/transaction"
 	"compound/store/user"
 	"compound/store/wallet"
 
 	"fmt"
+	"time"
 
 	"github.com/fox-one/mixin-sdk-go"
 	"github.com/fox-one/pkg/property"
 	"github.com/fox-one/pkg/store/db"
 	propertystore "github.com/fox-one/pkg/store/property"
+	"github.com/shopspring/decimal"
 )
 
 // provide db instance
@@ -64,9 +74,15 @@ func provideSystem() *core.System {
 			panic(fmt.Errorf("decode verify key for member %s failed", m.ClientID))
 		}
 
+		weight := m.Weight
+		if weight.LessThanOrEqual(decimal.Zero) {
+			weight = decimal.NewFromInt(1)
+		}
+
 		members = append(members, &core.Member{
 			ClientID:  m.ClientID,
 			VerifyKey: verifyKey,
+			Weight:    weight,
 		})
 	}
 
@@ -80,6 +96,21 @@ func provideSystem() *core.System {
 		panic(fmt.Errorf("base64 decode group sign key failed: %w", err))
 	}
 
+	voteWindow := time.Duration(cfg.Group.Vote.Window) * time.Second
+	if voteWindow <= 0 {
+		voteWindow = 7 * 24 * time.Hour
+	}
+
+	timelocks := make(map[core.ActionType]time.Duration, len(cfg.Group.Timelocks))
+	for name, delay := range cfg.Group.Timelocks {
+		action, ok := core.ParseActionType(name)
+		if !ok || action == core.ActionTypeProposalVeto {
+			panic(fmt.Errorf("invalid timelock action %s", name))
+		}
+
+		timelocks[action] = time.Duration(delay) * time.Second
+	}
+
 	return &core.System{
 		Admins:       cfg.Group.Admins,
 		ClientID:     cfg.Dapp.ClientID,
@@ -88,6 +119,9 @@ func provideSystem() *core.System {
 		Threshold:    cfg.Group.Threshold,
 		VoteAsset:    cfg.Group.Vote.Asset,
 		VoteAmount:   cfg.Group.Vote.Amount,
+		VoteWindow:   voteWindow,
+		Timelocks:    timelocks,
+		RewardAsset:  cfg.Reward.Asset,
 		PrivateKey:   privateKey,
 		SignKey:      signKey,
 		Location:     cfg.Location,
@@ -144,6 +178,34 @@ func provideAllowListStore(db *db.DB) core.IAllowListStore {
 	return operation.NewAllowListStore(db)
 }
 
+func provideFlashLoanStore(db *db.DB) core.IFlashLoanStore {
+	return flashloan.New(db)
+}
+
+func provideCategoryStore(db *db.DB) core.ICategoryStore {
+	return category.New(db)
+}
+
+func provideRewardStore(db *db.DB) core.IRewardStore {
+	return reward.New(db)
+}
+
+func provideDelegationStore(db *db.DB) core.IDelegationStore {
+	return delegation.New(db)
+}
+
+func provideAuctionStore(db *db.DB) core.IAuctionStore {
+	return auction.New(db)
+}
+
+func provideLiquidationAuctionStore(db *db.DB) core.ILiquidationAuctionStore {
+	return liquidationauction.New(db)
+}
+
+func provideMembershipStore(db *db.DB) core.IMembershipStore {
+	return membership.New(db)
+}
+
 // ------------------service------------------------------------
 func provideProposalService(client *mixin.Client, system *core.System, marketStore core.IMarketStore, messageStore core.MessageStore) core.ProposalService {
 	return proposalservice.New(system, client, marketStore, messageStore)
@@ -153,21 +215,26 @@ func provideMessageService(client *mixin.Client) core.MessageService {
 	return messageservice.New(client)
 }
 
-func provideWalletService(client *mixin.Client, cfg walletservice.Config) core.WalletService {
-	return walletservice.New(client, cfg)
+func provideWalletService(client *mixin.Client, cfg walletservice.Config, membershipStr core.IMembershipStore) core.WalletService {
+	return walletservice.New(client, cfg, membershipStr)
 }
 
 func provideBlockService() core.IBlockService {
 	return block.New(&cfg)
 }
 
-func providePriceService(blockSrv core.IBlockService) core.IPriceOracleService {
-	return oracle.New(&cfg, blockSrv)
+func providePriceService(priceStr core.IPriceStore, blockSrv core.IBlockService) core.IPriceOracleService {
+	priceSrv, err := oracle.New(&cfg, priceStr, blockSrv)
+	if err != nil {
+		panic(err)
+	}
+	return priceSrv
 }
 
-func provideMarketService(marketStr core.IMarketStore, blockSrv core.IBlockService) core.IMarketService {
+func provideMarketService(marketStr core.IMarketStore, supplyStr core.ISupplyStore, blockSrv core.IBlockService) core.IMarketService {
 	return marketservice.New(
 		marketStr,
+		supplyStr,
 		blockSrv)
 }
 
@@ -189,11 +256,22 @@ func provideAccountService(
 	marketStore core.IMarketStore,
 	supplyStore core.ISupplyStore,
 	borrowStore core.IBorrowStore,
+	flashLoanStore core.IFlashLoanStore,
+	categoryStore core.ICategoryStore,
 	priceSrv core.IPriceOracleService,
 	blockSrv core.IBlockService,
 	marketSrv core.IMarketService) core.IAccountService {
 
-	return accountservice.New(marketStore, supplyStore, borrowStore, priceSrv, blockSrv, marketSrv)
+	return accountservice.New(marketStore, supplyStore, borrowStore, flashLoanStore, categoryStore, priceSrv, blockSrv, marketSrv)
+}
+
+func provideRewardService(
+	marketStore core.IMarketStore,
+	supplyStore core.ISupplyStore,
+	borrowStore core.IBorrowStore,
+	rewardStore core.IRewardStore) core.IRewardService {
+
+	return rewardservice.New(marketStore, supplyStore, borrowStore, rewardStore)
 }
 
 func provideAllowListService(
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"compound/core"
+	"compound/core/proposal"
+	"compound/pkg/mtg"
+	"context"
+	"strings"
+
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+// governing command for liquidity mining rewards
+var updateRewardSpeedCmd = &cobra.Command{
+	Use:     "update-reward-speed",
+	Aliases: []string{"urs"},
+	Short:   "update market reward speeds",
+	Long:    "s for symbol, ss for supply_speed, bs for borrow_speed, the rewards distributed per block",
+	Run: func(cmd *cobra.Command, args []string) {
+		buildProposalTransfer(cmd, func(ctx context.Context, clientID, traceID uuid.UUID) ([]byte, error) {
+			req := proposal.UpdateRewardSpeedReq{}
+
+			symbol, e := cmd.Flags().GetString("s")
+			if e != nil || symbol == "" {
+				panic("invalid symbol")
+			}
+			req.Symbol = strings.ToUpper(symbol)
+
+			flag, e := cmd.Flags().GetString("ss")
+			if e != nil {
+				panic("invalid flag")
+			}
+			ss, e := decimal.NewFromString(flag)
+			if e != nil || ss.LessThan(decimal.Zero) {
+				panic("invalid supply speed")
+			}
+			req.SupplySpeed = ss
+
+			flag, e = cmd.Flags().GetString("bs")
+			if e != nil {
+				panic("invalid flag")
+			}
+			bs, e := decimal.NewFromString(flag)
+			if e != nil || bs.LessThan(decimal.Zero) {
+				panic("invalid borrow speed")
+			}
+			req.BorrowSpeed = bs
+
+			return mtg.Encode(clientID, traceID, int(core.ActionTypeProposalUpdateRewardSpeed), req)
+		})
+	},
+}
+
+// member command funding the reward pool
+var fundRewardsCmd = &cobra.Command{
+	Use:     "fund-rewards",
+	Aliases: []string{"fr"},
+	Short:   "fund the reward pool paying the claimed rewards",
+	Long:    "amount for the reward asset transferred to the reward pool",
+	Run: func(cmd *cobra.Command, args []string) {
+		system := provideSystem()
+		if system.RewardAsset == "" {
+			panic("reward asset not configured")
+		}
+
+		flag, e := cmd.Flags().GetString("amount")
+		if e != nil {
+			panic("invalid flag")
+		}
+		amount, e := decimal.NewFromString(flag)
+		if e != nil || amount.LessThanOrEqual(decimal.Zero) {
+			panic("invalid amount")
+		}
+
+		buildMemberTransfer(cmd, system.RewardAsset, amount, func(ctx context.Context, clientID, traceID uuid.UUID) ([]byte, error) {
+			return mtg.Encode(clientID, traceID, int(core.ActionTypeProposalFundRewards))
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(updateRewardSpeedCmd)
+	rootCmd.AddCommand(fundRewardsCmd)
+
+	updateRewardSpeedCmd.Flags().String("s", "", "market symbol")
+	updateRewardSpeedCmd.Flags().String("ss", "0", "supply reward speed, rewards per block")
+	updateRewardSpeedCmd.Flags().String("bs", "0", "borrow reward speed, rewards per block")
+
+	fundRewardsCmd.Flags().String("amount", "", "amount of the reward asset")
+}
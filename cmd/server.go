@@ -31,11 +31,19 @@ var serverCmd = &cobra.Command{
 		supplyStore := provideSupplyStore(db)
 		borrowStore := provideBorrowStore(db)
 		transactionStore := provideTransactionStore(db)
+		flashLoanStore := provideFlashLoanStore(db)
+		categoryStore := provideCategoryStore(db)
+		rewardStore := provideRewardStore(db)
+		delegationStore := provideDelegationStore(db)
+		auctionStore := provideAuctionStore(db)
+		priceStore := providePriceStore(db)
+		proposalStore := provideProposalStore(db)
 
 		blockService := provideBlockService()
-		priceService := providePriceService(blockService)
-		marketService := provideMarketService(marketStore, blockService)
-		accountService := provideAccountService(marketStore, supplyStore, borrowStore, priceService, blockService, marketService)
+		priceService := providePriceService(priceStore, blockService)
+		marketService := provideMarketService(marketStore, supplyStore, blockService)
+		accountService := provideAccountService(marketStore, supplyStore, borrowStore, flashLoanStore, categoryStore, priceService, blockService, marketService)
+		rewardService := provideRewardService(marketStore, supplyStore, borrowStore, rewardStore)
 
 		mux := chi.NewMux()
 		mux.Use(middleware.Recoverer)
@@ -52,7 +60,7 @@ var serverCmd = &cobra.Command{
 
 		{
 			//restful api
-			mux.Mount("/api/v1", rest.Handle(userStore, marketStore, supplyStore, borrowStore, transactionStore, blockService, priceService, accountService, marketService))
+			mux.Mount("/api/v1", rest.Handle(userStore, marketStore, supplyStore, borrowStore, transactionStore, blockService, priceService, accountService, marketService, rewardService, delegationStore, auctionStore, proposalStore))
 		}
 
 		port, _ := cmd.Flags().GetInt("port")
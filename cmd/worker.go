@@ -51,17 +51,25 @@ var workerCmd = &cobra.Command{
 		transactionStore := provideTransactionStore(db)
 		outputArchiveStore := provideOutputArchiveStore(db)
 		allowListStore := provideAllowListStore(db)
+		flashLoanStore := provideFlashLoanStore(db)
+		categoryStore := provideCategoryStore(db)
+		rewardStore := provideRewardStore(db)
+		delegationStore := provideDelegationStore(db)
+		auctionStore := provideAuctionStore(db)
+		liquidationAuctionStore := provideLiquidationAuctionStore(db)
+		membershipStore := provideMembershipStore(db)
 
 		walletService := provideWalletService(dapp.Client, walletservice.Config{
 			Pin:       dapp.Pin,
 			Members:   system.MemberIDs(),
 			Threshold: system.Threshold,
-		})
+		}, membershipStore)
 
 		blockService := provideBlockService()
-		priceService := providePriceService(blockService)
-		marketService := provideMarketService(marketStore, blockService)
-		accountService := provideAccountService(marketStore, supplyStore, borrowStore, priceService, blockService, marketService)
+		priceService := providePriceService(priceStore, blockService)
+		marketService := provideMarketService(marketStore, supplyStore, blockService)
+		accountService := provideAccountService(marketStore, supplyStore, borrowStore, flashLoanStore, categoryStore, priceService, blockService, marketService)
+		rewardService := provideRewardService(marketStore, supplyStore, borrowStore, rewardStore)
 		supplyService := provideSupplyService(marketService)
 		borrowService := provideBorrowService(blockService, priceService, accountService)
 		messageService := provideMessageService(dapp.Client)
@@ -86,10 +94,10 @@ var workerCmd = &cobra.Command{
 		}
 
 		workers := []worker.Worker{
-			cashier.New(walletStore, walletService, system),
-			message.New(messageStore, messageService),
+			cashier.New(walletStore, walletService, membershipStore, system),
+			message.New(messageStore, messageService, marketStore, proposalService),
 			priceoracle.New(system, dapp, marketStore, priceStore, priceService),
-			snapshot.NewPayee(db, system, dapp, propertyStore, userStore, outputArchiveStore, walletStore, priceStore, marketStore, supplyStore, borrowStore, proposalStore, transactionStore, proposalService, priceService, blockService, marketService, supplyService, borrowService, accountService, allowListService),
+			snapshot.NewPayee(db, system, dapp, propertyStore, userStore, outputArchiveStore, walletStore, priceStore, marketStore, supplyStore, borrowStore, proposalStore, transactionStore, flashLoanStore, categoryStore, rewardStore, delegationStore, auctionStore, liquidationAuctionStore, membershipStore, proposalService, priceService, blockService, marketService, supplyService, borrowService, accountService, rewardService, allowListService),
 			syncer.New(walletStore, walletService, propertyStore),
 			txsender.New(walletStore),
 			spentsync.New(db, walletStore, transactionStore),
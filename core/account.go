@@ -20,7 +20,19 @@ type Account struct {
 type IAccountService interface {
 	// calculate account liquidity
 	CalculateAccountLiquidity(ctx context.Context, userID string, blockNum int64) (decimal.Decimal, error)
+	// calculate account liquidity for borrowing from the market
+	CalculateBorrowLiquidity(ctx context.Context, userID string, blockNum int64, market *Market) (decimal.Decimal, error)
 	MaxSeize(ctx context.Context, supply *Supply, borrow *Borrow) (decimal.Decimal, error)
 	SeizeTokenAllowed(ctx context.Context, supply *Supply, borrow *Borrow, time time.Time) bool
 	SeizeToken(ctx context.Context, supply *Supply, borrow *Borrow, repayAmount decimal.Decimal) (string, error)
+	// the isolated collateral market pledged by the user, nil if not in isolation mode
+	IsolatedMarket(ctx context.Context, userID string) (*Market, error)
+	// the value of the outstanding borrows of the users pledging the isolated collateral at the current prices
+	IsolatedDebt(ctx context.Context, market *Market) (decimal.Decimal, error)
+	// the e-mode category of the user together with the given markets, nil if not in e-mode
+	EModeCategory(ctx context.Context, userID string, markets ...*Market) (*Category, error)
+	// whether the price of any market in the liquidity of the user is stale at t
+	PriceStale(ctx context.Context, userID string, t time.Time) (bool, error)
+	// the liquidation incentive for seizing the collateral of the user
+	LiquidationIncentive(ctx context.Context, userID string, supplyMarket *Market) (decimal.Decimal, error)
 }
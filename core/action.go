@@ -66,4 +66,81 @@ const (
 	ActionTypeProposalAddAllowList
 	// ActionTypeProposalRemoveAllowList proposal remove from allowlist action
 	ActionTypeProposalRemoveAllowList
+	// ActionTypeFlashLoan flash loan action
+	ActionTypeFlashLoan
+	// ActionTypeFlashLoanRepay flash loan repay action
+	ActionTypeFlashLoanRepay
+	// ActionTypeFlashLoanTransfer flash loan transfer action
+	ActionTypeFlashLoanTransfer
+	// ActionTypeFlashLoanDefault flash loan not returned in time, the collaterals seized
+	ActionTypeFlashLoanDefault
+	// ActionTypeProposalUpdateInterestRateModel proposal switch market interest rate model action
+	ActionTypeProposalUpdateInterestRateModel
+	// ActionTypeProposalUpdateIsolation proposal update market isolation mode action
+	ActionTypeProposalUpdateIsolation
+	// ActionTypeProposalUpdateCategory proposal create or update e-mode category action
+	ActionTypeProposalUpdateCategory
+	// ActionTypeProposalSetMarketCategory proposal set the e-mode category of market action
+	ActionTypeProposalSetMarketCategory
+	// ActionTypeBadDebtWriteOff the debt of the user without collaterals is written off
+	ActionTypeBadDebtWriteOff
+	// ActionTypeClaim claim liquidity mining rewards action
+	ActionTypeClaim
+	// ActionTypeClaimTransfer claim rewards transfer action
+	ActionTypeClaimTransfer
+	// ActionTypeProposalUpdateRewardSpeed proposal update market reward speeds action
+	ActionTypeProposalUpdateRewardSpeed
+	// ActionTypeDelegate grant borrowing allowance to the delegatee action
+	ActionTypeDelegate
+	// ActionTypeSwapCollateral swap the pledged collateral to another collateral action
+	ActionTypeSwapCollateral
+	// ActionTypeProposalStartAuction proposal start reserve auction action
+	ActionTypeProposalStartAuction
+	// ActionTypeAuctionBid bid for the reserve auction action
+	ActionTypeAuctionBid
+	// ActionTypeAuctionSettle the reserve auction is settled to the winner or closed without bids
+	ActionTypeAuctionSettle
+	// ActionTypeAuctionTransfer reserve auction lot and proceeds transfer action
+	ActionTypeAuctionTransfer
+	// ActionTypeAuctionRefundTransfer reserve auction losing bid refund transfer action
+	ActionTypeAuctionRefundTransfer
+	// ActionTypeProposalUpdateLiquidationMode proposal update market liquidation mode action
+	ActionTypeProposalUpdateLiquidationMode
+	// ActionTypeSelfLiquidate repay the borrow with the own collateral action
+	ActionTypeSelfLiquidate
+	// ActionTypeLeverage supply, borrow and re-supply in loops up to the leverage action
+	ActionTypeLeverage
+	// ActionTypeDeleverage repay the borrow with the collateral of the same market action
+	ActionTypeDeleverage
+	// ActionTypeProposalUpdatePriceGuard proposal update market max price change action
+	ActionTypeProposalUpdatePriceGuard
+	// ActionTypeProposalConfirmPrice proposal confirm the pending market price action
+	ActionTypeProposalConfirmPrice
+	// ActionTypeProposalUpdatePriceConsensus proposal update market price consensus action
+	ActionTypeProposalUpdatePriceConsensus
+	// ActionTypeProposalCancel cancel the proposal by the creator action
+	ActionTypeProposalCancel
+	// ActionTypeProposalVeto proposal veto the queued proposal action
+	ActionTypeProposalVeto
+	// ActionTypeProposalAddMember proposal add the member to the multisig group action
+	ActionTypeProposalAddMember
+	// ActionTypeProposalRemoveMember proposal remove the member from the multisig group action
+	ActionTypeProposalRemoveMember
+	// ActionTypeProposalUpdateThreshold proposal update the threshold of the multisig group action
+	ActionTypeProposalUpdateThreshold
+	// ActionTypeProposalBatch proposal execute the proposal requests atomically action
+	ActionTypeProposalBatch
+	// ActionTypeProposalFundRewards member fund the reward pool with the reward asset carried action
+	ActionTypeProposalFundRewards
 )
+
+// ParseActionType parse the action type by the name without the ActionType prefix
+func ParseActionType(name string) (ActionType, bool) {
+	for i := 0; i < len(_ActionType_index)-1; i++ {
+		if a := ActionType(i); a.String() == name {
+			return a, true
+		}
+	}
+
+	return ActionTypeDefault, false
+}
@@ -38,11 +38,44 @@ func _() {
 	_ = x[ActionTypeProposalRemoveScope-27]
 	_ = x[ActionTypeProposalAddAllowList-28]
 	_ = x[ActionTypeProposalRemoveAllowList-29]
+	_ = x[ActionTypeFlashLoan-30]
+	_ = x[ActionTypeFlashLoanRepay-31]
+	_ = x[ActionTypeFlashLoanTransfer-32]
+	_ = x[ActionTypeFlashLoanDefault-33]
+	_ = x[ActionTypeProposalUpdateInterestRateModel-34]
+	_ = x[ActionTypeProposalUpdateIsolation-35]
+	_ = x[ActionTypeProposalUpdateCategory-36]
+	_ = x[ActionTypeProposalSetMarketCategory-37]
+	_ = x[ActionTypeBadDebtWriteOff-38]
+	_ = x[ActionTypeClaim-39]
+	_ = x[ActionTypeClaimTransfer-40]
+	_ = x[ActionTypeProposalUpdateRewardSpeed-41]
+	_ = x[ActionTypeDelegate-42]
+	_ = x[ActionTypeSwapCollateral-43]
+	_ = x[ActionTypeProposalStartAuction-44]
+	_ = x[ActionTypeAuctionBid-45]
+	_ = x[ActionTypeAuctionSettle-46]
+	_ = x[ActionTypeAuctionTransfer-47]
+	_ = x[ActionTypeAuctionRefundTransfer-48]
+	_ = x[ActionTypeProposalUpdateLiquidationMode-49]
+	_ = x[ActionTypeSelfLiquidate-50]
+	_ = x[ActionTypeLeverage-51]
+	_ = x[ActionTypeDeleverage-52]
+	_ = x[ActionTypeProposalUpdatePriceGuard-53]
+	_ = x[ActionTypeProposalConfirmPrice-54]
+	_ = x[ActionTypeProposalUpdatePriceConsensus-55]
+	_ = x[ActionTypeProposalCancel-56]
+	_ = x[ActionTypeProposalVeto-57]
+	_ = x[ActionTypeProposalAddMember-58]
+	_ = x[ActionTypeProposalRemoveMember-59]
+	_ = x[ActionTypeProposalUpdateThreshold-60]
+	_ = x[ActionTypeProposalBatch-61]
+	_ = x[ActionTypeProposalFundRewards-62]
 }
 
-const _ActionType_name = "DefaultSupplyBorrowRedeemRepayMintPledgeUnpledgeLiquidateRedeemTransferUnpledgeTransferBorrowTransferLiquidateTransferRefundTransferRepayRefundTransferLiquidateRefundTransferProposalAddMarketProposalUpdateMarketProposalWithdrawReservesProposalProvidePriceProposalVoteProposalInjectCTokenForMintProposalUpdateMarketAdvanceProposalTransferProposalCloseMarketProposalOpenMarketProposalAddScopeProposalRemoveScopeProposalAddAllowListProposalRemoveAllowList"
+const _ActionType_name = "DefaultSupplyBorrowRedeemRepayMintPledgeUnpledgeLiquidateRedeemTransferUnpledgeTransferBorrowTransferLiquidateTransferRefundTransferRepayRefundTransferLiquidateRefundTransferProposalAddMarketProposalUpdateMarketProposalWithdrawReservesProposalProvidePriceProposalVoteProposalInjectCTokenForMintProposalUpdateMarketAdvanceProposalTransferProposalCloseMarketProposalOpenMarketProposalAddScopeProposalRemoveScopeProposalAddAllowListProposalRemoveAllowListFlashLoanFlashLoanRepayFlashLoanTransferFlashLoanDefaultProposalUpdateInterestRateModelProposalUpdateIsolationProposalUpdateCategoryProposalSetMarketCategoryBadDebtWriteOffClaimClaimTransferProposalUpdateRewardSpeedDelegateSwapCollateralProposalStartAuctionAuctionBidAuctionSettleAuctionTransferAuctionRefundTransferProposalUpdateLiquidationModeSelfLiquidateLeverageDeleverageProposalUpdatePriceGuardProposalConfirmPriceProposalUpdatePriceConsensusProposalCancelProposalVetoProposalAddMemberProposalRemoveMemberProposalUpdateThresholdProposalBatchProposalFundRewards"
 
-var _ActionType_index = [...]uint16{0, 7, 13, 19, 25, 30, 34, 40, 48, 57, 71, 87, 101, 118, 132, 151, 174, 191, 211, 235, 255, 267, 294, 321, 337, 356, 374, 390, 409, 429, 452}
+var _ActionType_index = [...]uint16{0, 7, 13, 19, 25, 30, 34, 40, 48, 57, 71, 87, 101, 118, 132, 151, 174, 191, 211, 235, 255, 267, 294, 321, 337, 356, 374, 390, 409, 429, 452, 461, 475, 492, 508, 539, 562, 584, 609, 624, 629, 642, 667, 675, 689, 709, 719, 732, 747, 768, 797, 810, 818, 828, 852, 872, 900, 914, 926, 943, 963, 986, 999, 1018}
 
 func (i ActionType) String() string {
 	if i < 0 || i >= ActionType(len(_ActionType_index)-1) {
@@ -0,0 +1,98 @@
+package core
+
+import (
+	"context"
+	"time"
+
+	"github.com/fox-one/pkg/store/db"
+	"github.com/shopspring/decimal"
+)
+
+// AuctionType reserve auction type
+type AuctionType int
+
+const (
+	_ AuctionType = iota
+	// AuctionTypeEnglish the open ascending auction, the highest bid wins when the auction ends
+	AuctionTypeEnglish
+	// AuctionTypeDutch the first bid reaching the declining asking price wins
+	AuctionTypeDutch
+)
+
+// AuctionStatus reserve auction status
+type AuctionStatus int
+
+const (
+	_ AuctionStatus = iota
+	// AuctionStatusOpen accepting bids
+	AuctionStatusOpen
+	// AuctionStatusSettled the lot is sold to the winner
+	AuctionStatusSettled
+	// AuctionStatusExpired ended without bids, the lot is returned to the market reserves
+	AuctionStatusExpired
+)
+
+// Auction reserve auction info, the reserves of the market are sold as a lot for the bid asset,
+// the prices are the bid asset amounts for the whole lot
+type Auction struct {
+	ID         uint64          `sql:"PRIMARY_KEY;AUTO_INCREMENT" json:"id"`
+	TraceID    string          `sql:"size:36;unique_index:auction_trace_idx" json:"trace_id"`
+	Type       AuctionType     `sql:"default:1" json:"type"`
+	AssetID    string          `sql:"size:36" json:"asset_id"`
+	Amount     decimal.Decimal `sql:"type:decimal(32,16)" json:"amount"`
+	BidAssetID string          `sql:"size:36" json:"bid_asset_id"`
+	// the receiver of the proceeds
+	Opponent string `sql:"size:36" json:"opponent"`
+	// the asking price when the dutch auction starts
+	StartPrice decimal.Decimal `sql:"type:decimal(32,16)" json:"start_price"`
+	// the floor price of the dutch auction or the minimum bid of the english auction
+	EndPrice   decimal.Decimal `sql:"type:decimal(32,16)" json:"end_price"`
+	Status     AuctionStatus   `sql:"default:1" json:"status"`
+	// the highest bidder and bid of the open english auction, or the winner of the settled auction
+	Winner     string          `sql:"size:36" json:"-"`
+	WinningBid decimal.Decimal `sql:"type:decimal(32,16)" json:"winning_bid"`
+	StartAt    time.Time       `json:"start_at"`
+	EndAt      time.Time       `json:"end_at"`
+	Version    int64           `sql:"default:0" json:"version"`
+	CreatedAt  time.Time       `sql:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt  time.Time       `sql:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// AuctionBidStatus auction bid status
+type AuctionBidStatus int
+
+const (
+	_ AuctionBidStatus = iota
+	// AuctionBidStatusPending the highest bid waiting for the english auction to end
+	AuctionBidStatusPending
+	// AuctionBidStatusWon the bid won the lot
+	AuctionBidStatusWon
+	// AuctionBidStatusRefunded the bid was outbid and refunded
+	AuctionBidStatusRefunded
+)
+
+// AuctionBid the bid of the english auction, held until it is outbid or the auction ends
+type AuctionBid struct {
+	ID        uint64           `sql:"PRIMARY_KEY;AUTO_INCREMENT" json:"id"`
+	TraceID   string           `sql:"size:36;unique_index:auction_bid_trace_idx" json:"trace_id"`
+	AuctionID string           `sql:"size:36;index:auction_bid_auction_idx" json:"auction_id"`
+	UserID    string           `sql:"size:36" json:"-"`
+	FollowID  string           `sql:"size:36" json:"follow_id"`
+	Amount    decimal.Decimal  `sql:"type:decimal(32,16)" json:"amount"`
+	Status    AuctionBidStatus `sql:"default:1" json:"status"`
+	Version   int64            `sql:"default:0" json:"version"`
+	CreatedAt time.Time        `sql:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt time.Time        `sql:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// IAuctionStore auction store interface
+type IAuctionStore interface {
+	Save(ctx context.Context, tx *db.DB, auction *Auction) error
+	Find(ctx context.Context, traceID string) (*Auction, bool, error)
+	All(ctx context.Context) ([]*Auction, error)
+	ListExpired(ctx context.Context, t time.Time) ([]*Auction, error)
+	Update(ctx context.Context, tx *db.DB, auction *Auction) error
+	SaveBid(ctx context.Context, tx *db.DB, bid *AuctionBid) error
+	ListBids(ctx context.Context, auctionID string) ([]*AuctionBid, error)
+	UpdateBid(ctx context.Context, tx *db.DB, bid *AuctionBid) error
+}
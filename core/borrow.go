@@ -16,9 +16,32 @@ type Borrow struct {
 	AssetID       string          `sql:"size:36;unique_index:borrow_idx" json:"asset_id"`
 	Principal     decimal.Decimal `sql:"type:decimal(32,16)" json:"principal"`
 	InterestIndex decimal.Decimal `sql:"type:decimal(32,16);default:1" json:"interest_index"`
-	Version       int64           `sql:"default:0" json:"version"`
-	CreatedAt     time.Time       `sql:"default:CURRENT_TIMESTAMP" json:"created_at"`
-	UpdatedAt     time.Time       `sql:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+	// 利率模式, 未设置时为浮动利率
+	RateMode BorrowRateMode `sql:"default:0" json:"rate_mode"`
+	// 借款时锁定的稳定利率 per block
+	StableRate decimal.Decimal `sql:"type:decimal(32,16);default:0" json:"stable_rate"`
+	// 稳定利率开始计息的区块高度
+	StableRateBlock int64     `sql:"default:0" json:"stable_rate_block"`
+	Version         int64     `sql:"default:0" json:"version"`
+	CreatedAt       time.Time `sql:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt       time.Time `sql:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// BorrowRateMode borrow rate mode
+type BorrowRateMode int
+
+const (
+	_ BorrowRateMode = iota
+	// BorrowRateModeVariable the borrow interest follows the market borrow index
+	BorrowRateModeVariable
+	// BorrowRateModeStable the borrow rate is locked at borrow time
+	BorrowRateModeStable
+)
+
+// IsValid is valid rate mode
+func (m BorrowRateMode) IsValid() bool {
+	return m == BorrowRateModeVariable ||
+		m == BorrowRateModeStable
 }
 
 var (
@@ -28,9 +51,25 @@ var (
 	ErrBorrowsOverCap = errors.New("borrows over market borrow cap")
 )
 
+// IsStable is stable rate borrow
+func (b *Borrow) IsStable() bool {
+	return b.RateMode == BorrowRateModeStable
+}
+
 // Balance caculate borrow balance
 // balance = borrow.principal * market.borrow_index / borrow.interest_index
+// stable balance = borrow.principal * (1 + borrow.stable_rate * (market.block_number - borrow.stable_rate_block))
 func (b *Borrow) Balance(ctx context.Context, market *Market) (decimal.Decimal, error) {
+	if b.IsStable() {
+		blockDelta := market.BlockNumber - b.StableRateBlock
+		if blockDelta <= 0 {
+			return b.Principal, nil
+		}
+
+		interest := b.Principal.Mul(b.StableRate).Mul(decimal.NewFromInt(blockDelta))
+		return b.Principal.Add(interest), nil
+	}
+
 	if market.BorrowIndex.LessThanOrEqual(decimal.Zero) {
 		market.BorrowIndex = market.BorrowRatePerBlock
 	}
@@ -55,10 +94,13 @@ type IBorrowStore interface {
 	Update(ctx context.Context, tx *db.DB, borrow *Borrow) error
 	All(ctx context.Context) ([]*Borrow, error)
 	Users(ctx context.Context) ([]string, error)
+	// ListStableBelow list the outstanding stable borrows of the asset with the stable rate lower than the rate, the lowest first
+	ListStableBelow(ctx context.Context, assetID string, rate decimal.Decimal, limit int) ([]*Borrow, error)
 }
 
 // IBorrowService supply service interface
 type IBorrowService interface {
 	BorrowAllowed(ctx context.Context, borrowAmount decimal.Decimal, userID string, market *Market, time time.Time) bool
+	DelegatedBorrowAllowed(ctx context.Context, borrowAmount decimal.Decimal, delegator, delegatee string, market *Market, time time.Time) bool
 	BorrowBalance(ctx context.Context, borrow *Borrow, market *Market) (decimal.Decimal, error)
 }
@@ -0,0 +1,33 @@
+package core
+
+import (
+	"context"
+	"time"
+
+	"github.com/fox-one/pkg/store/db"
+	"github.com/shopspring/decimal"
+)
+
+// Category e-mode category of the correlated markets, such as USD stable coins or BTC wrappers
+//
+// when the supplies and borrows of the user all sit in one category,
+// the collateral factor and liquidation incentive of the category are used instead of the market ones
+type Category struct {
+	ID   uint64 `sql:"PRIMARY_KEY;AUTO_INCREMENT" json:"id"`
+	Name string `sql:"size:20;unique_index:category_name_idx" json:"name"`
+	// 类别抵押因子, 一般高于市场抵押因子
+	CollateralFactor decimal.Decimal `sql:"type:decimal(32,16)" json:"collateral_factor"`
+	// 类别清算激励因子, 一般低于市场清算激励因子
+	LiquidationIncentive decimal.Decimal `sql:"type:decimal(32,16)" json:"liquidation_incentive"`
+	Version              int64           `sql:"default:0" json:"version"`
+	CreatedAt            time.Time       `sql:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt            time.Time       `sql:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// ICategoryStore e-mode category store interface
+type ICategoryStore interface {
+	Save(ctx context.Context, tx *db.DB, category *Category) error
+	Find(ctx context.Context, name string) (*Category, bool, error)
+	All(ctx context.Context) ([]*Category, error)
+	Update(ctx context.Context, tx *db.DB, category *Category) error
+}
@@ -14,6 +14,7 @@ type Config struct {
 	Dapp        Dapp        `json:"dapp"`
 	Group       Group       `json:"group"`
 	PriceOracle PriceOracle `json:"price_oracle"`
+	Reward      RewardConf  `json:"reward"`
 }
 
 // IsAdmin check if the user is admin or not
@@ -39,18 +40,24 @@ type Group struct {
 	Threshold  uint8        `json:"threshold"`
 	Members    []MemberConf `json:"members"`
 	Vote       Vote         `json:"vote"`
+	// the delay in seconds before the passed proposals executed, keyed by the action type name, such as ProposalUpdateMarket
+	Timelocks map[string]int64 `json:"timelocks"`
 }
 
 // MemberConf member info
 type MemberConf struct {
 	ClientID  string `json:"client_id"`
 	VerifyKey string `json:"verify_key"`
+	// the weight of the price provided by the member, 1 if not set
+	Weight decimal.Decimal `json:"weight"`
 }
 
 // Vote vote config info
 type Vote struct {
 	Asset  string          `json:"asset"`
 	Amount decimal.Decimal `json:"amount"`
+	// the voting window of the proposals in seconds, 7 days if not set
+	Window int64 `json:"window"`
 }
 
 // Dapp mixin dapp config
@@ -60,7 +67,33 @@ type Dapp struct {
 	Pin          string `json:"pin"`
 }
 
+// RewardConf liquidity mining reward config
+type RewardConf struct {
+	// the asset paid as rewards, should be injected to the multi-sign wallet
+	Asset string `json:"asset"`
+}
+
 // PriceOracle price oracle config
 type PriceOracle struct {
 	EndPoint string `json:"end_point"`
+	// the sources aggregated by median together with the end point
+	Sources []PriceSourceConf `json:"sources"`
+	// the prices deviating from the median more than the ratio are dropped, not dropped if zero
+	MaxDeviation decimal.Decimal `json:"max_deviation"`
+	// the prices older than the max age in seconds are dropped, not dropped if zero
+	MaxAge int64 `json:"max_age"`
+}
+
+// PriceSourceConf price source config
+type PriceSourceConf struct {
+	Name string `json:"name"`
+	// rest, file or csv
+	Type string `json:"type"`
+	// the url of the rest source, {asset_id} and {symbol} are replaced with the market,
+	// the path of the file source, the path or url of the csv source
+	URL string `json:"url"`
+	// the dot separated path of the price in the json response of the rest source
+	PriceField string `json:"price_field"`
+	// the dot separated path of the unix timestamp in the json response of the rest source, the pull time if empty
+	TimeField string `json:"time_field"`
 }
@@ -0,0 +1,32 @@
+package core
+
+import (
+	"context"
+	"time"
+
+	"github.com/fox-one/pkg/store/db"
+	"github.com/shopspring/decimal"
+)
+
+// Delegation the borrowing allowance granted by the delegator to the delegatee,
+// the delegatee borrows against the collaterals of the delegator, and the debt is recorded to the delegator
+type Delegation struct {
+	ID        uint64 `sql:"PRIMARY_KEY;AUTO_INCREMENT" json:"id"`
+	Delegator string `sql:"size:36;unique_index:delegation_idx" json:"-"`
+	Delegatee string `sql:"size:36;unique_index:delegation_idx;index:delegation_delegatee_idx" json:"-"`
+	AssetID   string `sql:"size:36;unique_index:delegation_idx" json:"asset_id"`
+	// 剩余可借额度
+	Allowance decimal.Decimal `sql:"type:decimal(32,16)" json:"allowance"`
+	Version   int64           `sql:"default:0" json:"version"`
+	CreatedAt time.Time       `sql:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt time.Time       `sql:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// IDelegationStore delegation store interface
+type IDelegationStore interface {
+	Save(ctx context.Context, tx *db.DB, delegation *Delegation) error
+	Find(ctx context.Context, delegator, delegatee, assetID string) (*Delegation, bool, error)
+	FindByDelegator(ctx context.Context, delegator string) ([]*Delegation, error)
+	FindByDelegatee(ctx context.Context, delegatee string) ([]*Delegation, error)
+	Update(ctx context.Context, tx *db.DB, delegation *Delegation) error
+}
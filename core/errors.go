@@ -37,6 +37,30 @@ const (
 	ErrPledgeNotAllowed ErrorCode = 100110
 	// ErrMarketClosed market closed
 	ErrMarketClosed ErrorCode = 100111
+	// ErrFlashLoanNotFound no pending flash loan
+	ErrFlashLoanNotFound ErrorCode = 100112
+	// ErrBorrowRateModeMismatch borrow rate mode is different from the existing borrow
+	ErrBorrowRateModeMismatch ErrorCode = 100113
+	// ErrSupplyOverCap supplies over market supply cap
+	ErrSupplyOverCap ErrorCode = 100114
+	// ErrIsolationModeViolated isolated collateral can not be pledged with other collaterals
+	ErrIsolationModeViolated ErrorCode = 100115
+	// ErrNoRewards no rewards to claim
+	ErrNoRewards ErrorCode = 100116
+	// ErrInsufficientAllowance borrow over the allowance granted by the delegator
+	ErrInsufficientAllowance ErrorCode = 100117
+	// ErrInsufficientReserves insufficient market reserves
+	ErrInsufficientReserves ErrorCode = 100118
+	// ErrAuctionNotFound auction not found
+	ErrAuctionNotFound ErrorCode = 100119
+	// ErrAuctionClosed auction closed
+	ErrAuctionClosed ErrorCode = 100120
+	// ErrBidTooLow bid lower than the asking price
+	ErrBidTooLow ErrorCode = 100121
+	// ErrPriceStale market price older than the max price age
+	ErrPriceStale ErrorCode = 100122
+	// ErrInsufficientRewardPool rewards claimed over the reward pool balance
+	ErrInsufficientRewardPool ErrorCode = 100123
 )
 
 func (e ErrorCode) String() string {
@@ -0,0 +1,58 @@
+package core
+
+import (
+	"context"
+	"time"
+
+	"github.com/fox-one/pkg/store/db"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// FlashLoanWindow the flash loan should be returned within the window, or the pledged collaterals will be seized
+	FlashLoanWindow = 10 * time.Minute
+)
+
+// FlashLoan flash loan info
+type FlashLoan struct {
+	ID       uint64          `sql:"PRIMARY_KEY;AUTO_INCREMENT" json:"id"`
+	TraceID  string          `sql:"size:36;unique_index:flash_loan_trace_idx" json:"trace_id"`
+	UserID   string          `sql:"size:36;index:flash_loan_user_idx" json:"-"`
+	FollowID string          `sql:"size:36" json:"follow_id"`
+	AssetID  string          `sql:"size:36" json:"asset_id"`
+	Amount   decimal.Decimal `sql:"type:decimal(32,16)" json:"amount"`
+	// fee = amount * market.flash_loan_fee
+	Fee       decimal.Decimal `sql:"type:decimal(32,16)" json:"fee"`
+	Status    FlashLoanStatus `sql:"default:1" json:"status"`
+	ExpiredAt time.Time       `json:"expired_at"`
+	Version   int64           `sql:"default:0" json:"version"`
+	CreatedAt time.Time       `sql:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt time.Time       `sql:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// FlashLoanStatus flash loan status
+type FlashLoanStatus int
+
+const (
+	_ FlashLoanStatus = iota
+	// FlashLoanStatusPending waiting for repayment
+	FlashLoanStatusPending
+	// FlashLoanStatusRepaid repaid with fee in time
+	FlashLoanStatusRepaid
+	// FlashLoanStatusDefaulted not repaid in time, the collaterals seized
+	FlashLoanStatusDefaulted
+)
+
+// Due the amount to return, principal plus fee
+func (l *FlashLoan) Due() decimal.Decimal {
+	return l.Amount.Add(l.Fee)
+}
+
+// IFlashLoanStore flash loan store interface
+type IFlashLoanStore interface {
+	Save(ctx context.Context, tx *db.DB, loan *FlashLoan) error
+	FindPending(ctx context.Context, userID, assetID string) (*FlashLoan, bool, error)
+	FindPendingByUser(ctx context.Context, userID string) ([]*FlashLoan, error)
+	ListExpired(ctx context.Context, t time.Time) ([]*FlashLoan, error)
+	Update(ctx context.Context, tx *db.DB, loan *FlashLoan) error
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 
 	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
 )
 
 // Member member
@@ -13,6 +14,8 @@ type Member struct {
 	ClientID  string
 	Name      string
 	VerifyKey ed25519.PublicKey
+	// the weight of the price provided by the member in the weighted median price consensus
+	Weight decimal.Decimal
 }
 
 // DecodeUserTransactionAction decode user transaction
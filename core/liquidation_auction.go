@@ -0,0 +1,41 @@
+package core
+
+import (
+	"context"
+	"time"
+
+	"github.com/fox-one/pkg/store/db"
+)
+
+// LiquidationAuction the liquidation auction of the underwater position,
+// opened when the account liquidity of the user drops below zero and closed when it recovers
+type LiquidationAuction struct {
+	ID      uint64                   `sql:"PRIMARY_KEY;AUTO_INCREMENT" json:"id"`
+	TraceID string                   `sql:"size:36;unique_index:liquidation_auction_trace_idx" json:"trace_id"`
+	UserID  string                   `sql:"size:36;index:liquidation_auction_user_idx" json:"-"`
+	Status  LiquidationAuctionStatus `sql:"default:1" json:"status"`
+	// the time the position became liquidatable
+	StartAt   time.Time `json:"start_at"`
+	Version   int64     `sql:"default:0" json:"version"`
+	CreatedAt time.Time `sql:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt time.Time `sql:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// LiquidationAuctionStatus liquidation auction status
+type LiquidationAuctionStatus int
+
+const (
+	_ LiquidationAuctionStatus = iota
+	// LiquidationAuctionStatusOpen the position is liquidatable
+	LiquidationAuctionStatusOpen
+	// LiquidationAuctionStatusClosed the position is healthy again
+	LiquidationAuctionStatusClosed
+)
+
+// ILiquidationAuctionStore liquidation auction store interface
+type ILiquidationAuctionStore interface {
+	Save(ctx context.Context, tx *db.DB, auction *LiquidationAuction) error
+	FindOpen(ctx context.Context, userID string) (*LiquidationAuction, bool, error)
+	ListOpen(ctx context.Context) ([]*LiquidationAuction, error)
+	Update(ctx context.Context, tx *db.DB, auction *LiquidationAuction) error
+}
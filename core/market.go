@@ -16,6 +16,12 @@ type Market struct {
 	CTokenAssetID string          `sql:"size:36;unique_index:ctoken_asset_idx" json:"ctoken_asset_id"`
 	TotalCash     decimal.Decimal `sql:"type:decimal(32,16)" json:"total_cash"`
 	TotalBorrows  decimal.Decimal `sql:"type:decimal(32,16)" json:"total_borrows"`
+	// 稳定利率借款总额, 包含在 TotalBorrows 中
+	TotalStableBorrows decimal.Decimal `sql:"type:decimal(32,16);default:0" json:"total_stable_borrows"`
+	// 稳定利率借款的加权平均利率 per block
+	AvgStableRate decimal.Decimal `sql:"type:decimal(32,16);default:0" json:"avg_stable_rate"`
+	// 未归还的闪电贷本金, 不计入借款也不计息, 在资金利用率和兑换率中视为现金
+	FlashLoans decimal.Decimal `sql:"type:decimal(32,16);default:0" json:"flash_loans"`
 	// 保留金
 	Reserves decimal.Decimal `sql:"type:decimal(32,16)" json:"reserves"`
 	// CToken 累计铸造出来的币的数量
@@ -28,6 +34,8 @@ type Market struct {
 	LiquidationIncentive decimal.Decimal `sql:"type:decimal(32,16)" json:"liquidation_incentive"`
 	// 资金池的最小资金量
 	BorrowCap decimal.Decimal `sql:"type:decimal(32,16);default:0" json:"borrow_cap"`
+	// 市场最大存款量, 0 为不限制
+	SupplyCap decimal.Decimal `sql:"type:decimal(32,16);default:0" json:"supply_cap"`
 	//抵押因子 = 可借贷价值 / 抵押资产价值，目前compound设置为0.75. 稳定币(USDT)的抵押率是0,即不可抵押
 	CollateralFactor decimal.Decimal `sql:"type:decimal(32,16)" json:"collateral_factor"`
 	//触发清算因子 [0.05, 0.9] 清算人最大可清算的资产比例
@@ -40,6 +48,44 @@ type Market struct {
 	JumpMultiplier decimal.Decimal `sql:"type:decimal(32,16)" json:"jump_multiplier"`
 	// Kink
 	Kink decimal.Decimal `sql:"type:decimal(32,16)" json:"kink"`
+	// 利率模型, 未设置时为 jump rate
+	InterestRateModel InterestRateModelType `sql:"default:0" json:"interest_rate_model"`
+	// The second kink of dual kink model
+	SecondKink decimal.Decimal `sql:"type:decimal(32,16);default:0" json:"second_kink"`
+	// The multiplierPerBlock after hitting the second kink of dual kink model. per year
+	SecondJumpMultiplier decimal.Decimal `sql:"type:decimal(32,16);default:0" json:"second_jump_multiplier"`
+	// 隔离模式, 抵押隔离资产的用户不能再抵押其他资产, 只能借出隔离模式下可借的资产
+	IsolationMode IsolationMode `sql:"default:0" json:"isolation_mode"`
+	// 隔离抵押资产的债务上限 (价值)
+	DebtCeiling decimal.Decimal `sql:"type:decimal(32,16);default:0" json:"debt_ceiling"`
+	// e-mode 类别名称, 为空时不属于任何类别
+	Category string `sql:"size:20;default:''" json:"category"`
+	// 闪电贷手续费率 [0, 1)
+	FlashLoanFee decimal.Decimal `sql:"type:decimal(32,16);default:0" json:"flash_loan_fee"`
+	// 抵押奖励速度, 每个区块分配给抵押用户的奖励数量
+	SupplyRewardSpeed decimal.Decimal `sql:"type:decimal(32,16);default:0" json:"supply_reward_speed"`
+	// 借款奖励速度, 每个区块分配给借款用户的奖励数量
+	BorrowRewardSpeed decimal.Decimal `sql:"type:decimal(32,16);default:0" json:"borrow_reward_speed"`
+	// 抵押奖励指数, 每个抵押的 ctoken 累计分配的奖励
+	SupplyRewardIndex decimal.Decimal `sql:"type:decimal(32,16);default:0" json:"supply_reward_index"`
+	// 借款奖励指数, 每单位借款 (principal / borrow_index) 累计分配的奖励
+	BorrowRewardIndex decimal.Decimal `sql:"type:decimal(32,16);default:0" json:"borrow_reward_index"`
+	// 清算模式, 固定清算折扣或者荷兰式拍卖
+	LiquidationMode LiquidationMode `sql:"default:1" json:"liquidation_mode"`
+	// 拍卖清算窗口 (秒), 清算折扣从 0 线性增长, 经过窗口后达到清算激励
+	LiquidationAuctionWindow int64 `sql:"default:0" json:"liquidation_auction_window"`
+	// 抵押估值的 TWAP 窗口 (秒), 为 0 时使用当前价格
+	TwapWindow int64 `sql:"default:0" json:"twap_window"`
+	// 价格最长有效期 (秒), 超过后暂停借款, 解除抵押, 赎回, 清算, 抵押品兑换, 自我清算, 杠杆和闪电贷, 为 0 时不限制
+	MaxPriceAge int64 `sql:"default:0" json:"max_price_age"`
+	// 每个价格区块的最大价格变化比例, 超过后价格被挂起等待确认, 为 0 时不限制
+	MaxPriceChange decimal.Decimal `sql:"type:decimal(32,16);default:0" json:"max_price_change"`
+	// 价格共识算法, 未设置时为相邻价格过滤后取平均
+	PriceConsensus PriceConsensus `sql:"default:1" json:"price_consensus"`
+	// 价格共识的容差, 为 0 时使用算法的默认值
+	PriceTolerance decimal.Decimal `sql:"type:decimal(32,16);default:0" json:"price_tolerance"`
+	// 价格共识版本, 每次修改价格共识后递增
+	PriceConsensusVersion int64 `sql:"default:0" json:"price_consensus_version"`
 	//当前区块高度
 	BlockNumber        int64           `json:"block_number"`
 	UtilizationRate    decimal.Decimal `sql:"type:decimal(32,16)" json:"utilization_rate"`
@@ -72,6 +118,85 @@ func (s MarketStatus) IsValid() bool {
 		s == MarketStatusOpen
 }
 
+//go:generate stringer -type InterestRateModelType -trimprefix InterestRateModel
+
+// InterestRateModelType interest rate model type
+type InterestRateModelType int
+
+const (
+	_ InterestRateModelType = iota
+	// InterestRateModelJumpRate jump rate model, the default model
+	InterestRateModelJumpRate
+	// InterestRateModelLinear linear model
+	InterestRateModelLinear
+	// InterestRateModelFixed fixed rate model
+	InterestRateModelFixed
+	// InterestRateModelDualKink dual kink model for stable coins
+	InterestRateModelDualKink
+)
+
+// IsValid is valid model type
+func (m InterestRateModelType) IsValid() bool {
+	return m >= InterestRateModelJumpRate &&
+		m <= InterestRateModelDualKink
+}
+
+// IsolationMode market isolation mode
+type IsolationMode int
+
+const (
+	_ IsolationMode = iota
+	// IsolationModeNone normal market
+	IsolationModeNone
+	// IsolationModeCollateral isolated collateral, can not be pledged with other collaterals
+	IsolationModeCollateral
+	// IsolationModeBorrowable borrowable by the users pledging isolated collaterals
+	IsolationModeBorrowable
+)
+
+// IsValid is valid isolation mode
+func (m IsolationMode) IsValid() bool {
+	return m == IsolationModeNone ||
+		m == IsolationModeCollateral ||
+		m == IsolationModeBorrowable
+}
+
+// IsIsolated is isolated collateral
+func (m *Market) IsIsolated() bool {
+	return m.IsolationMode == IsolationModeCollateral
+}
+
+// LiquidationMode liquidation mode of the collateral market
+type LiquidationMode int
+
+const (
+	_ LiquidationMode = iota
+	// LiquidationModeIncentive the collateral is seized at the fixed liquidation incentive discount
+	LiquidationModeIncentive
+	// LiquidationModeAuction the collateral is seized at the discount growing with the time since the position became liquidatable
+	LiquidationModeAuction
+)
+
+// IsValid is valid liquidation mode
+func (m LiquidationMode) IsValid() bool {
+	return m == LiquidationModeIncentive || m == LiquidationModeAuction
+}
+
+// IsLiquidationAuction is the collateral liquidated by dutch auction
+func (m *Market) IsLiquidationAuction() bool {
+	return m.LiquidationMode == LiquidationModeAuction
+}
+
+// IsPriceStale is the price older than the max price age at t, never stale if the max price age is not set
+func (m *Market) IsPriceStale(t time.Time) bool {
+	return m.MaxPriceAge > 0 && t.Sub(m.PriceUpdatedAt) > time.Duration(m.MaxPriceAge)*time.Second
+}
+
+// BorrowableInIsolation is borrowable in isolation mode
+func (m *Market) BorrowableInIsolation() bool {
+	return m.IsolationMode == IsolationModeBorrowable
+}
+
 // IMarketStore asset store interface
 type IMarketStore interface {
 	Save(ctx context.Context, tx *db.DB, market *Market) error
@@ -93,7 +218,9 @@ type IMarketService interface {
 	CurSupplyRate(ctx context.Context, market *Market) (decimal.Decimal, error)
 	CurTotalBorrows(ctx context.Context, market *Market) (decimal.Decimal, error)
 	CurTotalReserves(ctx context.Context, market *Market) (decimal.Decimal, error)
+	CurTotalSupplies(ctx context.Context, market *Market) (decimal.Decimal, error)
 	AccrueInterest(ctx context.Context, db *db.DB, market *Market, time time.Time) error
+	AccrueFee(ctx context.Context, db *db.DB, market *Market, fee decimal.Decimal) error
 	IsMarketClosed(ctx context.Context, market *Market) bool
 	HasClosedMarkets(ctx context.Context) bool
 }
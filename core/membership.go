@@ -0,0 +1,69 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/fox-one/pkg/store/db"
+	"github.com/jmoiron/sqlx/types"
+)
+
+// Membership the member set and threshold of the multisig group changed by the proposal
+//
+// the membership is effective for the outputs created after the EffectiveAt,
+// so that all the nodes switch at the same output
+type Membership struct {
+	ID uint64 `sql:"PRIMARY_KEY;AUTO_INCREMENT" json:"id"`
+	// the trace id of the proposal
+	TraceID     string         `sql:"size:36;unique_index:membership_trace_idx" json:"trace_id"`
+	EffectiveAt time.Time      `sql:"index:membership_effective_idx" json:"effective_at"`
+	Threshold   uint8          `json:"threshold"`
+	Members     types.JSONText `sql:"type:TEXT" json:"members"`
+	CreatedAt   time.Time      `sql:"default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+// IMembershipStore membership store interface
+type IMembershipStore interface {
+	Save(ctx context.Context, tx *db.DB, membership *Membership) error
+	// FindEffective find the membership effective for the output created at t
+	FindEffective(ctx context.Context, t time.Time) (*Membership, bool, error)
+	All(ctx context.Context) ([]*Membership, error)
+}
+
+// NewMembership new membership of the members and threshold
+func NewMembership(traceID string, members []*Member, threshold uint8, effectiveAt time.Time) *Membership {
+	data, _ := json.Marshal(members)
+	return &Membership{
+		TraceID:     traceID,
+		EffectiveAt: effectiveAt,
+		Threshold:   threshold,
+		Members:     data,
+	}
+}
+
+// MemberList decode the members
+func (m *Membership) MemberList() ([]*Member, error) {
+	var members []*Member
+	if e := json.Unmarshal(m.Members, &members); e != nil {
+		return nil, e
+	}
+
+	return members, nil
+}
+
+// MemberIDs member ids
+func (m *Membership) MemberIDs() []string {
+	members, _ := m.MemberList()
+	ids := make([]string, len(members))
+	for idx, member := range members {
+		ids[idx] = member.ClientID
+	}
+
+	return ids
+}
+
+// IsValidThreshold the threshold of the multisig group must be less than the member count
+func IsValidThreshold(threshold uint8, members int) bool {
+	return threshold >= 1 && int(threshold) < members
+}
@@ -13,19 +13,59 @@ import (
 const (
 	// 600 seconds
 	defaultPriceTimeAlign = 600
+
+	// PriceBlockDuration the duration of the price block, a passed price is in effect for one block at least
+	PriceBlockDuration = defaultPriceTimeAlign * time.Second
+
+	// MaxTwapWindow the max twap window of the market in seconds, far below the 7 days the prices are kept
+	MaxTwapWindow = 24 * 60 * 60
 )
 
 // Price price info
 type Price struct {
-	ID          int64           `sql:"PRIMARY_KEY;AUTO_INCREMENT" json:"id,omitempty"`
-	AssetID     string          `sql:"size:36;unique_index:idx_prices" json:"asset_id,omitempty"`
-	BlockNumber int64           `sql:"default:0;unique_index:idx_prices" json:"block_number,omitempty"`
-	Price       decimal.Decimal `sql:"type:decimal(20,8)" json:"price,omitempty"`
-	Content     types.JSONText  `sql:"type:varchar(1024)" json:"content,omitempty"`
-	Version     int64           `sql:"default:0" json:"version,omitempty"`
-	PassedAt    sql.NullTime    `json:"passed_at,omitempty"`
-	CreatedAt   time.Time       `sql:"default:CURRENT_TIMESTAMP;index:idx_prices_created_at" json:"created_at,omitempty"`
-	UpdatedAt   time.Time       `sql:"default:CURRENT_TIMESTAMP" json:"updated_at,omitempty"`
+	ID               int64           `sql:"PRIMARY_KEY;AUTO_INCREMENT" json:"id,omitempty"`
+	AssetID          string          `sql:"size:36;unique_index:idx_prices" json:"asset_id,omitempty"`
+	BlockNumber      int64           `sql:"default:0;unique_index:idx_prices" json:"block_number,omitempty"`
+	Price            decimal.Decimal `sql:"type:decimal(20,8)" json:"price,omitempty"`
+	Content          types.JSONText  `sql:"type:varchar(1024)" json:"content,omitempty"`
+	Version          int64           `sql:"default:0" json:"version,omitempty"`
+	PassedAt         sql.NullTime    `json:"passed_at,omitempty"`
+	Status           PriceStatus     `sql:"default:0" json:"status,omitempty"`
+	ConsensusVersion int64           `sql:"default:0" json:"consensus_version,omitempty"`
+	CreatedAt        time.Time       `sql:"default:CURRENT_TIMESTAMP;index:idx_prices_created_at" json:"created_at,omitempty"`
+	UpdatedAt        time.Time       `sql:"default:CURRENT_TIMESTAMP" json:"updated_at,omitempty"`
+}
+
+// PriceStatus status of the passed price
+type PriceStatus int
+
+const (
+	_ PriceStatus = iota
+	// PriceStatusPending the price moved more than the max price change and is held until confirmed
+	PriceStatusPending
+	// PriceStatusConfirmed the pending price confirmed by the next price or the governance
+	PriceStatusConfirmed
+)
+
+// PriceConsensus the algorithm agreeing the price provided by the members,
+// the behavior of an existing algorithm must never change, add a new one instead
+type PriceConsensus int
+
+const (
+	_ PriceConsensus = iota
+	// PriceConsensusAdjacency the adjacent prices changing less than the tolerance (5% by default) are valid, averaged
+	PriceConsensusAdjacency
+	// PriceConsensusTrimmedMedian the median of the prices within the tolerance of the median
+	PriceConsensusTrimmedMedian
+	// PriceConsensusWeightedMedian the median weighted by the member weights of the prices within the tolerance of the weighted median
+	PriceConsensusWeightedMedian
+)
+
+// IsValid is valid price consensus
+func (c PriceConsensus) IsValid() bool {
+	return c == PriceConsensusAdjacency ||
+		c == PriceConsensusTrimmedMedian ||
+		c == PriceConsensusWeightedMedian
 }
 
 // PriceTicker price ticker
@@ -40,13 +80,29 @@ type IPriceStore interface {
 	Create(ctx context.Context, tx *db.DB, price *Price) error
 	FindByAssetBlock(ctx context.Context, assetID string, blockNumber int64) (*Price, bool, error)
 	Update(ctx context.Context, tx *db.DB, price *Price) error
+	// FindLatestPassed find the latest passed price of the asset before the block
+	FindLatestPassed(ctx context.Context, assetID string, blockNumber int64) (*Price, bool, error)
+	// FindPassedBefore find the last applied price of the asset passed before t
+	FindPassedBefore(ctx context.Context, assetID string, t time.Time) (*Price, bool, error)
+	// ListPassed list the applied prices of the asset passed between from and to, in ascending order of passed_at
+	ListPassed(ctx context.Context, assetID string, from, to time.Time) ([]*Price, error)
 	DeleteByTime(ctx context.Context, t time.Time) error
 }
 
+// PriceSource the outside source of the market price
+type PriceSource interface {
+	Name() string
+	// Pull pull the price of the market and the time the price updated at
+	Pull(ctx context.Context, market *Market, t time.Time) (decimal.Decimal, time.Time, error)
+}
+
 // IPriceOracleService pracle price service interface
 type IPriceOracleService interface {
 	GetCurrentUnderlyingPrice(ctx context.Context, market *Market) (decimal.Decimal, error)
-	PullPriceTicker(ctx context.Context, assetID string, t time.Time) (*PriceTicker, error)
+	// GetCollateralPrice the twap of the market if the twap window is set, otherwise the current price
+	GetCollateralPrice(ctx context.Context, market *Market) (decimal.Decimal, error)
+	// PullPriceTicker pull the prices from all sources and aggregate them by median
+	PullPriceTicker(ctx context.Context, market *Market, t time.Time) (*PriceTicker, error)
 	PullAllPriceTickers(ctx context.Context, t time.Time) ([]*PriceTicker, error)
 }
 
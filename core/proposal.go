@@ -13,18 +13,22 @@ import (
 type (
 	// Proposal proposal info
 	Proposal struct {
-		ID        int64           `sql:"PRIMARY_KEY" json:"id,omitempty"`
-		CreatedAt time.Time       `json:"created_at,omitempty"`
-		UpdatedAt time.Time       `json:"updated_at,omitempty"`
-		PassedAt  sql.NullTime    `json:"passed_at,omitempty"`
-		Version   int64           `json:"version,omitempty"`
-		TraceID   string          `sql:"size:36" json:"trace_id,omitempty"`
-		Creator   string          `sql:"size:36" json:"creator,omitempty"`
-		AssetID   string          `sql:"size:36" json:"asset_id,omitempty"`
-		Amount    decimal.Decimal `sql:"type:decimal(32,8)" json:"amount,omitempty"`
-		Action    ActionType      `json:"action,omitempty"`
-		Content   types.JSONText  `sql:"type:varchar(1024)" json:"content,omitempty"`
-		Votes     pq.StringArray  `sql:"type:varchar(1024)" json:"votes,omitempty"`
+		ID           int64           `sql:"PRIMARY_KEY" json:"id,omitempty"`
+		CreatedAt    time.Time       `json:"created_at,omitempty"`
+		UpdatedAt    time.Time       `json:"updated_at,omitempty"`
+		PassedAt     sql.NullTime    `json:"passed_at,omitempty"`
+		ExpiresAt    sql.NullTime    `json:"expires_at,omitempty"`
+		ExecutableAt sql.NullTime    `json:"executable_at,omitempty"`
+		Status       ProposalStatus  `sql:"default:1" json:"status,omitempty"`
+		Version      int64           `json:"version,omitempty"`
+		TraceID      string          `sql:"size:36" json:"trace_id,omitempty"`
+		Creator      string          `sql:"size:36" json:"creator,omitempty"`
+		AssetID      string          `sql:"size:36" json:"asset_id,omitempty"`
+		Amount       decimal.Decimal `sql:"type:decimal(32,8)" json:"amount,omitempty"`
+		Action       ActionType      `json:"action,omitempty"`
+		Content      types.JSONText  `sql:"type:text" json:"content,omitempty"`
+		Votes        pq.StringArray  `sql:"type:varchar(1024)" json:"votes,omitempty"`
+		Reason       string          `sql:"size:255" json:"reason,omitempty"`
 	}
 
 	// ProposalStore proposal store interface
@@ -33,6 +37,12 @@ type (
 		Find(ctx context.Context, trace string) (*Proposal, bool, error)
 		Update(ctx context.Context, proposal *Proposal) error
 		List(ctx context.Context, fromID int64, limit int) ([]*Proposal, error)
+		// ListExpired list the created proposals expired at t
+		ListExpired(ctx context.Context, t time.Time) ([]*Proposal, error)
+		// ListQueued list the queued proposals in ascending order of executable_at
+		ListQueued(ctx context.Context) ([]*Proposal, error)
+		// ListExecutable list the queued proposals executable at t
+		ListExecutable(ctx context.Context, t time.Time) ([]*Proposal, error)
 	}
 
 	// ProposalService proposal service interface
@@ -40,5 +50,50 @@ type (
 		ProposalCreated(ctx context.Context, proposal *Proposal, by *Member) error
 		ProposalApproved(ctx context.Context, proposal *Proposal, by *Member) error
 		ProposalPassed(ctx context.Context, proposal *Proposal) error
+		ProposalExpired(ctx context.Context, proposal *Proposal) error
+		ProposalCancelled(ctx context.Context, proposal *Proposal) error
+		ProposalQueued(ctx context.Context, proposal *Proposal) error
+		ProposalVetoed(ctx context.Context, proposal *Proposal) error
+		// BadDebtWrittenOff report the bad debt write-off to the node managers
+		BadDebtWrittenOff(ctx context.Context, transaction *Transaction) error
+		// PriceStale alert the node managers that the market price is older than the max price age
+		PriceStale(ctx context.Context, market *Market) error
 	}
 )
+
+// ProposalStatus proposal status
+type ProposalStatus int
+
+const (
+	_ ProposalStatus = iota
+	// ProposalStatusCreated created and voting
+	ProposalStatusCreated
+	// ProposalStatusPassed passed by the threshold of votes
+	ProposalStatusPassed
+	// ProposalStatusExpired not passed in the voting window
+	ProposalStatusExpired
+	// ProposalStatusCancelled cancelled by the creator before passed
+	ProposalStatusCancelled
+	// ProposalStatusQueued passed and waiting for the timelock
+	ProposalStatusQueued
+	// ProposalStatusVetoed vetoed in the queue
+	ProposalStatusVetoed
+	// ProposalStatusRejected rejected at the creation for the invalid parameters, with the reason
+	ProposalStatusRejected
+	// ProposalStatusExecuted passed and executed, the later votes are ignored
+	ProposalStatusExecuted
+)
+
+// IsClosed is the proposal closed to the votes
+func (p *Proposal) IsClosed() bool {
+	return p.Status == ProposalStatusExpired ||
+		p.Status == ProposalStatusCancelled ||
+		p.Status == ProposalStatusVetoed ||
+		p.Status == ProposalStatusRejected ||
+		p.Status == ProposalStatusExecuted
+}
+
+// IsExpired is the voting window of the proposal ended at t, the proposals without expiry never expire
+func (p *Proposal) IsExpired(t time.Time) bool {
+	return p.ExpiresAt.Valid && !t.Before(p.ExpiresAt.Time)
+}
@@ -0,0 +1,60 @@
+package proposal
+
+import (
+	"compound/pkg/mtg"
+
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// StartAuctionReq start the auction of the market reserves, the prices are the bid asset amounts for the whole lot
+type StartAuctionReq struct {
+	Symbol     string          `json:"symbol,omitempty"`
+	Amount     decimal.Decimal `json:"amount,omitempty"`
+	BidAsset   string          `json:"bid_asset,omitempty"`
+	Opponent   string          `json:"opponent,omitempty"`
+	Type       int             `json:"type,omitempty"`
+	StartPrice decimal.Decimal `json:"start_price"`
+	EndPrice   decimal.Decimal `json:"end_price"`
+	// auction duration in seconds
+	Duration int64 `json:"duration,omitempty"`
+}
+
+// MarshalBinary marshal req to binary
+func (w StartAuctionReq) MarshalBinary() (data []byte, err error) {
+	bidAsset, err := uuid.FromString(w.BidAsset)
+	if err != nil {
+		return nil, err
+	}
+
+	opponent, err := uuid.FromString(w.Opponent)
+	if err != nil {
+		return nil, err
+	}
+
+	return mtg.Encode(w.Symbol, w.Amount, bidAsset, opponent, w.Type, w.StartPrice, w.EndPrice, w.Duration)
+}
+
+// UnmarshalBinary unmarshal bytes to req
+func (w *StartAuctionReq) UnmarshalBinary(data []byte) error {
+	var symbol string
+	var amount, startPrice, endPrice decimal.Decimal
+	var bidAsset, opponent uuid.UUID
+	var auctionType int
+	var duration int64
+
+	if _, err := mtg.Scan(data, &symbol, &amount, &bidAsset, &opponent, &auctionType, &startPrice, &endPrice, &duration); err != nil {
+		return err
+	}
+
+	w.Symbol = symbol
+	w.Amount = amount
+	w.BidAsset = bidAsset.String()
+	w.Opponent = opponent.String()
+	w.Type = auctionType
+	w.StartPrice = startPrice
+	w.EndPrice = endPrice
+	w.Duration = duration
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package proposal
+
+import (
+	"compound/core"
+	"compound/pkg/mtg"
+	"encoding"
+	"encoding/json"
+)
+
+// BatchReq execute the proposal requests in order atomically, voted once
+type BatchReq struct {
+	Items []BatchItem `json:"items"`
+}
+
+// BatchItem the proposal request in the batch
+type BatchItem struct {
+	Action core.ActionType `json:"action"`
+	// the proposal request decoded by the action
+	Content json.RawMessage `json:"content,omitempty"`
+	// the encoded proposal request, as the body of the single proposal
+	Data []byte `json:"-"`
+}
+
+// NewBatchItem new batch item of the proposal request
+func NewBatchItem(action core.ActionType, req encoding.BinaryMarshaler) (BatchItem, error) {
+	data, err := mtg.Encode(req)
+	if err != nil {
+		return BatchItem{}, err
+	}
+
+	content, err := json.Marshal(req)
+	if err != nil {
+		return BatchItem{}, err
+	}
+
+	return BatchItem{
+		Action:  action,
+		Content: content,
+		Data:    data,
+	}, nil
+}
+
+// MarshalBinary marshal req to binary
+func (w BatchReq) MarshalBinary() (data []byte, err error) {
+	values := make([]interface{}, 0, len(w.Items)*2)
+	for _, item := range w.Items {
+		values = append(values, item.Action, string(item.Data))
+	}
+
+	return mtg.Encode(values...)
+}
+
+// UnmarshalBinary unmarshal bytes to req
+func (w *BatchReq) UnmarshalBinary(data []byte) error {
+	var items []BatchItem
+
+	for len(data) > 0 {
+		var action core.ActionType
+		var body string
+
+		rest, err := mtg.Scan(data, &action, &body)
+		if err != nil {
+			return err
+		}
+
+		items = append(items, BatchItem{
+			Action: action,
+			Data:   []byte(body),
+		})
+		data = rest
+	}
+
+	w.Items = items
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package proposal
+
+import (
+	"compound/pkg/mtg"
+
+	"github.com/shopspring/decimal"
+)
+
+// UpdateCategoryReq create or update the e-mode category
+type UpdateCategoryReq struct {
+	Name                 string          `json:"name,omitempty"`
+	CollateralFactor     decimal.Decimal `json:"collateral_factor,omitempty"`
+	LiquidationIncentive decimal.Decimal `json:"liquidation_incentive,omitempty"`
+}
+
+// MarshalBinary marshal req to binary
+func (w UpdateCategoryReq) MarshalBinary() (data []byte, err error) {
+	return mtg.Encode(w.Name, w.CollateralFactor, w.LiquidationIncentive)
+}
+
+// UnmarshalBinary unmarshal bytes to req
+func (w *UpdateCategoryReq) UnmarshalBinary(data []byte) error {
+	var name string
+	var cf, li decimal.Decimal
+
+	if _, err := mtg.Scan(data, &name, &cf, &li); err != nil {
+		return err
+	}
+
+	w.Name = name
+	w.CollateralFactor = cf
+	w.LiquidationIncentive = li
+
+	return nil
+}
+
+// MarketCategoryReq put the market into the e-mode category, empty category to remove it from the category
+type MarketCategoryReq struct {
+	Symbol   string `json:"symbol,omitempty"`
+	Category string `json:"category"`
+}
+
+// MarshalBinary marshal req to binary
+func (w MarketCategoryReq) MarshalBinary() (data []byte, err error) {
+	return mtg.Encode(w.Symbol, w.Category)
+}
+
+// UnmarshalBinary unmarshal bytes to req
+func (w *MarketCategoryReq) UnmarshalBinary(data []byte) error {
+	var symbol, category string
+
+	if _, err := mtg.Scan(data, &symbol, &category); err != nil {
+		return err
+	}
+
+	w.Symbol = symbol
+	w.Category = category
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package proposal
+
+import (
+	"compound/pkg/mtg"
+
+	"github.com/shopspring/decimal"
+)
+
+// UpdateInterestRateModelReq switch the interest rate model of the market
+type UpdateInterestRateModelReq struct {
+	Symbol               string          `json:"symbol,omitempty"`
+	Model                int             `json:"model"`
+	BaseRate             decimal.Decimal `json:"base_rate,omitempty"`
+	Multiplier           decimal.Decimal `json:"multiplier,omitempty"`
+	JumpMultiplier       decimal.Decimal `json:"jump_multiplier,omitempty"`
+	Kink                 decimal.Decimal `json:"kink,omitempty"`
+	SecondJumpMultiplier decimal.Decimal `json:"second_jump_multiplier,omitempty"`
+	SecondKink           decimal.Decimal `json:"second_kink,omitempty"`
+}
+
+// MarshalBinary marshal req to binary
+func (w UpdateInterestRateModelReq) MarshalBinary() (data []byte, err error) {
+	return mtg.Encode(w.Symbol, w.Model, w.BaseRate, w.Multiplier, w.JumpMultiplier, w.Kink, w.SecondJumpMultiplier, w.SecondKink)
+}
+
+// UnmarshalBinary unmarshal bytes to req
+func (w *UpdateInterestRateModelReq) UnmarshalBinary(data []byte) error {
+	var symbol string
+	var model int
+	var baseRate, multiplier, jumpMultiplier, kink, secondJumpMultiplier, secondKink decimal.Decimal
+
+	if _, err := mtg.Scan(data, &symbol, &model, &baseRate, &multiplier, &jumpMultiplier, &kink, &secondJumpMultiplier, &secondKink); err != nil {
+		return err
+	}
+
+	w.Symbol = symbol
+	w.Model = model
+	w.BaseRate = baseRate
+	w.Multiplier = multiplier
+	w.JumpMultiplier = jumpMultiplier
+	w.Kink = kink
+	w.SecondJumpMultiplier = secondJumpMultiplier
+	w.SecondKink = secondKink
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package proposal
+
+import (
+	"compound/pkg/mtg"
+
+	"github.com/shopspring/decimal"
+)
+
+// UpdateIsolationReq update the isolation mode parameters of the market
+type UpdateIsolationReq struct {
+	Symbol        string          `json:"symbol,omitempty"`
+	IsolationMode int             `json:"isolation_mode"`
+	DebtCeiling   decimal.Decimal `json:"debt_ceiling,omitempty"`
+}
+
+// MarshalBinary marshal req to binary
+func (w UpdateIsolationReq) MarshalBinary() (data []byte, err error) {
+	return mtg.Encode(w.Symbol, w.IsolationMode, w.DebtCeiling)
+}
+
+// UnmarshalBinary unmarshal bytes to req
+func (w *UpdateIsolationReq) UnmarshalBinary(data []byte) error {
+	var symbol string
+	var mode int
+	var debtCeiling decimal.Decimal
+
+	if _, err := mtg.Scan(data, &symbol, &mode, &debtCeiling); err != nil {
+		return err
+	}
+
+	w.Symbol = symbol
+	w.IsolationMode = mode
+	w.DebtCeiling = debtCeiling
+
+	return nil
+}
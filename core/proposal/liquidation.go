@@ -0,0 +1,35 @@
+package proposal
+
+import (
+	"compound/pkg/mtg"
+)
+
+// UpdateLiquidationModeReq update the liquidation mode of the collateral market
+type UpdateLiquidationModeReq struct {
+	Symbol string `json:"symbol,omitempty"`
+	Mode   int    `json:"mode"`
+	// the discount reaches the liquidation incentive after the window, in seconds
+	Window int64 `json:"window,omitempty"`
+}
+
+// MarshalBinary marshal req to binary
+func (w UpdateLiquidationModeReq) MarshalBinary() (data []byte, err error) {
+	return mtg.Encode(w.Symbol, w.Mode, w.Window)
+}
+
+// UnmarshalBinary unmarshal bytes to req
+func (w *UpdateLiquidationModeReq) UnmarshalBinary(data []byte) error {
+	var symbol string
+	var mode int
+	var window int64
+
+	if _, err := mtg.Scan(data, &symbol, &mode, &window); err != nil {
+		return err
+	}
+
+	w.Symbol = symbol
+	w.Mode = mode
+	w.Window = window
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package proposal
+
+import (
+	"compound/pkg/mtg"
+
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// AddMemberReq add the member to the multisig group
+type AddMemberReq struct {
+	ClientID string `json:"client_id,omitempty"`
+	// base64 encoded ed25519 public key to verify the votes of the member
+	VerifyKey string `json:"verify_key,omitempty"`
+	// 0 for the default weight 1
+	Weight decimal.Decimal `json:"weight"`
+}
+
+// MarshalBinary marshal req to binary
+func (w AddMemberReq) MarshalBinary() (data []byte, err error) {
+	clientID, err := uuid.FromString(w.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	return mtg.Encode(clientID, w.VerifyKey, w.Weight)
+}
+
+// UnmarshalBinary unmarshal bytes to req
+func (w *AddMemberReq) UnmarshalBinary(data []byte) error {
+	var clientID uuid.UUID
+	var verifyKey string
+	var weight decimal.Decimal
+
+	if _, err := mtg.Scan(data, &clientID, &verifyKey, &weight); err != nil {
+		return err
+	}
+
+	w.ClientID = clientID.String()
+	w.VerifyKey = verifyKey
+	w.Weight = weight
+
+	return nil
+}
+
+// RemoveMemberReq remove the member from the multisig group
+type RemoveMemberReq struct {
+	ClientID string `json:"client_id,omitempty"`
+}
+
+// MarshalBinary marshal req to binary
+func (w RemoveMemberReq) MarshalBinary() (data []byte, err error) {
+	clientID, err := uuid.FromString(w.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	return mtg.Encode(clientID)
+}
+
+// UnmarshalBinary unmarshal bytes to req
+func (w *RemoveMemberReq) UnmarshalBinary(data []byte) error {
+	var clientID uuid.UUID
+
+	if _, err := mtg.Scan(data, &clientID); err != nil {
+		return err
+	}
+
+	w.ClientID = clientID.String()
+
+	return nil
+}
+
+// UpdateThresholdReq update the threshold of the multisig group
+type UpdateThresholdReq struct {
+	Threshold int `json:"threshold"`
+}
+
+// MarshalBinary marshal req to binary
+func (w UpdateThresholdReq) MarshalBinary() (data []byte, err error) {
+	return mtg.Encode(w.Threshold)
+}
+
+// UnmarshalBinary unmarshal bytes to req
+func (w *UpdateThresholdReq) UnmarshalBinary(data []byte) error {
+	var threshold int
+
+	if _, err := mtg.Scan(data, &threshold); err != nil {
+		return err
+	}
+
+	w.Threshold = threshold
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package proposal
+
+import (
+	"compound/pkg/mtg"
+
+	"github.com/shopspring/decimal"
+)
+
+// UpdatePriceConsensusReq update the price consensus of the market
+type UpdatePriceConsensusReq struct {
+	Symbol    string `json:"symbol,omitempty"`
+	Consensus int    `json:"consensus"`
+	// 0 for the default tolerance of the consensus
+	Tolerance decimal.Decimal `json:"tolerance"`
+}
+
+// MarshalBinary marshal req to binary
+func (w UpdatePriceConsensusReq) MarshalBinary() (data []byte, err error) {
+	return mtg.Encode(w.Symbol, w.Consensus, w.Tolerance)
+}
+
+// UnmarshalBinary unmarshal bytes to req
+func (w *UpdatePriceConsensusReq) UnmarshalBinary(data []byte) error {
+	var symbol string
+	var consensus int
+	var tolerance decimal.Decimal
+
+	if _, err := mtg.Scan(data, &symbol, &consensus, &tolerance); err != nil {
+		return err
+	}
+
+	w.Symbol = symbol
+	w.Consensus = consensus
+	w.Tolerance = tolerance
+
+	return nil
+}
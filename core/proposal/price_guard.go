@@ -0,0 +1,57 @@
+package proposal
+
+import (
+	"compound/pkg/mtg"
+
+	"github.com/shopspring/decimal"
+)
+
+// UpdatePriceGuardReq update the max price change of the market per price block
+type UpdatePriceGuardReq struct {
+	Symbol string `json:"symbol,omitempty"`
+	// the ratio, 0 for no limit
+	MaxChange decimal.Decimal `json:"max_change"`
+}
+
+// MarshalBinary marshal req to binary
+func (w UpdatePriceGuardReq) MarshalBinary() (data []byte, err error) {
+	return mtg.Encode(w.Symbol, w.MaxChange)
+}
+
+// UnmarshalBinary unmarshal bytes to req
+func (w *UpdatePriceGuardReq) UnmarshalBinary(data []byte) error {
+	var symbol string
+	var maxChange decimal.Decimal
+
+	if _, err := mtg.Scan(data, &symbol, &maxChange); err != nil {
+		return err
+	}
+
+	w.Symbol = symbol
+	w.MaxChange = maxChange
+
+	return nil
+}
+
+// ConfirmPriceReq apply the pending price of the market held by the price guard
+type ConfirmPriceReq struct {
+	Symbol string `json:"symbol,omitempty"`
+}
+
+// MarshalBinary marshal req to binary
+func (w ConfirmPriceReq) MarshalBinary() (data []byte, err error) {
+	return mtg.Encode(w.Symbol)
+}
+
+// UnmarshalBinary unmarshal bytes to req
+func (w *ConfirmPriceReq) UnmarshalBinary(data []byte) error {
+	var symbol string
+
+	if _, err := mtg.Scan(data, &symbol); err != nil {
+		return err
+	}
+
+	w.Symbol = symbol
+
+	return nil
+}
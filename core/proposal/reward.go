@@ -0,0 +1,35 @@
+package proposal
+
+import (
+	"compound/pkg/mtg"
+
+	"github.com/shopspring/decimal"
+)
+
+// UpdateRewardSpeedReq update the liquidity mining reward speeds of the market, rewards per block
+type UpdateRewardSpeedReq struct {
+	Symbol      string          `json:"symbol,omitempty"`
+	SupplySpeed decimal.Decimal `json:"supply_speed"`
+	BorrowSpeed decimal.Decimal `json:"borrow_speed"`
+}
+
+// MarshalBinary marshal req to binary
+func (w UpdateRewardSpeedReq) MarshalBinary() (data []byte, err error) {
+	return mtg.Encode(w.Symbol, w.SupplySpeed, w.BorrowSpeed)
+}
+
+// UnmarshalBinary unmarshal bytes to req
+func (w *UpdateRewardSpeedReq) UnmarshalBinary(data []byte) error {
+	var symbol string
+	var supplySpeed, borrowSpeed decimal.Decimal
+
+	if _, err := mtg.Scan(data, &symbol, &supplySpeed, &borrowSpeed); err != nil {
+		return err
+	}
+
+	w.Symbol = symbol
+	w.SupplySpeed = supplySpeed
+	w.BorrowSpeed = borrowSpeed
+
+	return nil
+}
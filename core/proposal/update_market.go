@@ -48,19 +48,26 @@ type UpdateMarketAdvanceReq struct {
 	Multiplier     decimal.Decimal `json:"multiplier,omitempty"`
 	JumpMultiplier decimal.Decimal `json:"jump_multiplier,omitempty"`
 	Kink           decimal.Decimal `json:"kink,omitempty"`
+	FlashLoanFee   decimal.Decimal `json:"flash_loan_fee,omitempty"`
+	SupplyCap      decimal.Decimal `json:"supply_cap,omitempty"`
+	// the twap window of the collateral valuation in seconds, 0 for the current price and negative for unchanged
+	TwapWindow int64 `json:"twap_window,omitempty"`
+	// the max price age in seconds, 0 for no limit and negative for unchanged
+	MaxPriceAge int64 `json:"max_price_age,omitempty"`
 }
 
 // MarshalBinary marshal req to binary
 func (w UpdateMarketAdvanceReq) MarshalBinary() (data []byte, err error) {
-	return mtg.Encode(w.Symbol, w.BorrowCap, w.CloseFactor, w.Multiplier, w.JumpMultiplier, w.Kink)
+	return mtg.Encode(w.Symbol, w.BorrowCap, w.CloseFactor, w.Multiplier, w.JumpMultiplier, w.Kink, w.FlashLoanFee, w.SupplyCap, w.TwapWindow, w.MaxPriceAge)
 }
 
 // UnmarshalBinary unmarshal bytes to withdraw
 func (w *UpdateMarketAdvanceReq) UnmarshalBinary(data []byte) error {
 	var symbol string
-	var borrowCap, closeFactor, multiplier, jumpMultiplier, kink decimal.Decimal
+	var borrowCap, closeFactor, multiplier, jumpMultiplier, kink, flashLoanFee, supplyCap decimal.Decimal
+	var twapWindow, maxPriceAge int64
 
-	if _, err := mtg.Scan(data, &symbol, &borrowCap, &closeFactor, &multiplier, &jumpMultiplier, &kink); err != nil {
+	if _, err := mtg.Scan(data, &symbol, &borrowCap, &closeFactor, &multiplier, &jumpMultiplier, &kink, &flashLoanFee, &supplyCap, &twapWindow, &maxPriceAge); err != nil {
 		return err
 	}
 
@@ -70,6 +77,10 @@ func (w *UpdateMarketAdvanceReq) UnmarshalBinary(data []byte) error {
 	w.Multiplier = multiplier
 	w.JumpMultiplier = jumpMultiplier
 	w.Kink = kink
+	w.FlashLoanFee = flashLoanFee
+	w.SupplyCap = supplyCap
+	w.TwapWindow = twapWindow
+	w.MaxPriceAge = maxPriceAge
 
 	return nil
 }
@@ -0,0 +1,35 @@
+package proposal
+
+import (
+	"compound/pkg/mtg"
+
+	"github.com/gofrs/uuid"
+)
+
+// VetoReq veto the proposal queued by the timelock
+type VetoReq struct {
+	Proposal string `json:"proposal,omitempty"`
+}
+
+// MarshalBinary marshal req to binary
+func (w VetoReq) MarshalBinary() (data []byte, err error) {
+	proposal, err := uuid.FromString(w.Proposal)
+	if err != nil {
+		return nil, err
+	}
+
+	return mtg.Encode(proposal)
+}
+
+// UnmarshalBinary unmarshal bytes to req
+func (w *VetoReq) UnmarshalBinary(data []byte) error {
+	var proposal uuid.UUID
+
+	if _, err := mtg.Scan(data, &proposal); err != nil {
+		return err
+	}
+
+	w.Proposal = proposal.String()
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package core
+
+import (
+	"context"
+	"time"
+
+	"github.com/fox-one/pkg/store/db"
+	"github.com/shopspring/decimal"
+)
+
+// Reward the liquidity mining rewards accrued by the user in the market
+type Reward struct {
+	ID      uint64 `sql:"PRIMARY_KEY;AUTO_INCREMENT" json:"id"`
+	UserID  string `sql:"size:36;unique_index:reward_idx" json:"-"`
+	AssetID string `sql:"size:36;unique_index:reward_idx" json:"asset_id"`
+	// 用户最近一次结算时的市场存款奖励指数
+	SupplyIndex decimal.Decimal `sql:"type:decimal(32,16);default:0" json:"supply_index"`
+	// 用户最近一次结算时的市场借款奖励指数
+	BorrowIndex decimal.Decimal `sql:"type:decimal(32,16);default:0" json:"borrow_index"`
+	// 已结算未领取的奖励
+	Accrued   decimal.Decimal `sql:"type:decimal(32,16);default:0" json:"accrued"`
+	Version   int64           `sql:"default:0" json:"version"`
+	CreatedAt time.Time       `sql:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt time.Time       `sql:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// RewardPool the reward asset funded by the members to pay the claimed rewards
+type RewardPool struct {
+	ID      uint64 `sql:"PRIMARY_KEY;AUTO_INCREMENT" json:"id"`
+	AssetID string `sql:"size:36;unique_index:reward_pool_idx" json:"asset_id"`
+	// 可供领取的奖励余额
+	Balance   decimal.Decimal `sql:"type:decimal(32,16);default:0" json:"balance"`
+	Version   int64           `sql:"default:0" json:"version"`
+	CreatedAt time.Time       `sql:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt time.Time       `sql:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// IRewardStore reward store interface
+type IRewardStore interface {
+	Save(ctx context.Context, tx *db.DB, reward *Reward) error
+	Find(ctx context.Context, userID, assetID string) (*Reward, bool, error)
+	FindByUser(ctx context.Context, userID string) ([]*Reward, error)
+	Update(ctx context.Context, tx *db.DB, reward *Reward) error
+	SavePool(ctx context.Context, tx *db.DB, pool *RewardPool) error
+	FindPool(ctx context.Context, assetID string) (*RewardPool, bool, error)
+	UpdatePool(ctx context.Context, tx *db.DB, pool *RewardPool) error
+}
+
+// IRewardService reward service interface
+type IRewardService interface {
+	// Distribute accrue the rewards of the user in the market up to the current market reward indexes,
+	// should be called after the market interest accrued and before the collaterals or borrows of the user changed
+	Distribute(ctx context.Context, tx *db.DB, market *Market, userID string) (*Reward, error)
+	// Accrued the rewards of the user not claimed yet, including the part not distributed
+	Accrued(ctx context.Context, userID string) (decimal.Decimal, error)
+}
@@ -2,6 +2,7 @@ package core
 
 import (
 	"crypto/ed25519"
+	"time"
 
 	"github.com/shopspring/decimal"
 )
@@ -15,6 +16,9 @@ type System struct {
 	Threshold    uint8
 	VoteAsset    string
 	VoteAmount   decimal.Decimal
+	VoteWindow   time.Duration
+	Timelocks    map[ActionType]time.Duration
+	RewardAsset  string
 	PrivateKey   ed25519.PrivateKey
 	SignKey      ed25519.PrivateKey
 	Location     string
@@ -46,3 +50,8 @@ func (s *System) IsAdmin(userID string) bool {
 
 	return false
 }
+
+// Timelock the delay before the passed proposal of the action executed
+func (s *System) Timelock(action ActionType) time.Duration {
+	return s.Timelocks[action]
+}
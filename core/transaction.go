@@ -54,6 +54,24 @@ const (
 	TransactionKeyRefund = "refund"
 	// TransactionKeyOrigin origin
 	TransactionKeyOrigin = "origin"
+	// TransactionKeyFee fee
+	TransactionKeyFee = "fee"
+	// TransactionKeyStableRate stable borrow rate
+	TransactionKeyStableRate = "stable_rate"
+	// TransactionKeySocialized bad debt socialized to the suppliers
+	TransactionKeySocialized = "socialized"
+	// TransactionKeyTargetCTokenAssetID target ctoken asset id of collateral swap
+	TransactionKeyTargetCTokenAssetID = "target_ctoken_asset_id"
+	// TransactionKeyTargetAmount target amount of collateral swap
+	TransactionKeyTargetAmount = "target_amount"
+	// TransactionKeyRepayAssetID repay asset id of self liquidation
+	TransactionKeyRepayAssetID = "repay_asset_id"
+	// TransactionKeyRepayAmount repay amount of self liquidation and deleverage
+	TransactionKeyRepayAmount = "repay_amount"
+	// TransactionKeyBorrowAmount borrow amount of leverage
+	TransactionKeyBorrowAmount = "borrow_amount"
+	// TransactionKeyRewardPool reward pool balance after funded
+	TransactionKeyRewardPool = "reward_pool"
 )
 
 // TransactionExtraData extra data
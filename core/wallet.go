@@ -42,7 +42,8 @@ type Output struct {
 
 // Transfer transfer struct
 type Transfer struct {
-	ID        int64           `sql:"PRIMARY_KEY" json:"id,omitempty"`
+	ID int64 `sql:"PRIMARY_KEY" json:"id,omitempty"`
+	// the creation time of the output making the transfer, the cashier pays it with the member set effective at the time
 	CreatedAt time.Time       `json:"created_at,omitempty"`
 	UpdatedAt time.Time       `json:"updated_at,omitempty"`
 	TraceID   string          `sql:"type:char(36)" json:"trace_id,omitempty"`
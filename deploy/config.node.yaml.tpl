@@ -15,6 +15,30 @@ db:
 # 每个节点部署一个自己的price oracle
 price_oracle:
   end_point: https://poracle-dev.fox.one
+  # 多个价格源, 与 end_point 一起取中位数
+  sources:
+    # 通用 REST JSON 价格源, {asset_id} 和 {symbol} 替换为市场的资产, price_field 和 time_field 为 json 字段路径
+    - name: ~
+      type: rest
+      url: ~
+      price_field: ~
+      time_field: ~
+    # 静态 json 文件价格源, {"资产 ID 或 symbol": "价格"}
+    # - name: ~
+    #   type: file
+    #   url: ~
+    # CSV 价格源, 文件路径或 url, 每行 "资产 ID 或 symbol,价格[,unix 时间戳]"
+    # - name: ~
+    #   type: csv
+    #   url: ~
+  # 偏离中位数超过该比例的价格被丢弃, 0 表示不丢弃
+  max_deviation: 0.05
+  # 超过该时间 (秒) 的价格被丢弃, 0 表示不丢弃
+  max_age: 600
+
+# 流动性挖矿奖励的资产, 需要注入到多签钱包
+reward:
+  asset: ~
 
 dapp:
   num: 7000103159
@@ -40,7 +64,15 @@ group:
     - client_id: ~
     # 节点用于校验签名的公钥
       verify_key: ~
+    # 节点喂价在加权中位数价格共识中的权重, 未设置时为 1
+      weight: 1
   threshold: 2
   vote:
     asset: 965e5c6e-434c-3fa9-b780-c50f43cd955c
-    amount: 0.00000001
\ No newline at end of file
+    amount: 0.00000001
+    # 提案投票期限 (秒), 超过后提案过期, 未设置时为 7 天
+    window: 604800
+  # 提案通过后的执行延迟 (秒), 按提案类型配置, 未配置的提案通过后立即执行
+  timelocks:
+    ProposalUpdateMarket: 86400
+    ProposalWithdrawReserves: 86400
\ No newline at end of file
@@ -0,0 +1,43 @@
+package rest
+
+import (
+	"compound/core"
+	"compound/handler/render"
+	"compound/handler/views"
+	"compound/internal/compound"
+	"net/http"
+	"time"
+)
+
+// response all reserve auctions, the current highest bid of the open english auction is exposed as the winning bid
+func allAuctionsHandler(auctionStr core.IAuctionStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		auctions, e := auctionStr.All(ctx)
+		if e != nil {
+			render.BadRequest(w, e)
+			return
+		}
+
+		now := time.Now()
+		auctionViews := make([]*views.Auction, 0, len(auctions))
+		for _, a := range auctions {
+			auctionView := views.Auction{
+				Auction: *a,
+			}
+
+			if a.Winner != "" {
+				auctionView.WinnerAddress = core.BuildUserAddress(a.Winner)
+			}
+
+			if a.Type == core.AuctionTypeDutch && a.Status == core.AuctionStatusOpen {
+				auctionView.Price = compound.DutchAuctionPrice(a.StartPrice, a.EndPrice, a.StartAt, a.EndAt, now).String()
+			}
+
+			auctionViews = append(auctionViews, &auctionView)
+		}
+
+		render.JSON(w, auctionViews)
+	}
+}
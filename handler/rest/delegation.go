@@ -0,0 +1,74 @@
+package rest
+
+import (
+	"compound/core"
+	"compound/handler/param"
+	"compound/handler/render"
+	"compound/handler/views"
+	"errors"
+	"net/http"
+)
+
+// response borrow allowances by delegator or delegatee address
+func delegationsHandler(userStr core.UserStore, delegationStr core.IDelegationStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		var params struct {
+			Delegator string `json:"delegator"`
+			Delegatee string `json:"delegatee"`
+		}
+
+		if e := param.Binding(r, &params); e != nil {
+			render.BadRequest(w, e)
+			return
+		}
+
+		var delegations []*core.Delegation
+		if params.Delegator != "" {
+			user, e := userStr.FindByAddress(ctx, params.Delegator)
+			if e != nil {
+				render.BadRequest(w, e)
+				return
+			}
+
+			delegations, e = delegationStr.FindByDelegator(ctx, user.UserID)
+			if e != nil {
+				render.BadRequest(w, e)
+				return
+			}
+		} else if params.Delegatee != "" {
+			user, e := userStr.FindByAddress(ctx, params.Delegatee)
+			if e != nil {
+				render.BadRequest(w, e)
+				return
+			}
+
+			delegations, e = delegationStr.FindByDelegatee(ctx, user.UserID)
+			if e != nil {
+				render.BadRequest(w, e)
+				return
+			}
+		} else {
+			render.BadRequest(w, errors.New("delegator or delegatee required"))
+			return
+		}
+
+		delegationViews := make([]*views.Delegation, 0, len(delegations))
+		for _, d := range delegations {
+			delegationViews = append(delegationViews, convert2DelegationView(d))
+		}
+
+		render.JSON(w, delegationViews)
+	}
+}
+
+func convert2DelegationView(delegation *core.Delegation) *views.Delegation {
+	delegationView := views.Delegation{
+		Delegation:       *delegation,
+		DelegatorAddress: core.BuildUserAddress(delegation.Delegator),
+		DelegateeAddress: core.BuildUserAddress(delegation.Delegatee),
+	}
+
+	return &delegationView
+}
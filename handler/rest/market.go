@@ -7,6 +7,7 @@ import (
 	"compound/handler/views"
 	"context"
 	"net/http"
+	"time"
 
 	"github.com/shopspring/decimal"
 )
@@ -76,11 +77,22 @@ func getMarketView(ctx context.Context, market *core.Market, supplyStr core.ISup
 	}
 
 	marketView := views.Market{
-		Market:    *market,
-		SupplyAPY: supplyRate,
-		BorrowAPY: borrowRate,
-		Suppliers: countOfSupplies,
-		Borrowers: countOfBorrows,
+		Market:     *market,
+		SupplyAPY:  supplyRate,
+		BorrowAPY:  borrowRate,
+		Suppliers:  countOfSupplies,
+		Borrowers:  countOfBorrows,
+		PriceStale: market.IsPriceStale(time.Now()),
+	}
+
+	if market.SupplyCap.GreaterThan(decimal.Zero) {
+		totalSupplies, e := marketSrv.CurTotalSupplies(ctx, market)
+		if e != nil {
+			totalSupplies = decimal.Zero
+		}
+
+		remaining := decimal.Max(market.SupplyCap.Sub(totalSupplies), decimal.Zero)
+		marketView.SupplyCapRemaining = &remaining
 	}
 
 	return &marketView
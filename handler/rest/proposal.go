@@ -0,0 +1,22 @@
+package rest
+
+import (
+	"compound/core"
+	"compound/handler/render"
+	"net/http"
+)
+
+// response the proposals queued by the timelock, in ascending order of the executable time
+func queuedProposalsHandler(proposalStr core.ProposalStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		proposals, e := proposalStr.ListQueued(ctx)
+		if e != nil {
+			render.BadRequest(w, e)
+			return
+		}
+
+		render.JSON(w, proposals)
+	}
+}
@@ -19,7 +19,11 @@ func Handle(
 	blockService core.IBlockService,
 	priceService core.IPriceOracleService,
 	accountService core.IAccountService,
-	marketService core.IMarketService) http.Handler {
+	marketService core.IMarketService,
+	rewardService core.IRewardService,
+	delegationStore core.IDelegationStore,
+	auctionStore core.IAuctionStore,
+	proposalStore core.ProposalStore) http.Handler {
 	router := chi.NewRouter()
 
 	router.NotFound(func(w http.ResponseWriter, r *http.Request) {
@@ -29,12 +33,17 @@ func Handle(
 	router.Get("/markets", allMarketsHandler(marketStore, supplyStore, borrowStore, marketService))
 	router.Get("/markets/{asset}", marketHandler(marketStore, supplyStore, borrowStore, marketService))
 	router.Get("/liquidities/{address}", liquidityHandler(userStore, blockService, accountService))
+	router.Get("/rewards/{address}", rewardHandler(userStore, rewardService))
 
 	// supplies?address=xxxxx&asset=xxxxx
 	router.Get("/supplies", suppliesHandler(userStore, marketStore, supplyStore, priceService, blockService))
 	// borrows?address=xxxxx&asset=xxxx
 	router.Get("/borrows", borrowsHandler(userStore, marketStore, borrowStore, priceService, blockService))
 	router.Get("/transactions", transactionsHandler(transactionStore))
+	// delegations?delegator=xxxxx or delegations?delegatee=xxxxx
+	router.Get("/delegations", delegationsHandler(userStore, delegationStore))
+	router.Get("/auctions", allAuctionsHandler(auctionStore))
+	router.Get("/proposals/queued", queuedProposalsHandler(proposalStore))
 
 	return router
 }
@@ -0,0 +1,43 @@
+package rest
+
+import (
+	"compound/core"
+	"compound/handler/param"
+	"compound/handler/render"
+	"compound/handler/views"
+	"net/http"
+)
+
+// response accrued rewards by address
+func rewardHandler(userStr core.UserStore, rewardSrv core.IRewardService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		var params struct {
+			Address string `json:"address"`
+		}
+
+		if e := param.Binding(r, &params); e != nil {
+			render.BadRequest(w, e)
+			return
+		}
+
+		user, e := userStr.FindByAddress(ctx, params.Address)
+		if e != nil {
+			render.BadRequest(w, e)
+			return
+		}
+
+		accrued, e := rewardSrv.Accrued(ctx, user.UserID)
+		if e != nil {
+			render.BadRequest(w, e)
+			return
+		}
+
+		rewardView := views.Reward{
+			Accrued: accrued,
+		}
+
+		render.JSON(w, rewardView)
+	}
+}
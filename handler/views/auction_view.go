@@ -0,0 +1,14 @@
+package views
+
+import (
+	"compound/core"
+)
+
+// Auction reserve auction view
+type Auction struct {
+	core.Auction
+	// the current highest bidder of the open english auction or the winner of the settled auction
+	WinnerAddress string `json:"winner_address,omitempty"`
+	// the current asking price of the open dutch auction
+	Price string `json:"price,omitempty"`
+}
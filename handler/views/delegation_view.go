@@ -0,0 +1,12 @@
+package views
+
+import (
+	"compound/core"
+)
+
+// Delegation borrow delegation view
+type Delegation struct {
+	core.Delegation
+	DelegatorAddress string `json:"delegator_address"`
+	DelegateeAddress string `json:"delegatee_address"`
+}
@@ -13,4 +13,8 @@ type Market struct {
 	BorrowAPY decimal.Decimal `json:"borrow_apy"`
 	Suppliers int64           `json:"suppliers"`
 	Borrowers int64           `json:"borrowers"`
+	// remaining supply headroom under the supply cap, omitted if no cap
+	SupplyCapRemaining *decimal.Decimal `json:"supply_cap_remaining,omitempty"`
+	// the price is older than the max price age, borrow, unpledge, redeem and liquidation are paused
+	PriceStale bool `json:"price_stale"`
 }
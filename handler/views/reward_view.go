@@ -0,0 +1,8 @@
+package views
+
+import "github.com/shopspring/decimal"
+
+// Reward liquidity mining reward view
+type Reward struct {
+	Accrued decimal.Decimal `json:"accrued"`
+}
@@ -0,0 +1,22 @@
+package compound
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// DutchAuctionPrice the asking price of the dutch auction at t, declines linearly from startPrice at startAt to endPrice at endAt
+func DutchAuctionPrice(startPrice, endPrice decimal.Decimal, startAt, endAt, t time.Time) decimal.Decimal {
+	if !t.After(startAt) || !endAt.After(startAt) {
+		return startPrice
+	}
+
+	if !t.Before(endAt) {
+		return endPrice
+	}
+
+	elapsed := decimal.NewFromInt(int64(t.Sub(startAt)))
+	duration := decimal.NewFromInt(int64(endAt.Sub(startAt)))
+	return startPrice.Sub(startPrice.Sub(endPrice).Mul(elapsed).Div(duration)).Truncate(8)
+}
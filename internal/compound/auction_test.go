@@ -0,0 +1,30 @@
+package compound
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDutchAuctionPrice(t *testing.T) {
+	d := decimal.RequireFromString
+	startAt := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	endAt := startAt.Add(10 * time.Hour)
+
+	for _, c := range []struct {
+		elapsed time.Duration
+		price   string
+	}{
+		{-time.Hour, "100"},
+		{0, "100"},
+		{time.Hour, "95"},
+		{5 * time.Hour, "75"},
+		{10 * time.Hour, "50"},
+		{11 * time.Hour, "50"},
+	} {
+		price := DutchAuctionPrice(d("100"), d("50"), startAt, endAt, startAt.Add(c.elapsed))
+		assert.Equal(t, c.price, price.String(), c.elapsed.String())
+	}
+}
@@ -0,0 +1,16 @@
+package compound
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// WriteOffBadDebt split the bad debt into the part written off against the reserves and the part socialized to the suppliers
+// the socialized part is removed from the total borrows without touching the reserves, which lowers the exchange rate
+func WriteOffBadDebt(debt, reserves decimal.Decimal) (decimal.Decimal, decimal.Decimal) {
+	if debt.LessThanOrEqual(decimal.Zero) {
+		return decimal.Zero, decimal.Zero
+	}
+
+	fromReserves := decimal.Min(debt, decimal.Max(reserves, decimal.Zero))
+	return fromReserves, debt.Sub(fromReserves)
+}
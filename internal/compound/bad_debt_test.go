@@ -0,0 +1,27 @@
+package compound
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteOffBadDebt(t *testing.T) {
+	d := decimal.RequireFromString
+
+	for _, c := range []struct {
+		debt, reserves           string
+		fromReserves, socialized string
+	}{
+		{"0", "10", "0", "0"},
+		{"5", "10", "5", "0"},
+		{"10", "10", "10", "0"},
+		{"15", "10", "10", "5"},
+		{"15", "0", "0", "15"},
+	} {
+		fromReserves, socialized := WriteOffBadDebt(d(c.debt), d(c.reserves))
+		assert.Equal(t, c.fromReserves, fromReserves.String(), c.debt)
+		assert.Equal(t, c.socialized, socialized.String(), c.debt)
+	}
+}
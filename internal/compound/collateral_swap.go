@@ -0,0 +1,38 @@
+package compound
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// CollateralSwapFee the fee of the collateral swap, charged on the swapped underlying and kept in the reserves
+var CollateralSwapFee = decimal.NewFromFloat(0.003)
+
+// SwapPrices the prices of the collateral swap, the swapped collateral at the lower of the current and collateral prices,
+// and the target collateral at the higher one, so the swap is never priced better than the liquidity check
+func SwapPrices(fromPrice, fromCollateralPrice, toPrice, toCollateralPrice decimal.Decimal) (decimal.Decimal, decimal.Decimal) {
+	return decimal.Min(fromPrice, fromCollateralPrice), decimal.Max(toPrice, toCollateralPrice)
+}
+
+// SwapCollateral convert the ctokens of market A to the ctokens of market B at the prices, after the fee
+//
+// 	underlying_a = ctokens_a * exchange_rate_a
+// 	underlying_b = underlying_a * (1 - fee) * price_a / price_b
+// 	ctokens_b = underlying_b / exchange_rate_b
+func SwapCollateral(ctokensA, exchangeRateA, priceA, exchangeRateB, priceB, fee decimal.Decimal) (underlyingA, underlyingB, ctokensB decimal.Decimal) {
+	if ctokensA.LessThanOrEqual(decimal.Zero) ||
+		exchangeRateA.LessThanOrEqual(decimal.Zero) ||
+		priceA.LessThanOrEqual(decimal.Zero) ||
+		exchangeRateB.LessThanOrEqual(decimal.Zero) ||
+		priceB.LessThanOrEqual(decimal.Zero) ||
+		fee.LessThan(decimal.Zero) || fee.GreaterThanOrEqual(decimal.NewFromInt(1)) {
+		return decimal.Zero, decimal.Zero, decimal.Zero
+	}
+
+	underlyingA = ctokensA.Mul(exchangeRateA).Truncate(16)
+	underlyingB = underlyingA.Mul(decimal.NewFromInt(1).Sub(fee)).Mul(priceA).Div(priceB).Truncate(16)
+	ctokensB = underlyingB.Div(exchangeRateB).Truncate(8)
+	// the underlying is recalculated with the truncated ctokens
+	underlyingB = ctokensB.Mul(exchangeRateB).Truncate(16)
+
+	return underlyingA, underlyingB, ctokensB
+}
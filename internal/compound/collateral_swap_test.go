@@ -0,0 +1,42 @@
+package compound
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSwapCollateral(t *testing.T) {
+	d := decimal.RequireFromString
+
+	underlyingA, underlyingB, ctokensB := SwapCollateral(d("100"), d("0.02"), d("50000"), d("0.5"), d("2000"), decimal.Zero)
+	assert.Equal(t, "2", underlyingA.String())
+	assert.Equal(t, "50", underlyingB.String())
+	assert.Equal(t, "100", ctokensB.String())
+
+	// the fee is kept out of the swapped underlying
+	underlyingA, underlyingB, ctokensB = SwapCollateral(d("100"), d("0.02"), d("50000"), d("0.5"), d("2000"), d("0.01"))
+	assert.Equal(t, "2", underlyingA.String())
+	assert.Equal(t, "49.5", underlyingB.String())
+	assert.Equal(t, "99", ctokensB.String())
+
+	_, _, ctokensB = SwapCollateral(d("100"), d("0.02"), d("50000"), d("0.5"), d("0"), decimal.Zero)
+	assert.True(t, ctokensB.IsZero())
+
+	_, _, ctokensB = SwapCollateral(d("100"), d("0.02"), d("50000"), d("0.5"), d("2000"), d("1"))
+	assert.True(t, ctokensB.IsZero())
+}
+
+func TestSwapPrices(t *testing.T) {
+	d := decimal.RequireFromString
+
+	// the current price of A is pumped above its twap, and the current price of B is dumped below its twap
+	fromPrice, toPrice := SwapPrices(d("60000"), d("50000"), d("1800"), d("2000"))
+	assert.Equal(t, "50000", fromPrice.String())
+	assert.Equal(t, "2000", toPrice.String())
+
+	fromPrice, toPrice = SwapPrices(d("40000"), d("50000"), d("2200"), d("2000"))
+	assert.Equal(t, "40000", fromPrice.String())
+	assert.Equal(t, "2200", toPrice.String())
+}
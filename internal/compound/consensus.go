@@ -0,0 +1,154 @@
+package compound
+
+import (
+	"sort"
+
+	"github.com/shopspring/decimal"
+)
+
+// DefaultAdjacencyTolerance the max change ratio between the adjacent prices of the adjacency consensus
+var DefaultAdjacencyTolerance = decimal.NewFromFloat(0.05)
+
+// ProvidedPrice the price provided by the member and the weight of the member
+type ProvidedPrice struct {
+	Provider string
+	Price    decimal.Decimal
+	Weight   decimal.Decimal
+}
+
+// AdjacencyConsensus sort the prices ascending, the adjacent prices changing less than the tolerance are valid,
+// returns the average of the valid prices and the count of the providers of them. DefaultAdjacencyTolerance is used if the tolerance is not positive
+//
+// the pair is valued at the lower price of the two
+func AdjacencyConsensus(prices []ProvidedPrice, tolerance decimal.Decimal) (decimal.Decimal, int) {
+	if tolerance.LessThanOrEqual(decimal.Zero) {
+		tolerance = DefaultAdjacencyTolerance
+	}
+
+	sorted := sortPrices(prices)
+	valid := make(map[string]decimal.Decimal)
+	for i := 1; i < len(sorted); i++ {
+		first := sorted[i-1]
+		second := sorted[i]
+		if first.Price.LessThanOrEqual(decimal.Zero) {
+			continue
+		}
+
+		changeRatio := second.Price.Sub(first.Price).Abs().Div(first.Price)
+		if changeRatio.LessThan(tolerance) {
+			valid[first.Provider] = first.Price
+			valid[second.Provider] = first.Price
+		}
+	}
+
+	if len(valid) == 0 {
+		return decimal.Zero, 0
+	}
+
+	sum := decimal.Zero
+	for _, p := range valid {
+		sum = sum.Add(p)
+	}
+
+	return sum.Div(decimal.NewFromInt(int64(len(valid)))), len(valid)
+}
+
+// TrimmedMedianConsensus trim the prices deviating from the median more than the tolerance,
+// returns the median of the remaining prices and the count of the providers of them, nothing trimmed if the tolerance is not positive
+func TrimmedMedianConsensus(prices []ProvidedPrice, tolerance decimal.Decimal) (decimal.Decimal, int) {
+	latest := latestPrices(prices)
+	values := make([]decimal.Decimal, len(latest))
+	for i, p := range latest {
+		values[i] = p.Price
+	}
+
+	return AggregatePrices(values, tolerance)
+}
+
+// WeightedMedianConsensus trim the prices deviating from the weighted median more than the tolerance,
+// returns the weighted median of the remaining prices and the count of the providers of them, nothing trimmed if the tolerance is not positive.
+// the providers without positive weight take no part in the median, so they are not counted
+func WeightedMedianConsensus(prices []ProvidedPrice, tolerance decimal.Decimal) (decimal.Decimal, int) {
+	weighted := make([]ProvidedPrice, 0, len(prices))
+	for _, p := range latestPrices(prices) {
+		if p.Weight.GreaterThan(decimal.Zero) {
+			weighted = append(weighted, p)
+		}
+	}
+
+	median := WeightedMedian(weighted)
+	if median.LessThanOrEqual(decimal.Zero) {
+		return median, 0
+	}
+
+	if tolerance.LessThanOrEqual(decimal.Zero) {
+		return median, len(weighted)
+	}
+
+	valid := make([]ProvidedPrice, 0, len(weighted))
+	for _, p := range weighted {
+		if p.Price.Sub(median).Abs().Div(median).LessThanOrEqual(tolerance) {
+			valid = append(valid, p)
+		}
+	}
+
+	return WeightedMedian(valid), len(valid)
+}
+
+// WeightedMedian the price splitting the total weight in half, the average of the two prices if split exactly between them,
+// the prices without positive weight are ignored
+func WeightedMedian(prices []ProvidedPrice) decimal.Decimal {
+	sorted := make([]ProvidedPrice, 0, len(prices))
+	total := decimal.Zero
+	for _, p := range sortPrices(prices) {
+		if p.Weight.GreaterThan(decimal.Zero) {
+			sorted = append(sorted, p)
+			total = total.Add(p.Weight)
+		}
+	}
+
+	half := total.Div(decimal.NewFromInt(2))
+	cumulative := decimal.Zero
+	for i, p := range sorted {
+		cumulative = cumulative.Add(p.Weight)
+		if cumulative.LessThan(half) {
+			continue
+		}
+
+		if cumulative.Equal(half) && i+1 < len(sorted) {
+			return p.Price.Add(sorted[i+1].Price).Div(decimal.NewFromInt(2)).Truncate(8)
+		}
+
+		return p.Price
+	}
+
+	return decimal.Zero
+}
+
+// sortPrices sort the prices ascending, the order of the equal prices is kept
+func sortPrices(prices []ProvidedPrice) []ProvidedPrice {
+	sorted := make([]ProvidedPrice, len(prices))
+	copy(sorted, prices)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Price.LessThan(sorted[j].Price)
+	})
+
+	return sorted
+}
+
+// latestPrices keep the latest price of each provider
+func latestPrices(prices []ProvidedPrice) []ProvidedPrice {
+	index := make(map[string]int)
+	latest := make([]ProvidedPrice, 0, len(prices))
+	for _, p := range prices {
+		if i, ok := index[p.Provider]; ok {
+			latest[i] = p
+			continue
+		}
+
+		index[p.Provider] = len(latest)
+		latest = append(latest, p)
+	}
+
+	return latest
+}
@@ -0,0 +1,147 @@
+package compound
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+// providedPrices build the provided prices of the providers a, b, c... with the weights, 1 if not given
+func providedPrices(prices []string, weights ...string) []ProvidedPrice {
+	provided := make([]ProvidedPrice, len(prices))
+	for i, p := range prices {
+		weight := decimal.NewFromInt(1)
+		if i < len(weights) {
+			weight = decimal.RequireFromString(weights[i])
+		}
+
+		provided[i] = ProvidedPrice{
+			Provider: string(rune('a' + i)),
+			Price:    decimal.RequireFromString(p),
+			Weight:   weight,
+		}
+	}
+
+	return provided
+}
+
+func TestAdjacencyConsensus(t *testing.T) {
+	for _, c := range []struct {
+		name      string
+		prices    []string
+		tolerance string
+		price     string
+		count     int
+	}{
+		{"empty", nil, "0", "0", 0},
+		{"single", []string{"100"}, "0", "0", 0},
+		{"two close", []string{"100", "101"}, "0", "100", 2},
+		{"two apart", []string{"100", "110"}, "0", "0", 0},
+		{"exactly the default tolerance", []string{"100", "105"}, "0", "0", 0},
+		{"unsorted", []string{"102", "100", "101"}, "0", "100.6666666666666667", 3},
+		{"outlier dropped", []string{"100", "101", "200"}, "0", "100", 2},
+		{"two clusters", []string{"100", "101", "200", "201"}, "0", "150", 4},
+		{"chained", []string{"100", "104", "108"}, "0", "102.6666666666666667", 3},
+		{"custom tolerance", []string{"100", "109"}, "0.1", "100", 2},
+		{"tighter tolerance", []string{"100", "101"}, "0.005", "0", 0},
+		{"equal prices", []string{"100", "100", "100"}, "0", "100", 3},
+	} {
+		price, count := AdjacencyConsensus(providedPrices(c.prices), decimal.RequireFromString(c.tolerance))
+		assert.Equal(t, c.price, price.String(), c.name)
+		assert.Equal(t, c.count, count, c.name)
+	}
+}
+
+func TestAdjacencyConsensusDuplicatedProvider(t *testing.T) {
+	prices := providedPrices([]string{"100", "101"})
+	prices = append(prices, ProvidedPrice{Provider: "a", Price: decimal.NewFromInt(102)})
+
+	price, count := AdjacencyConsensus(prices, decimal.Zero)
+	assert.Equal(t, "101", price.String())
+	assert.Equal(t, 2, count)
+}
+
+func TestTrimmedMedianConsensus(t *testing.T) {
+	for _, c := range []struct {
+		name      string
+		prices    []string
+		tolerance string
+		price     string
+		count     int
+	}{
+		{"empty", nil, "0.05", "0", 0},
+		{"single", []string{"100"}, "0.05", "100", 1},
+		{"odd", []string{"102", "100", "101"}, "0.05", "101", 3},
+		{"even", []string{"100", "101", "102", "103"}, "0.05", "101.5", 4},
+		{"outlier trimmed", []string{"100", "101", "102", "200"}, "0.05", "101", 3},
+		{"both sides trimmed", []string{"50", "100", "101", "102", "200"}, "0.05", "101", 3},
+		{"no tolerance", []string{"100", "101", "102", "200"}, "0", "101.5", 4},
+		{"exactly the tolerance", []string{"95", "100", "105"}, "0.05", "100", 3},
+		{"majority apart", []string{"100", "200", "300"}, "0.05", "200", 1},
+	} {
+		price, count := TrimmedMedianConsensus(providedPrices(c.prices), decimal.RequireFromString(c.tolerance))
+		assert.Equal(t, c.price, price.String(), c.name)
+		assert.Equal(t, c.count, count, c.name)
+	}
+}
+
+func TestTrimmedMedianConsensusDuplicatedProvider(t *testing.T) {
+	prices := providedPrices([]string{"100", "101", "102"})
+	prices = append(prices, ProvidedPrice{Provider: "a", Price: decimal.NewFromInt(103)})
+
+	price, count := TrimmedMedianConsensus(prices, decimal.NewFromFloat(0.05))
+	assert.Equal(t, "102", price.String())
+	assert.Equal(t, 3, count)
+}
+
+func TestWeightedMedian(t *testing.T) {
+	for _, c := range []struct {
+		name    string
+		prices  []string
+		weights []string
+		median  string
+	}{
+		{"empty", nil, nil, "0"},
+		{"single", []string{"100"}, nil, "100"},
+		{"equal weights odd", []string{"102", "100", "101"}, nil, "101"},
+		{"equal weights even", []string{"100", "101", "102", "103"}, nil, "101.5"},
+		{"heavy low", []string{"100", "101", "102"}, []string{"3", "1", "1"}, "100"},
+		{"heavy high", []string{"100", "101", "102"}, []string{"1", "1", "3"}, "102"},
+		{"split exactly", []string{"100", "102"}, []string{"2", "2"}, "101"},
+		{"split exactly at the heavy one", []string{"100", "101", "102"}, []string{"2", "1", "1"}, "100.5"},
+		{"zero weight ignored", []string{"100", "101", "500"}, []string{"1", "1", "0"}, "100.5"},
+		{"all zero weights", []string{"100", "101"}, []string{"0", "0"}, "0"},
+		{"fractional weights", []string{"100", "101", "102"}, []string{"0.2", "0.5", "0.3"}, "101"},
+	} {
+		median := WeightedMedian(providedPrices(c.prices, c.weights...))
+		assert.Equal(t, c.median, median.String(), c.name)
+	}
+}
+
+func TestWeightedMedianConsensus(t *testing.T) {
+	for _, c := range []struct {
+		name      string
+		prices    []string
+		weights   []string
+		tolerance string
+		price     string
+		count     int
+	}{
+		{"empty", nil, nil, "0.05", "0", 0},
+		{"equal weights", []string{"100", "101", "102"}, nil, "0.05", "101", 3},
+		{"outlier trimmed", []string{"100", "101", "102", "200"}, nil, "0.05", "101", 3},
+		{"heavy member", []string{"100", "101", "102"}, []string{"1", "1", "3"}, "0.05", "102", 3},
+		{"heavy outlier wins the median", []string{"100", "101", "200"}, []string{"1", "1", "3"}, "0.05", "200", 1},
+		{"no tolerance", []string{"100", "101", "200"}, []string{"1", "1", "1"}, "0", "101", 3},
+		{"light outlier trimmed", []string{"100", "101", "102", "200"}, []string{"2", "2", "2", "1"}, "0.05", "101", 3},
+		{"zero weights not counted", []string{"100", "101", "102", "103"}, []string{"1", "0", "0", "1"}, "0.05", "101.5", 2},
+		{"zero weights not counted without tolerance", []string{"100", "101", "102"}, []string{"1", "0", "0"}, "0", "100", 1},
+		{"zero weight outlier not counted", []string{"100", "101", "200"}, []string{"1", "1", "0"}, "0.05", "100.5", 2},
+		{"all zero weights", []string{"100", "101"}, []string{"0", "0"}, "0.05", "0", 0},
+	} {
+		price, count := WeightedMedianConsensus(providedPrices(c.prices, c.weights...), decimal.RequireFromString(c.tolerance))
+		assert.Equal(t, c.price, price.String(), c.name)
+		assert.Equal(t, c.count, count, c.name)
+	}
+}
@@ -0,0 +1,17 @@
+package compound
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// SettleFlashLoanDefault split the due of the defaulted flash loan covered by the seized collaterals into the principal and the fee repaid,
+// the due not covered is written off against the reserves first, the remainder is socialized to the suppliers
+func SettleFlashLoanDefault(amount, fee, covered, reserves decimal.Decimal) (principal, paidFee, fromReserves, socialized decimal.Decimal) {
+	covered = decimal.Max(covered, decimal.Zero)
+	principal = decimal.Min(covered, amount)
+	paidFee = decimal.Min(covered.Sub(principal), fee)
+
+	uncovered := decimal.Max(amount.Add(fee).Sub(covered), decimal.Zero)
+	fromReserves, socialized = WriteOffBadDebt(uncovered, reserves)
+	return
+}
@@ -0,0 +1,57 @@
+package compound
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSettleFlashLoanDefault(t *testing.T) {
+	d := decimal.RequireFromString
+
+	for _, c := range []struct {
+		name                                         string
+		amount, fee, covered, reserves               string
+		principal, paidFee, fromReserves, socialized string
+	}{
+		{"fully covered", "100", "1", "101", "5", "100", "1", "0", "0"},
+		{"fee not covered", "100", "1", "100.5", "5", "100", "0.5", "0.5", "0"},
+		{"principal not covered", "100", "1", "60", "5", "60", "0", "5", "36"},
+		{"not covered", "100", "1", "0", "0", "0", "0", "0", "101"},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			principal, paidFee, fromReserves, socialized := SettleFlashLoanDefault(d(c.amount), d(c.fee), d(c.covered), d(c.reserves))
+			assert.Equal(t, c.principal, principal.String())
+			assert.Equal(t, c.paidFee, paidFee.String())
+			assert.Equal(t, c.fromReserves, fromReserves.String())
+			assert.Equal(t, c.socialized, socialized.String())
+		})
+	}
+}
+
+func TestSettleFlashLoanDefaultExchangeRate(t *testing.T) {
+	d := decimal.RequireFromString
+
+	var (
+		cash          = d("900")
+		flashLoans    = d("100")
+		borrows       = d("500")
+		reserves      = d("10")
+		ctokens       = d("7000")
+		reserveFactor = d("0.1")
+		amount        = d("100")
+		fee           = d("1")
+	)
+
+	before := GetExchangeRate(cash.Add(flashLoans), borrows, reserves, ctokens, decimal.Zero)
+
+	// the seized collaterals cover the due, the loan market is credited as if the loan were repaid
+	principal, paidFee, fromReserves, _ := SettleFlashLoanDefault(amount, fee, amount.Add(fee), reserves)
+	cash = cash.Add(principal).Add(paidFee)
+	reserves = reserves.Sub(fromReserves).Add(paidFee.Mul(reserveFactor))
+	flashLoans = flashLoans.Sub(amount)
+
+	after := GetExchangeRate(cash.Add(flashLoans), borrows, reserves, ctokens, decimal.Zero)
+	assert.True(t, after.GreaterThanOrEqual(before), "exchange rate dropped from %s to %s", before, after)
+}
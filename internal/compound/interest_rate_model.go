@@ -59,9 +59,7 @@ func GetBorrowRatePerBlock(utilizationRate, baseRate, multiplier, jumpMultiplier
 // GetSupplyRatePerBlock supply rate per block
 func GetSupplyRatePerBlock(utilizationRate, baseRate, multiplier, jumpMultiplier, kink, reserveFactor decimal.Decimal) decimal.Decimal {
 	borrowRate := GetBorrowRatePerBlock(utilizationRate, baseRate, multiplier, jumpMultiplier, kink)
-	oneMinusReserveFactor := decimal.NewFromInt(1).Sub(reserveFactor)
-	rateToPool := borrowRate.Mul(oneMinusReserveFactor)
-	return utilizationRate.Mul(rateToPool).Truncate(MaxPricision)
+	return supplyRatePerBlock(borrowRate, utilizationRate, reserveFactor)
 }
 
 // GetBaseRatePerBlock base rate per block
@@ -78,3 +76,97 @@ func GetMultiplierPerBlock(multiplier decimal.Decimal) decimal.Decimal {
 func GetJumpMultiplierPerBlock(jumpMultiplier decimal.Decimal) decimal.Decimal {
 	return jumpMultiplier.Div(BlocksPerYear).Truncate(MaxPricision)
 }
+
+// InterestRateModel calculates the borrow and supply rates per block from the utilization rate
+type InterestRateModel interface {
+	// BorrowRatePerBlock borrow rate per block
+	BorrowRatePerBlock(utilizationRate decimal.Decimal) decimal.Decimal
+	// SupplyRatePerBlock supply rate per block
+	SupplyRatePerBlock(utilizationRate, reserveFactor decimal.Decimal) decimal.Decimal
+}
+
+// supply_rate = utilization_rate * borrow_rate * (1 - reserve_factor)
+func supplyRatePerBlock(borrowRate, utilizationRate, reserveFactor decimal.Decimal) decimal.Decimal {
+	oneMinusReserveFactor := decimal.NewFromInt(1).Sub(reserveFactor)
+	rateToPool := borrowRate.Mul(oneMinusReserveFactor)
+	return utilizationRate.Mul(rateToPool).Truncate(MaxPricision)
+}
+
+// JumpRateModel the rate grows with multiplier until kink, and with jump multiplier after kink
+type JumpRateModel struct {
+	BaseRate       decimal.Decimal
+	Multiplier     decimal.Decimal
+	JumpMultiplier decimal.Decimal
+	Kink           decimal.Decimal
+}
+
+// BorrowRatePerBlock borrow rate per block
+func (m JumpRateModel) BorrowRatePerBlock(utilizationRate decimal.Decimal) decimal.Decimal {
+	return GetBorrowRatePerBlock(utilizationRate, m.BaseRate, m.Multiplier, m.JumpMultiplier, m.Kink)
+}
+
+// SupplyRatePerBlock supply rate per block
+func (m JumpRateModel) SupplyRatePerBlock(utilizationRate, reserveFactor decimal.Decimal) decimal.Decimal {
+	return supplyRatePerBlock(m.BorrowRatePerBlock(utilizationRate), utilizationRate, reserveFactor)
+}
+
+// LinearRateModel borrow_rate = base_rate + utilization_rate * multiplier
+type LinearRateModel struct {
+	BaseRate   decimal.Decimal
+	Multiplier decimal.Decimal
+}
+
+// BorrowRatePerBlock borrow rate per block
+func (m LinearRateModel) BorrowRatePerBlock(utilizationRate decimal.Decimal) decimal.Decimal {
+	return utilizationRate.Mul(GetMultiplierPerBlock(m.Multiplier)).Add(GetBaseRatePerBlock(m.BaseRate)).Truncate(MaxPricision)
+}
+
+// SupplyRatePerBlock supply rate per block
+func (m LinearRateModel) SupplyRatePerBlock(utilizationRate, reserveFactor decimal.Decimal) decimal.Decimal {
+	return supplyRatePerBlock(m.BorrowRatePerBlock(utilizationRate), utilizationRate, reserveFactor)
+}
+
+// FixedRateModel borrow_rate = base_rate, no matter what the utilization rate is
+type FixedRateModel struct {
+	BaseRate decimal.Decimal
+}
+
+// BorrowRatePerBlock borrow rate per block
+func (m FixedRateModel) BorrowRatePerBlock(utilizationRate decimal.Decimal) decimal.Decimal {
+	return GetBaseRatePerBlock(m.BaseRate)
+}
+
+// SupplyRatePerBlock supply rate per block
+func (m FixedRateModel) SupplyRatePerBlock(utilizationRate, reserveFactor decimal.Decimal) decimal.Decimal {
+	return supplyRatePerBlock(m.BorrowRatePerBlock(utilizationRate), utilizationRate, reserveFactor)
+}
+
+// DualKinkRateModel jump rate model with a second kink, mostly used by stable coins
+//
+// 	the rate grows with multiplier until kink, with jump multiplier until second kink,
+// 	and with second jump multiplier after second kink
+type DualKinkRateModel struct {
+	BaseRate             decimal.Decimal
+	Multiplier           decimal.Decimal
+	JumpMultiplier       decimal.Decimal
+	Kink                 decimal.Decimal
+	SecondJumpMultiplier decimal.Decimal
+	SecondKink           decimal.Decimal
+}
+
+// BorrowRatePerBlock borrow rate per block
+func (m DualKinkRateModel) BorrowRatePerBlock(utilizationRate decimal.Decimal) decimal.Decimal {
+	if m.SecondKink.LessThanOrEqual(m.Kink) ||
+		utilizationRate.LessThanOrEqual(m.SecondKink) {
+		return GetBorrowRatePerBlock(utilizationRate, m.BaseRate, m.Multiplier, m.JumpMultiplier, m.Kink)
+	}
+
+	secondKinkRate := GetBorrowRatePerBlock(m.SecondKink, m.BaseRate, m.Multiplier, m.JumpMultiplier, m.Kink)
+	excessUtilRate := utilizationRate.Sub(m.SecondKink)
+	return excessUtilRate.Mul(GetJumpMultiplierPerBlock(m.SecondJumpMultiplier)).Add(secondKinkRate).Truncate(MaxPricision)
+}
+
+// SupplyRatePerBlock supply rate per block
+func (m DualKinkRateModel) SupplyRatePerBlock(utilizationRate, reserveFactor decimal.Decimal) decimal.Decimal {
+	return supplyRatePerBlock(m.BorrowRatePerBlock(utilizationRate), utilizationRate, reserveFactor)
+}
@@ -0,0 +1,75 @@
+package compound
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterestRateModels(t *testing.T) {
+	d := decimal.RequireFromString
+
+	// 2.1024 / BlocksPerYear = 0.000001 per block
+	jump := JumpRateModel{
+		BaseRate:       d("2.1024"),
+		Multiplier:     d("21.024"),
+		JumpMultiplier: d("210.24"),
+		Kink:           d("0.8"),
+	}
+
+	dualKink := DualKinkRateModel{
+		BaseRate:             jump.BaseRate,
+		Multiplier:           jump.Multiplier,
+		JumpMultiplier:       jump.JumpMultiplier,
+		Kink:                 jump.Kink,
+		SecondJumpMultiplier: d("2102.4"),
+		SecondKink:           d("0.9"),
+	}
+
+	noSecondKink := dualKink
+	noSecondKink.SecondKink = decimal.Zero
+
+	tests := []struct {
+		name  string
+		model InterestRateModel
+		util  string
+		rate  string
+	}{
+		{"jump below kink", jump, "0.5", "0.000006"},
+		{"jump at kink", jump, "0.8", "0.000009"},
+		{"jump above kink", jump, "0.9", "0.000019"},
+		{"linear", LinearRateModel{BaseRate: jump.BaseRate, Multiplier: jump.Multiplier}, "0.9", "0.00001"},
+		{"fixed zero utilization", FixedRateModel{BaseRate: jump.BaseRate}, "0", "0.000001"},
+		{"fixed full utilization", FixedRateModel{BaseRate: jump.BaseRate}, "1", "0.000001"},
+		{"dual kink below kink", dualKink, "0.5", "0.000006"},
+		{"dual kink between kinks", dualKink, "0.85", "0.000014"},
+		{"dual kink above second kink", dualKink, "0.95", "0.000069"},
+		{"dual kink without second kink", noSecondKink, "0.95", "0.000024"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rate := test.model.BorrowRatePerBlock(d(test.util))
+			assert.Equal(t, d(test.rate).String(), rate.String())
+		})
+	}
+}
+
+func TestSupplyRatePerBlock(t *testing.T) {
+	d := decimal.RequireFromString
+
+	model := LinearRateModel{
+		BaseRate:   d("2.1024"),
+		Multiplier: d("21.024"),
+	}
+
+	// borrow_rate = 0.000006, supply_rate = 0.5 * 0.000006 * (1 - 0.1)
+	rate := model.SupplyRatePerBlock(d("0.5"), d("0.1"))
+	assert.Equal(t, "0.0000027", rate.String())
+
+	// the same as the legacy function
+	jump := JumpRateModel{BaseRate: d("0.025"), Multiplier: d("0.3"), JumpMultiplier: d("0.5"), Kink: d("0.7")}
+	legacy := GetSupplyRatePerBlock(d("0.8"), d("0.025"), d("0.3"), d("0.5"), d("0.7"), d("0.1"))
+	assert.Equal(t, legacy.String(), jump.SupplyRatePerBlock(d("0.8"), d("0.1")).String())
+}
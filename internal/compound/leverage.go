@@ -0,0 +1,38 @@
+package compound
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// LeverageMaxLoops the max borrow and re-supply loops of the leveraged position
+const LeverageMaxLoops = 10
+
+// Leverage loop borrowing against the last supplied amount and re-supplying the borrowed amount,
+// until the total supplied amount reaches amount * leverage or the max loops,
+// returns the total borrowed amount and the loops
+//
+// 	borrow_n = supply_(n-1) * collateral_factor
+func Leverage(amount, collateralFactor, leverage decimal.Decimal, maxLoops int) (decimal.Decimal, int) {
+	borrowed := decimal.Zero
+	if amount.LessThanOrEqual(decimal.Zero) ||
+		collateralFactor.LessThanOrEqual(decimal.Zero) ||
+		leverage.LessThanOrEqual(decimal.NewFromInt(1)) {
+		return borrowed, 0
+	}
+
+	target := amount.Mul(leverage.Sub(decimal.NewFromInt(1))).Truncate(8)
+	supplied := amount
+	loops := 0
+	for loops < maxLoops && borrowed.LessThan(target) {
+		borrow := decimal.Min(supplied.Mul(collateralFactor), target.Sub(borrowed)).Truncate(8)
+		if borrow.LessThanOrEqual(decimal.Zero) {
+			break
+		}
+
+		borrowed = borrowed.Add(borrow)
+		supplied = borrow
+		loops++
+	}
+
+	return borrowed, loops
+}
@@ -0,0 +1,31 @@
+package compound
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeverage(t *testing.T) {
+	d := decimal.RequireFromString
+
+	for _, c := range []struct {
+		amount, collateralFactor, leverage string
+		maxLoops                           int
+		borrowed                           string
+		loops                              int
+	}{
+		{"100", "0.75", "1", 10, "0", 0},
+		{"100", "0", "2", 10, "0", 0},
+		{"0", "0.75", "2", 10, "0", 0},
+		{"100", "0.75", "1.5", 10, "50", 1},
+		{"100", "0.75", "2", 10, "100", 2},
+		{"100", "0.5", "2", 3, "87.5", 3},
+		{"100", "0.5", "10", 1, "50", 1},
+	} {
+		borrowed, loops := Leverage(d(c.amount), d(c.collateralFactor), d(c.leverage), c.maxLoops)
+		assert.Equal(t, c.borrowed, borrowed.String(), c.leverage)
+		assert.Equal(t, c.loops, loops, c.leverage)
+	}
+}
@@ -0,0 +1,22 @@
+package compound
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// LiquidationAuctionDiscount the discount of the seized collateral at t, grows linearly from zero
+// when the position became liquidatable at startAt to maxDiscount after the window
+func LiquidationAuctionDiscount(maxDiscount decimal.Decimal, startAt, t time.Time, window time.Duration) decimal.Decimal {
+	if window <= 0 || !t.Before(startAt.Add(window)) {
+		return maxDiscount
+	}
+
+	if !t.After(startAt) {
+		return decimal.Zero
+	}
+
+	elapsed := decimal.NewFromInt(int64(t.Sub(startAt)))
+	return maxDiscount.Mul(elapsed).Div(decimal.NewFromInt(int64(window))).Truncate(16)
+}
@@ -0,0 +1,31 @@
+package compound
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLiquidationAuctionDiscount(t *testing.T) {
+	d := decimal.RequireFromString
+	startAt := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, c := range []struct {
+		elapsed  time.Duration
+		window   time.Duration
+		discount string
+	}{
+		{-time.Minute, time.Hour, "0"},
+		{0, time.Hour, "0"},
+		{15 * time.Minute, time.Hour, "0.025"},
+		{30 * time.Minute, time.Hour, "0.05"},
+		{time.Hour, time.Hour, "0.1"},
+		{2 * time.Hour, time.Hour, "0.1"},
+		{0, 0, "0.1"},
+	} {
+		discount := LiquidationAuctionDiscount(d("0.1"), startAt, startAt.Add(c.elapsed), c.window)
+		assert.Equal(t, c.discount, discount.String(), c.elapsed.String())
+	}
+}
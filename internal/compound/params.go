@@ -0,0 +1,33 @@
+package compound
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// KinkMax max of the kink of the utilization rate
+var KinkMax = decimal.NewFromInt(1)
+
+// IsValidCloseFactor close factor in (CloseFactorMin, CloseFactorMax]
+func IsValidCloseFactor(closeFactor decimal.Decimal) bool {
+	return closeFactor.GreaterThan(CloseFactorMin) && closeFactor.LessThanOrEqual(CloseFactorMax)
+}
+
+// IsValidCollateralFactor collateral factor in [0, CollateralFactorMax]
+func IsValidCollateralFactor(collateralFactor decimal.Decimal) bool {
+	return collateralFactor.GreaterThanOrEqual(decimal.Zero) && collateralFactor.LessThanOrEqual(CollateralFactorMax)
+}
+
+// IsValidLiquidationIncentive liquidation incentive in [LiquidationIncentiveMin, LiquidationIncentiveMax]
+func IsValidLiquidationIncentive(liquidationIncentive decimal.Decimal) bool {
+	return liquidationIncentive.GreaterThanOrEqual(LiquidationIncentiveMin) && liquidationIncentive.LessThanOrEqual(LiquidationIncentiveMax)
+}
+
+// IsValidKink kink in [0, KinkMax]
+func IsValidKink(kink decimal.Decimal) bool {
+	return kink.GreaterThanOrEqual(decimal.Zero) && kink.LessThanOrEqual(KinkMax)
+}
+
+// IsValidRatio ratio in [0, 1), such as the reserve factor and the rates per year
+func IsValidRatio(ratio decimal.Decimal) bool {
+	return ratio.GreaterThanOrEqual(decimal.Zero) && ratio.LessThan(decimal.NewFromInt(1))
+}
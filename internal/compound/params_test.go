@@ -0,0 +1,41 @@
+package compound
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParamBounds(t *testing.T) {
+	d := decimal.RequireFromString
+
+	for _, c := range []struct {
+		name  string
+		valid func(decimal.Decimal) bool
+		value string
+		want  bool
+	}{
+		{"close factor at min", IsValidCloseFactor, "0.05", false},
+		{"close factor above min", IsValidCloseFactor, "0.06", true},
+		{"close factor at max", IsValidCloseFactor, "0.9", true},
+		{"close factor above max", IsValidCloseFactor, "0.91", false},
+		{"collateral factor zero", IsValidCollateralFactor, "0", true},
+		{"collateral factor at max", IsValidCollateralFactor, "0.9", true},
+		{"collateral factor above max", IsValidCollateralFactor, "0.95", false},
+		{"collateral factor negative", IsValidCollateralFactor, "-0.1", false},
+		{"liquidation incentive below min", IsValidLiquidationIncentive, "0.005", false},
+		{"liquidation incentive at min", IsValidLiquidationIncentive, "0.01", true},
+		{"liquidation incentive at max", IsValidLiquidationIncentive, "0.9", true},
+		{"liquidation incentive above max", IsValidLiquidationIncentive, "1", false},
+		{"kink zero", IsValidKink, "0", true},
+		{"kink at max", IsValidKink, "1", true},
+		{"kink above max", IsValidKink, "1.01", false},
+		{"kink negative", IsValidKink, "-0.5", false},
+		{"ratio zero", IsValidRatio, "0", true},
+		{"ratio one", IsValidRatio, "1", false},
+		{"ratio negative", IsValidRatio, "-0.01", false},
+	} {
+		assert.Equal(t, c.want, c.valid(d(c.value)), c.name)
+	}
+}
@@ -0,0 +1,55 @@
+package compound
+
+import (
+	"sort"
+
+	"github.com/shopspring/decimal"
+)
+
+// MedianPrice the median of the prices, the average of the two middle prices if the count is even
+func MedianPrice(prices []decimal.Decimal) decimal.Decimal {
+	if len(prices) == 0 {
+		return decimal.Zero
+	}
+
+	sorted := make([]decimal.Decimal, len(prices))
+	copy(sorted, prices)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].LessThan(sorted[j])
+	})
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[mid]
+	}
+
+	return sorted[mid-1].Add(sorted[mid]).Div(decimal.NewFromInt(2)).Truncate(8)
+}
+
+// AggregatePrices drop the outliers deviating from the median more than maxDeviation (ratio),
+// returns the median of the remaining prices and the count of them, no price is dropped if maxDeviation is not positive
+func AggregatePrices(prices []decimal.Decimal, maxDeviation decimal.Decimal) (decimal.Decimal, int) {
+	median := MedianPrice(prices)
+	if median.LessThanOrEqual(decimal.Zero) || maxDeviation.LessThanOrEqual(decimal.Zero) {
+		return median, len(prices)
+	}
+
+	valid := make([]decimal.Decimal, 0, len(prices))
+	for _, p := range prices {
+		if p.Sub(median).Abs().Div(median).LessThanOrEqual(maxDeviation) {
+			valid = append(valid, p)
+		}
+	}
+
+	return MedianPrice(valid), len(valid)
+}
+
+// PriceChangeExceeded is the change from the price to the new price more than maxChange (ratio),
+// never exceeded if maxChange or the price is not positive
+func PriceChangeExceeded(price, newPrice, maxChange decimal.Decimal) bool {
+	if maxChange.LessThanOrEqual(decimal.Zero) || price.LessThanOrEqual(decimal.Zero) {
+		return false
+	}
+
+	return newPrice.Sub(price).Abs().Div(price).GreaterThan(maxChange)
+}
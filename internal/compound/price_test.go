@@ -0,0 +1,77 @@
+package compound
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func decimals(values ...string) []decimal.Decimal {
+	ds := make([]decimal.Decimal, 0, len(values))
+	for _, v := range values {
+		ds = append(ds, decimal.RequireFromString(v))
+	}
+
+	return ds
+}
+
+func TestMedianPrice(t *testing.T) {
+	for _, c := range []struct {
+		prices []string
+		median string
+	}{
+		{nil, "0"},
+		{[]string{"10"}, "10"},
+		{[]string{"10", "12"}, "11"},
+		{[]string{"12", "10", "11"}, "11"},
+		{[]string{"13", "10", "12", "11"}, "11.5"},
+		{[]string{"1", "2"}, "1.5"},
+		{[]string{"1", "1.00000001"}, "1"},
+	} {
+		assert.Equal(t, c.median, MedianPrice(decimals(c.prices...)).String(), c.prices)
+	}
+}
+
+func TestAggregatePrices(t *testing.T) {
+	d := decimal.RequireFromString
+
+	for _, c := range []struct {
+		prices       []string
+		maxDeviation string
+		price        string
+		count        int
+	}{
+		{nil, "0.05", "0", 0},
+		{[]string{"100"}, "0.05", "100", 1},
+		{[]string{"100", "101", "150"}, "0", "101", 3},
+		{[]string{"100", "101", "150"}, "0.05", "100.5", 2},
+		{[]string{"100", "101", "102", "50", "150"}, "0.05", "101", 3},
+		{[]string{"100", "105", "110"}, "0.05", "105", 3},
+		{[]string{"100", "200"}, "0.1", "0", 0},
+	} {
+		price, count := AggregatePrices(decimals(c.prices...), d(c.maxDeviation))
+		assert.Equal(t, c.price, price.String(), c.prices)
+		assert.Equal(t, c.count, count, c.prices)
+	}
+}
+
+func TestPriceChangeExceeded(t *testing.T) {
+	d := decimal.RequireFromString
+	for _, c := range []struct {
+		price     string
+		newPrice  string
+		maxChange string
+		exceeded  bool
+	}{
+		{"100", "110", "0.1", false},
+		{"100", "90", "0.1", false},
+		{"100", "110.01", "0.1", true},
+		{"100", "89.99", "0.1", true},
+		{"100", "1000", "0", false},
+		{"0", "1000", "0.1", false},
+	} {
+		exceeded := PriceChangeExceeded(d(c.price), d(c.newPrice), d(c.maxChange))
+		assert.Equal(t, c.exceeded, exceeded, c.price+"->"+c.newPrice)
+	}
+}
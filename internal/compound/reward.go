@@ -0,0 +1,26 @@
+package compound
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// RewardIndexDelta the increase of the market reward index in the blocks
+// delta = speed * blocks / total
+func RewardIndexDelta(speed decimal.Decimal, blockDelta int64, total decimal.Decimal) decimal.Decimal {
+	if speed.LessThanOrEqual(decimal.Zero) || blockDelta <= 0 || total.LessThanOrEqual(decimal.Zero) {
+		return decimal.Zero
+	}
+
+	return speed.Mul(decimal.NewFromInt(blockDelta)).Div(total).Truncate(MaxPricision)
+}
+
+// RewardAccrued the rewards accrued by the user since the user index
+// accrued = amount * (market_index - user_index)
+func RewardAccrued(amount, marketIndex, userIndex decimal.Decimal) decimal.Decimal {
+	delta := marketIndex.Sub(userIndex)
+	if amount.LessThanOrEqual(decimal.Zero) || delta.LessThanOrEqual(decimal.Zero) {
+		return decimal.Zero
+	}
+
+	return amount.Mul(delta).Truncate(MaxPricision)
+}
@@ -0,0 +1,25 @@
+package compound
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRewardIndexDelta(t *testing.T) {
+	d := decimal.RequireFromString
+
+	assert.Equal(t, "0.5", RewardIndexDelta(d("1"), 50, d("100")).String())
+	assert.True(t, RewardIndexDelta(d("0"), 50, d("100")).IsZero())
+	assert.True(t, RewardIndexDelta(d("1"), 0, d("100")).IsZero())
+	assert.True(t, RewardIndexDelta(d("1"), 50, d("0")).IsZero())
+}
+
+func TestRewardAccrued(t *testing.T) {
+	d := decimal.RequireFromString
+
+	assert.Equal(t, "25", RewardAccrued(d("50"), d("0.8"), d("0.3")).String())
+	assert.True(t, RewardAccrued(d("0"), d("0.8"), d("0.3")).IsZero())
+	assert.True(t, RewardAccrued(d("50"), d("0.3"), d("0.3")).IsZero())
+}
@@ -0,0 +1,27 @@
+package compound
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// SelfLiquidate seize the own collateral at the oracle price without incentive to repay the borrow,
+// the repay amount is capped by the collateral
+//
+// 	seized = repay * borrow_price / supply_price
+func SelfLiquidate(repayAmount, borrowPrice, collateral, supplyPrice decimal.Decimal) (repay, seized decimal.Decimal) {
+	if repayAmount.LessThanOrEqual(decimal.Zero) ||
+		borrowPrice.LessThanOrEqual(decimal.Zero) ||
+		collateral.LessThanOrEqual(decimal.Zero) ||
+		supplyPrice.LessThanOrEqual(decimal.Zero) {
+		return decimal.Zero, decimal.Zero
+	}
+
+	repay = repayAmount.Truncate(8)
+	seized = repay.Mul(borrowPrice).Div(supplyPrice).Truncate(8)
+	if seized.GreaterThan(collateral) {
+		seized = collateral.Truncate(8)
+		repay = seized.Mul(supplyPrice).Div(borrowPrice).Truncate(8)
+	}
+
+	return repay, seized
+}
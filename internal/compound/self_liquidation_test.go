@@ -0,0 +1,29 @@
+package compound
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelfLiquidate(t *testing.T) {
+	d := decimal.RequireFromString
+
+	for _, c := range []struct {
+		repayAmount, borrowPrice, collateral, supplyPrice string
+		repay, seized                                     string
+	}{
+		{"0", "1", "1", "40000", "0", "0"},
+		{"100", "1", "1", "0", "0", "0"},
+		{"4000", "1", "1", "40000", "4000", "0.1"},
+		{"40000", "1", "1", "40000", "40000", "1"},
+		{"50000", "1", "1", "40000", "40000", "1"},
+		{"1", "3", "1", "7", "1", "0.42857142"},
+		{"10", "3", "1", "7", "2.33333333", "1"},
+	} {
+		repay, seized := SelfLiquidate(d(c.repayAmount), d(c.borrowPrice), d(c.collateral), d(c.supplyPrice))
+		assert.Equal(t, c.repay, repay.String(), c.repayAmount)
+		assert.Equal(t, c.seized, seized.String(), c.repayAmount)
+	}
+}
@@ -0,0 +1,72 @@
+package compound
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+var (
+	// StableRatePremium the stable borrow rate is higher than the variable borrow rate by this premium, per year
+	StableRatePremium = decimal.NewFromFloat(0.02)
+	// StableRateRebalanceUtilization the stable borrows could be rebalanced when the utilization rate exceeds this value
+	StableRateRebalanceUtilization = decimal.NewFromFloat(0.95)
+)
+
+// GetStableRatePerBlock stable borrow rate per block
+// stable_rate = borrow_rate + stable_rate_premium
+func GetStableRatePerBlock(borrowRatePerBlock decimal.Decimal) decimal.Decimal {
+	return borrowRatePerBlock.Add(StableRatePremium.Div(BlocksPerYear)).Truncate(MaxPricision)
+}
+
+// StableRateRebalanceAllowed the stable rate is rebalanced to the current stable rate
+// if the utilization rate exceeds the threshold and the stable rate is lower than the variable borrow rate
+func StableRateRebalanceAllowed(utilizationRate, stableRate, borrowRatePerBlock decimal.Decimal) bool {
+	return utilizationRate.GreaterThan(StableRateRebalanceUtilization) &&
+		stableRate.LessThan(borrowRatePerBlock)
+}
+
+// AddStableBorrows add the borrows at the stable rate, return the new total stable borrows and average stable rate
+// avg_rate = (avg_rate * total + rate * amount) / (total + amount)
+func AddStableBorrows(total, avgRate, amount, rate decimal.Decimal) (decimal.Decimal, decimal.Decimal) {
+	newTotal := total.Add(amount)
+	if newTotal.LessThanOrEqual(decimal.Zero) {
+		return decimal.Zero, decimal.Zero
+	}
+
+	newAvgRate := avgRate.Mul(total).Add(rate.Mul(amount)).Div(newTotal)
+	return newTotal.Truncate(MaxPricision), newAvgRate.Truncate(MaxPricision)
+}
+
+// UpdateStableBorrows replace the stable borrow of the balance, principal and rate with the new principal at the new rate,
+// return the new total stable borrows and average stable rate
+//
+// the stable borrows accrue simple interest on their principals, so that the market keeps
+//
+// 	total_stable_borrows = sum(balance)
+// 	total_stable_borrows * avg_rate = sum(principal * rate)
+func UpdateStableBorrows(total, avgRate, balance, principal, rate, newPrincipal, newRate decimal.Decimal) (decimal.Decimal, decimal.Decimal) {
+	newTotal := total.Sub(balance).Add(newPrincipal)
+	if newTotal.LessThanOrEqual(decimal.Zero) {
+		return decimal.Zero, decimal.Zero
+	}
+
+	interest := total.Mul(avgRate).Sub(principal.Mul(rate)).Add(newPrincipal.Mul(newRate))
+	newAvgRate := decimal.Max(interest, decimal.Zero).Div(newTotal)
+	return newTotal.Truncate(MaxPricision), newAvgRate.Truncate(MaxPricision)
+}
+
+// AccrueStableBorrows accrue the simple interest of the stable borrows over the blocks, the same as the stable borrow balances,
+// return the new total stable borrows, average stable rate and the interest accrued
+//
+// 	interest = total_stable_borrows * avg_rate * blocks
+//
+// the interest per block is kept, so the average rate is diluted by the interest accrued
+func AccrueStableBorrows(total, avgRate decimal.Decimal, blocks int64) (decimal.Decimal, decimal.Decimal, decimal.Decimal) {
+	if blocks <= 0 || total.LessThanOrEqual(decimal.Zero) {
+		return total, avgRate, decimal.Zero
+	}
+
+	interestPerBlock := total.Mul(avgRate)
+	interest := interestPerBlock.Mul(decimal.NewFromInt(blocks))
+	newTotal := total.Add(interest)
+	return newTotal.Truncate(MaxPricision), interestPerBlock.Div(newTotal).Truncate(MaxPricision), interest
+}
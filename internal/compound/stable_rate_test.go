@@ -0,0 +1,83 @@
+package compound
+
+import (
+	"context"
+	"testing"
+
+	"compound/core"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStableBorrows(t *testing.T) {
+	d := decimal.RequireFromString
+
+	total, avgRate := AddStableBorrows(decimal.Zero, decimal.Zero, d("100"), d("0.00001"))
+	assert.Equal(t, "100", total.String())
+	assert.Equal(t, "0.00001", avgRate.String())
+
+	total, avgRate = AddStableBorrows(total, avgRate, d("100"), d("0.00003"))
+	assert.Equal(t, "200", total.String())
+	assert.Equal(t, "0.00002", avgRate.String())
+
+	total, avgRate = UpdateStableBorrows(total, avgRate, d("100"), d("100"), d("0.00003"), decimal.Zero, decimal.Zero)
+	assert.Equal(t, "100", total.String())
+	assert.Equal(t, "0.00001", avgRate.String())
+
+	total, avgRate = UpdateStableBorrows(total, avgRate, d("150"), d("100"), d("0.00001"), decimal.Zero, decimal.Zero)
+	assert.True(t, total.IsZero())
+	assert.True(t, avgRate.IsZero())
+}
+
+func TestStableBorrowsAccrual(t *testing.T) {
+	d := decimal.RequireFromString
+	ctx := context.Background()
+
+	market := &core.Market{}
+	accrue := func(block int64) {
+		market.TotalStableBorrows, market.AvgStableRate, _ = AccrueStableBorrows(market.TotalStableBorrows, market.AvgStableRate, block-market.BlockNumber)
+		market.BlockNumber = block
+	}
+
+	balance := func(borrow *core.Borrow) decimal.Decimal {
+		b, err := borrow.Balance(ctx, market)
+		assert.Nil(t, err)
+		return b
+	}
+
+	// borrow, repay or rebalance the stable borrow at the current block
+	update := func(borrow *core.Borrow, amount, rate decimal.Decimal) {
+		b := balance(borrow)
+		principal := b.Add(amount)
+		market.TotalStableBorrows, market.AvgStableRate = UpdateStableBorrows(market.TotalStableBorrows, market.AvgStableRate, b, borrow.Principal, borrow.StableRate, principal, rate)
+		borrow.Principal = principal
+		borrow.StableRate = rate
+		borrow.StableRateBlock = market.BlockNumber
+	}
+
+	alice := &core.Borrow{RateMode: core.BorrowRateModeStable}
+	bob := &core.Borrow{RateMode: core.BorrowRateModeStable}
+
+	update(alice, d("100"), d("0.00001"))
+	accrue(100)
+	update(bob, d("50"), d("0.00003"))
+	accrue(250)
+	update(alice, d("-30"), alice.StableRate)
+	accrue(400)
+	accrue(700)
+	update(bob, decimal.Zero, d("0.00002"))
+	accrue(1000)
+	accrue(5000)
+
+	sum := balance(alice).Add(balance(bob))
+	assert.True(t, market.TotalStableBorrows.Sub(sum).Abs().LessThan(d("0.000001")), "total %s, sum of balances %s", market.TotalStableBorrows, sum)
+}
+
+func TestStableRateRebalanceAllowed(t *testing.T) {
+	d := decimal.RequireFromString
+
+	assert.False(t, StableRateRebalanceAllowed(d("0.9"), d("0.00001"), d("0.00002")))
+	assert.False(t, StableRateRebalanceAllowed(d("0.96"), d("0.00003"), d("0.00002")))
+	assert.True(t, StableRateRebalanceAllowed(d("0.96"), d("0.00001"), d("0.00002")))
+}
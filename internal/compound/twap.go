@@ -0,0 +1,48 @@
+package compound
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// TWAP the time weighted average price between from and to, each price is weighted by the time it was in effect
+// until the next price passed, the prices must be in ascending order of passedAts.
+// Zero if no price was in effect in the window
+func TWAP(prices []decimal.Decimal, passedAts []time.Time, from, to time.Time) decimal.Decimal {
+	sum := decimal.Zero
+	weights := decimal.Zero
+	for i, price := range prices {
+		start := passedAts[i]
+		if start.Before(from) {
+			start = from
+		}
+
+		end := to
+		if i+1 < len(prices) && passedAts[i+1].Before(to) {
+			end = passedAts[i+1]
+		}
+
+		if !end.After(start) {
+			continue
+		}
+
+		weight := decimal.NewFromInt(int64(end.Sub(start) / time.Second))
+		sum = sum.Add(price.Mul(weight))
+		weights = weights.Add(weight)
+	}
+
+	if weights.IsZero() {
+		return decimal.Zero
+	}
+
+	return sum.Div(weights).Truncate(16)
+}
+
+// TWAPWindow the twap window of the current price passed at updatedAt, the window ends one price block after it,
+// as the current price is in effect for one block at least
+func TWAPWindow(updatedAt time.Time, window, block time.Duration) (from, to time.Time) {
+	to = updatedAt.Add(block)
+	from = to.Add(-window)
+	return from, to
+}
@@ -0,0 +1,75 @@
+package compound
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTWAP(t *testing.T) {
+	d := decimal.RequireFromString
+	from := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+
+	for _, c := range []struct {
+		name      string
+		prices    []string
+		passedAts []time.Duration
+		twap      string
+	}{
+		{"empty", nil, nil, "0"},
+		{"single", []string{"100"}, []time.Duration{0}, "100"},
+		{"passed at the end", []string{"100"}, []time.Duration{time.Hour}, "0"},
+		{"equal weights", []string{"100", "200"}, []time.Duration{0, 30 * time.Minute}, "150"},
+		{"spike at the end", []string{"100", "100", "1000"}, []time.Duration{0, 30 * time.Minute, 54 * time.Minute}, "190"},
+		{"started before the window", []string{"100", "200"}, []time.Duration{-time.Hour, 45 * time.Minute}, "125"},
+		{"latest price excluded", []string{"100", "200"}, []time.Duration{0, time.Hour}, "100"},
+	} {
+		prices := make([]decimal.Decimal, len(c.prices))
+		passedAts := make([]time.Time, len(c.passedAts))
+		for i := range c.prices {
+			prices[i] = d(c.prices[i])
+			passedAts[i] = from.Add(c.passedAts[i])
+		}
+
+		twap := TWAP(prices, passedAts, from, to)
+		assert.Equal(t, c.twap, twap.String(), c.name)
+	}
+}
+
+func TestTWAPWindow(t *testing.T) {
+	d := decimal.RequireFromString
+	updatedAt := time.Date(2021, 1, 1, 1, 0, 0, 0, time.UTC)
+	block := 10 * time.Minute
+
+	for _, c := range []struct {
+		name      string
+		window    time.Duration
+		prices    []string
+		passedAts []time.Duration
+		twap      string
+	}{
+		{"window shorter than a block", 5 * time.Minute, []string{"100", "200"}, []time.Duration{-10 * time.Minute, 0}, "200"},
+		{"window of a block", 10 * time.Minute, []string{"100", "200"}, []time.Duration{-10 * time.Minute, 0}, "200"},
+		{"latest price weighted", 20 * time.Minute, []string{"100", "200"}, []time.Duration{-10 * time.Minute, 0}, "150"},
+		{"price before the window", time.Hour, []string{"100", "200", "400"}, []time.Duration{-2 * time.Hour, -20 * time.Minute, 0}, "183.3333333333333333"},
+		{"only the price before the window", time.Hour, []string{"100"}, []time.Duration{-2 * time.Hour}, "100"},
+		{"spike at the end", time.Hour, []string{"100", "1000"}, []time.Duration{-time.Hour, 0}, "250"},
+	} {
+		prices := make([]decimal.Decimal, len(c.prices))
+		passedAts := make([]time.Time, len(c.passedAts))
+		for i := range c.prices {
+			prices[i] = d(c.prices[i])
+			passedAts[i] = updatedAt.Add(c.passedAts[i])
+		}
+
+		from, to := TWAPWindow(updatedAt, c.window, block)
+		assert.True(t, to.Equal(updatedAt.Add(block)), c.name)
+		assert.Equal(t, c.window, to.Sub(from), c.name)
+
+		twap := TWAP(prices, passedAts, from, to)
+		assert.Equal(t, c.twap, twap.String(), c.name)
+	}
+}
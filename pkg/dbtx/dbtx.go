@@ -0,0 +1,56 @@
+package dbtx
+
+import (
+	"context"
+
+	"github.com/fox-one/pkg/store/db"
+	"github.com/jinzhu/gorm"
+)
+
+type contextKey struct{}
+
+// WithContext bind the transaction to the context
+func WithContext(ctx context.Context, tx *db.DB) context.Context {
+	return context.WithValue(ctx, contextKey{}, tx)
+}
+
+// FromContext the transaction bound to the context
+func FromContext(ctx context.Context) (*db.DB, bool) {
+	tx, ok := ctx.Value(contextKey{}).(*db.DB)
+	return tx, ok
+}
+
+// View read through the transaction bound to the context to see the writes not committed yet, or the read db
+func View(ctx context.Context, db *db.DB) *gorm.DB {
+	if tx, ok := FromContext(ctx); ok {
+		return tx.Update()
+	}
+
+	return db.View()
+}
+
+// Update write through the transaction bound to the context, or the write db
+func Update(ctx context.Context, db *db.DB) *gorm.DB {
+	if tx, ok := FromContext(ctx); ok {
+		return tx.Update()
+	}
+
+	return db.Update()
+}
+
+// Savepoint run fn within a savepoint of the transaction, the writes of fn are rolled back if it returns an error
+func Savepoint(tx *db.DB, name string, fn func() error) error {
+	if err := tx.Update().Exec("SAVEPOINT " + name).Error; err != nil {
+		return err
+	}
+
+	if err := fn(); err != nil {
+		if e := tx.Update().Exec("ROLLBACK TO SAVEPOINT " + name).Error; e != nil {
+			return e
+		}
+
+		return err
+	}
+
+	return tx.Update().Exec("RELEASE SAVEPOINT " + name).Error
+}
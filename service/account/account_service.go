@@ -10,12 +10,14 @@ import (
 )
 
 type accountService struct {
-	marketStore   core.IMarketStore
-	supplyStore   core.ISupplyStore
-	borrowStore   core.IBorrowStore
-	priceService  core.IPriceOracleService
-	blockService  core.IBlockService
-	marketService core.IMarketService
+	marketStore    core.IMarketStore
+	supplyStore    core.ISupplyStore
+	borrowStore    core.IBorrowStore
+	flashLoanStore core.IFlashLoanStore
+	categoryStore  core.ICategoryStore
+	priceService   core.IPriceOracleService
+	blockService   core.IBlockService
+	marketService  core.IMarketService
 }
 
 // New new account service
@@ -23,26 +25,53 @@ func New(
 	marketStore core.IMarketStore,
 	supplyStore core.ISupplyStore,
 	borrowStore core.IBorrowStore,
+	flashLoanStore core.IFlashLoanStore,
+	categoryStore core.ICategoryStore,
 	priceSrv core.IPriceOracleService,
 	blockSrv core.IBlockService,
 	marketServie core.IMarketService,
 ) core.IAccountService {
 	return &accountService{
-		marketStore:   marketStore,
-		supplyStore:   supplyStore,
-		borrowStore:   borrowStore,
-		priceService:  priceSrv,
-		blockService:  blockSrv,
-		marketService: marketServie,
+		marketStore:    marketStore,
+		supplyStore:    supplyStore,
+		borrowStore:    borrowStore,
+		flashLoanStore: flashLoanStore,
+		categoryStore:  categoryStore,
+		priceService:   priceSrv,
+		blockService:   blockSrv,
+		marketService:  marketServie,
 	}
 }
 
 // CalculateAccountLiquidity calculate account liquidity
 //
-// 	supplyValue = supply.collaterals * market.exchange_rate * market.collateral_factor * market.price
-// 	borrowValue = borrow.Balance()
+// 	supplyValue = supply.collaterals * market.exchange_rate * market.collateral_factor * market.twap
+// 	borrowValue = borrow.Balance() + pending flash loans due
 // 	liquidity = total_supply_values - total_borrow_values
+//
+// the collateral factor of the e-mode category is used if the user is in e-mode,
+// the collaterals are valued at the twap of the market if the twap window is set and the borrows at the current price
 func (s *accountService) CalculateAccountLiquidity(ctx context.Context, userID string, blockNum int64) (decimal.Decimal, error) {
+	category, e := s.EModeCategory(ctx, userID)
+	if e != nil {
+		return decimal.Zero, e
+	}
+
+	return s.calculateAccountLiquidity(ctx, userID, category)
+}
+
+// CalculateBorrowLiquidity calculate account liquidity for borrowing from the market,
+// the e-mode only applies if the market sits in the same category
+func (s *accountService) CalculateBorrowLiquidity(ctx context.Context, userID string, blockNum int64, market *core.Market) (decimal.Decimal, error) {
+	category, e := s.EModeCategory(ctx, userID, market)
+	if e != nil {
+		return decimal.Zero, e
+	}
+
+	return s.calculateAccountLiquidity(ctx, userID, category)
+}
+
+func (s *accountService) calculateAccountLiquidity(ctx context.Context, userID string, category *core.Category) (decimal.Decimal, error) {
 	supplies, e := s.supplyStore.FindByUser(ctx, userID)
 	if e != nil {
 		return decimal.Zero, e
@@ -54,7 +83,7 @@ func (s *accountService) CalculateAccountLiquidity(ctx context.Context, userID s
 			continue
 		}
 
-		price, e := s.priceService.GetCurrentUnderlyingPrice(ctx, market)
+		price, e := s.priceService.GetCollateralPrice(ctx, market)
 		if e != nil {
 			continue
 		}
@@ -63,7 +92,13 @@ func (s *accountService) CalculateAccountLiquidity(ctx context.Context, userID s
 		if e != nil {
 			continue
 		}
-		value := supply.Collaterals.Mul(exchangeRate).Mul(market.CollateralFactor).Mul(price)
+
+		collateralFactor := market.CollateralFactor
+		if category != nil && collateralFactor.GreaterThan(decimal.Zero) {
+			collateralFactor = category.CollateralFactor
+		}
+
+		value := supply.Collaterals.Mul(exchangeRate).Mul(collateralFactor).Mul(price)
 		supplyValue = supplyValue.Add(value)
 	}
 
@@ -92,11 +127,89 @@ func (s *accountService) CalculateAccountLiquidity(ctx context.Context, userID s
 		borrowValue = borrowValue.Add(value)
 	}
 
+	// the pending flash loans are backed by the collaterals as well
+	loans, e := s.flashLoanStore.FindPendingByUser(ctx, userID)
+	if e != nil {
+		return decimal.Zero, e
+	}
+
+	for _, loan := range loans {
+		market, _, e := s.marketStore.Find(ctx, loan.AssetID)
+		if e != nil {
+			continue
+		}
+		price, e := s.priceService.GetCurrentUnderlyingPrice(ctx, market)
+		if e != nil {
+			continue
+		}
+
+		value := loan.Due().Mul(price)
+		borrowValue = borrowValue.Add(value)
+	}
+
 	liquidity := supplyValue.Sub(borrowValue)
 
 	return liquidity, nil
 }
 
+// PriceStale whether the price of any market in the liquidity of the user is stale at t,
+// the markets of the collaterals, the borrows and the pending flash loans are checked
+func (s *accountService) PriceStale(ctx context.Context, userID string, t time.Time) (bool, error) {
+	supplies, e := s.supplyStore.FindByUser(ctx, userID)
+	if e != nil {
+		return false, e
+	}
+
+	for _, supply := range supplies {
+		if supply.Collaterals.LessThanOrEqual(decimal.Zero) {
+			continue
+		}
+
+		market, _, e := s.marketStore.FindByCToken(ctx, supply.CTokenAssetID)
+		if e != nil {
+			return false, e
+		}
+
+		if market.IsPriceStale(t) {
+			return true, nil
+		}
+	}
+
+	borrows, e := s.borrowStore.FindByUser(ctx, userID)
+	if e != nil {
+		return false, e
+	}
+
+	assets := make([]string, 0, len(borrows))
+	for _, borrow := range borrows {
+		if borrow.Principal.GreaterThan(decimal.Zero) {
+			assets = append(assets, borrow.AssetID)
+		}
+	}
+
+	loans, e := s.flashLoanStore.FindPendingByUser(ctx, userID)
+	if e != nil {
+		return false, e
+	}
+
+	for _, loan := range loans {
+		assets = append(assets, loan.AssetID)
+	}
+
+	for _, assetID := range assets {
+		market, _, e := s.marketStore.Find(ctx, assetID)
+		if e != nil {
+			return false, e
+		}
+
+		if market.IsPriceStale(t) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
 // SeizeTokenAllowed
 //
 // check account liquidity
@@ -152,7 +265,11 @@ func (s *accountService) MaxSeize(ctx context.Context, supply *core.Supply, borr
 	if e != nil {
 		return decimal.Zero, e
 	}
-	seizePrice := supplyPrice.Sub(supplyPrice.Mul(supplyMarket.LiquidationIncentive))
+	liquidationIncentive, e := s.LiquidationIncentive(ctx, supply.UserID, supplyMarket)
+	if e != nil {
+		return decimal.Zero, e
+	}
+	seizePrice := supplyPrice.Sub(supplyPrice.Mul(liquidationIncentive))
 	seizeValue := maxSeize.Mul(seizePrice)
 	borrowValue := borrow.Principal.Mul(borrowPrice)
 	if seizeValue.GreaterThan(borrowValue) {
@@ -166,3 +283,169 @@ func (s *accountService) MaxSeize(ctx context.Context, supply *core.Supply, borr
 func (s *accountService) SeizeToken(ctx context.Context, supply *core.Supply, borrow *core.Borrow, repayAmount decimal.Decimal) (string, error) {
 	panic("implement me")
 }
+
+// IsolatedMarket the isolated collateral market pledged by the user, nil if not in isolation mode
+func (s *accountService) IsolatedMarket(ctx context.Context, userID string) (*core.Market, error) {
+	supplies, e := s.supplyStore.FindByUser(ctx, userID)
+	if e != nil {
+		return nil, e
+	}
+
+	for _, supply := range supplies {
+		if supply.Collaterals.LessThanOrEqual(decimal.Zero) {
+			continue
+		}
+
+		market, _, e := s.marketStore.FindByCToken(ctx, supply.CTokenAssetID)
+		if e != nil {
+			return nil, e
+		}
+
+		if market.IsIsolated() {
+			return market, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// IsolatedDebt the value of the outstanding borrows of the users pledging the isolated collateral,
+// recomputed from the borrow balances at the current prices to follow the interests and the prices
+func (s *accountService) IsolatedDebt(ctx context.Context, market *core.Market) (decimal.Decimal, error) {
+	supplies, e := s.supplyStore.FindByCTokenAssetID(ctx, market.CTokenAssetID)
+	if e != nil {
+		return decimal.Zero, e
+	}
+
+	debt := decimal.Zero
+	for _, supply := range supplies {
+		if supply.Collaterals.LessThanOrEqual(decimal.Zero) {
+			continue
+		}
+
+		borrows, e := s.borrowStore.FindByUser(ctx, supply.UserID)
+		if e != nil {
+			return decimal.Zero, e
+		}
+
+		for _, borrow := range borrows {
+			if borrow.Principal.LessThanOrEqual(decimal.Zero) {
+				continue
+			}
+
+			borrowMarket, _, e := s.marketStore.Find(ctx, borrow.AssetID)
+			if e != nil {
+				return decimal.Zero, e
+			}
+
+			price, e := s.priceService.GetCurrentUnderlyingPrice(ctx, borrowMarket)
+			if e != nil {
+				return decimal.Zero, e
+			}
+
+			balance, e := borrow.Balance(ctx, borrowMarket)
+			if e != nil {
+				return decimal.Zero, e
+			}
+
+			debt = debt.Add(balance.Mul(price))
+		}
+	}
+
+	return debt, nil
+}
+
+// EModeCategory the e-mode category of the user, nil if the collaterals and borrows of the user,
+// together with the given markets, do not sit in one category
+func (s *accountService) EModeCategory(ctx context.Context, userID string, markets ...*core.Market) (*core.Category, error) {
+	names := map[string]bool{}
+	for _, market := range markets {
+		names[market.Category] = true
+	}
+
+	supplies, e := s.supplyStore.FindByUser(ctx, userID)
+	if e != nil {
+		return nil, e
+	}
+
+	for _, supply := range supplies {
+		if supply.Collaterals.LessThanOrEqual(decimal.Zero) {
+			continue
+		}
+
+		market, _, e := s.marketStore.FindByCToken(ctx, supply.CTokenAssetID)
+		if e != nil {
+			return nil, e
+		}
+		names[market.Category] = true
+	}
+
+	borrows, e := s.borrowStore.FindByUser(ctx, userID)
+	if e != nil {
+		return nil, e
+	}
+
+	for _, borrow := range borrows {
+		if borrow.Principal.LessThanOrEqual(decimal.Zero) {
+			continue
+		}
+
+		market, _, e := s.marketStore.Find(ctx, borrow.AssetID)
+		if e != nil {
+			return nil, e
+		}
+		names[market.Category] = true
+	}
+
+	loans, e := s.flashLoanStore.FindPendingByUser(ctx, userID)
+	if e != nil {
+		return nil, e
+	}
+
+	for _, loan := range loans {
+		market, _, e := s.marketStore.Find(ctx, loan.AssetID)
+		if e != nil {
+			return nil, e
+		}
+		names[market.Category] = true
+	}
+
+	if len(names) != 1 {
+		return nil, nil
+	}
+
+	var name string
+	for n := range names {
+		name = n
+	}
+
+	if name == "" {
+		return nil, nil
+	}
+
+	category, isRecordNotFound, e := s.categoryStore.Find(ctx, name)
+	if e != nil {
+		if isRecordNotFound {
+			return nil, nil
+		}
+
+		return nil, e
+	}
+
+	return category, nil
+}
+
+// LiquidationIncentive the liquidation incentive for seizing the collateral of the user,
+// the liquidation incentive of the e-mode category is used if the user is in e-mode
+func (s *accountService) LiquidationIncentive(ctx context.Context, userID string, supplyMarket *core.Market) (decimal.Decimal, error) {
+	category, e := s.EModeCategory(ctx, userID)
+	if e != nil {
+		return decimal.Zero, e
+	}
+
+	if category != nil {
+		return category.LiquidationIncentive, nil
+	}
+
+	return supplyMarket.LiquidationIncentive, nil
+}
@@ -56,7 +56,7 @@ func (s *borrowService) BorrowAllowed(ctx context.Context, borrowAmount decimal.
 	}
 
 	// check liquidity
-	liquidity, e := s.accountService.CalculateAccountLiquidity(ctx, userID, blockNum)
+	liquidity, e := s.accountService.CalculateBorrowLiquidity(ctx, userID, blockNum, market)
 	if e != nil {
 		log.Errorln(e)
 		return false
@@ -74,9 +74,47 @@ func (s *borrowService) BorrowAllowed(ctx context.Context, borrowAmount decimal.
 		return false
 	}
 
+	// check isolation mode
+	isolatedMarket, e := s.accountService.IsolatedMarket(ctx, userID)
+	if e != nil {
+		log.Errorln(e)
+		return false
+	}
+
+	if isolatedMarket != nil {
+		if !market.BorrowableInIsolation() {
+			log.Errorln("not borrowable in isolation mode")
+			return false
+		}
+
+		debt, e := s.accountService.IsolatedDebt(ctx, isolatedMarket)
+		if e != nil {
+			log.Errorln(e)
+			return false
+		}
+
+		if debt.Add(borrowValue).GreaterThan(isolatedMarket.DebtCeiling) {
+			log.Errorln("isolated debt over ceiling")
+			return false
+		}
+	}
+
 	return true
 }
 
+// DelegatedBorrowAllowed check the liquidity of the delegator the borrow recorded to,
+// the allowance granted to the delegatee is checked against the delegation by the caller
+func (s *borrowService) DelegatedBorrowAllowed(ctx context.Context, borrowAmount decimal.Decimal, delegator, delegatee string, market *core.Market, time time.Time) bool {
+	log := logger.FromContext(ctx)
+
+	if delegator == delegatee {
+		log.Errorln("self delegation")
+		return false
+	}
+
+	return s.BorrowAllowed(ctx, borrowAmount, delegator, market, time)
+}
+
 // Deprecated
 func (s *borrowService) MaxBorrow(ctx context.Context, userID string, market *core.Market) (decimal.Decimal, error) {
 	// check borrow cap
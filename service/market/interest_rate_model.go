@@ -0,0 +1,37 @@
+package market
+
+import (
+	"compound/core"
+	"compound/internal/compound"
+)
+
+// interestRateModel build the interest rate model of the market
+func interestRateModel(market *core.Market) compound.InterestRateModel {
+	switch market.InterestRateModel {
+	case core.InterestRateModelLinear:
+		return compound.LinearRateModel{
+			BaseRate:   market.BaseRate,
+			Multiplier: market.Multiplier,
+		}
+	case core.InterestRateModelFixed:
+		return compound.FixedRateModel{
+			BaseRate: market.BaseRate,
+		}
+	case core.InterestRateModelDualKink:
+		return compound.DualKinkRateModel{
+			BaseRate:             market.BaseRate,
+			Multiplier:           market.Multiplier,
+			JumpMultiplier:       market.JumpMultiplier,
+			Kink:                 market.Kink,
+			SecondJumpMultiplier: market.SecondJumpMultiplier,
+			SecondKink:           market.SecondKink,
+		}
+	default:
+		return compound.JumpRateModel{
+			BaseRate:       market.BaseRate,
+			Multiplier:     market.Multiplier,
+			JumpMultiplier: market.JumpMultiplier,
+			Kink:           market.Kink,
+		}
+	}
+}
@@ -13,16 +13,19 @@ import (
 
 type service struct {
 	marketStore core.IMarketStore
+	supplyStore core.ISupplyStore
 	blockSrv    core.IBlockService
 }
 
 // New new market service
 func New(
 	marketStr core.IMarketStore,
+	supplyStr core.ISupplyStore,
 	blockSrv core.IBlockService,
 ) core.IMarketService {
 	return &service{
 		marketStore: marketStr,
+		supplyStore: supplyStr,
 		blockSrv:    blockSrv,
 	}
 }
@@ -62,7 +65,7 @@ func (s *service) CurSupplyRatePerBlock(ctx context.Context, market *core.Market
 
 //
 func (s *service) curUtilizationRateInternal(ctx context.Context, market *core.Market) (decimal.Decimal, error) {
-	rate := compound.UtilizationRate(market.TotalCash, market.TotalBorrows, market.Reserves)
+	rate := compound.UtilizationRate(market.TotalCash.Add(market.FlashLoans), market.TotalBorrows, market.Reserves)
 	return rate, nil
 }
 
@@ -71,7 +74,7 @@ func (s *service) curExchangeRateInternal(ctx context.Context, market *core.Mark
 		return market.InitExchangeRate, nil
 	}
 
-	rate := compound.GetExchangeRate(market.TotalCash, market.TotalBorrows, market.Reserves, market.CTokens, market.InitExchangeRate)
+	rate := compound.GetExchangeRate(market.TotalCash.Add(market.FlashLoans), market.TotalBorrows, market.Reserves, market.CTokens, market.InitExchangeRate)
 
 	return rate, nil
 }
@@ -93,7 +96,7 @@ func (s *service) curBorrowRatePerBlockInternal(ctx context.Context, market *cor
 		return decimal.Zero, e
 	}
 
-	rate := compound.GetBorrowRatePerBlock(utilRate, market.BaseRate, market.Multiplier, market.JumpMultiplier, market.Kink)
+	rate := interestRateModel(market).BorrowRatePerBlock(utilRate)
 
 	return rate, nil
 }
@@ -115,7 +118,7 @@ func (s *service) curSupplyRatePerBlockInternal(ctx context.Context, market *cor
 		return decimal.Zero, e
 	}
 
-	rate := compound.GetSupplyRatePerBlock(utilRate, market.BaseRate, market.Multiplier, market.JumpMultiplier, market.Kink, market.ReserveFactor)
+	rate := interestRateModel(market).SupplyRatePerBlock(utilRate, market.ReserveFactor)
 
 	return rate, nil
 }
@@ -130,6 +133,12 @@ func (s *service) CurTotalReserves(ctx context.Context, market *core.Market) (de
 	return market.Reserves, nil
 }
 
+// CurTotalSupplies total supplies of the market
+// total_supplies = market.total_cash + market.flash_loans + market.total_borrows - market.reserves
+func (s *service) CurTotalSupplies(ctx context.Context, market *core.Market) (decimal.Decimal, error) {
+	return market.TotalCash.Add(market.FlashLoans).Add(market.TotalBorrows).Sub(market.Reserves), nil
+}
+
 // AccrueInterest accrue interest market per block(15 seconds)
 //
 // Accruing interest only occurs when there is a behavior that causes changes in market transaction data, such as supply, borrow, pledge, unpledge, redeem, repay, price updating
@@ -152,14 +161,23 @@ func (s *service) AccrueInterest(ctx context.Context, tx *db.DB, market *core.Ma
 			market.BorrowIndex = borrowRate
 		}
 
+		if e = s.accrueRewardIndex(ctx, market, blockDelta); e != nil {
+			return e
+		}
+
 		timesBorrowRate := borrowRate.Mul(decimal.NewFromInt(blockDelta))
-		interestAccumulated := market.TotalBorrows.Mul(timesBorrowRate)
+		// the stable borrows accrue simple interest on their principals, the same as the stable borrow balances
+		variableBorrows := market.TotalBorrows.Sub(market.TotalStableBorrows)
+		totalStableBorrowsNew, avgStableRateNew, stableInterest := compound.AccrueStableBorrows(market.TotalStableBorrows, market.AvgStableRate, blockDelta)
+		interestAccumulated := variableBorrows.Mul(timesBorrowRate).Add(stableInterest)
 		totalBorrowsNew := interestAccumulated.Add(market.TotalBorrows)
 		totalReservesNew := interestAccumulated.Mul(market.ReserveFactor).Add(market.Reserves)
 		borrowIndexNew := market.BorrowIndex.Add(timesBorrowRate.Mul(market.BorrowIndex))
 
 		market.BlockNumber = blockNum
 		market.TotalBorrows = totalBorrowsNew.Truncate(16)
+		market.TotalStableBorrows = totalStableBorrowsNew.Truncate(16)
+		market.AvgStableRate = avgStableRateNew.Truncate(16)
 		market.Reserves = totalReservesNew.Truncate(16)
 		market.BorrowIndex = borrowIndexNew.Truncate(16)
 	}
@@ -194,6 +212,42 @@ func (s *service) AccrueInterest(ctx context.Context, tx *db.DB, market *core.Ma
 	return s.marketStore.Update(ctx, tx, market)
 }
 
+// accrueRewardIndex accrue the market reward indexes with the collaterals and borrows before the interest accrued
+//
+// 	supply_reward_index += supply_reward_speed * blocks / total_collaterals
+// 	borrow_reward_index += borrow_reward_speed * blocks / (total_borrows / borrow_index)
+func (s *service) accrueRewardIndex(ctx context.Context, market *core.Market, blockDelta int64) error {
+	if market.SupplyRewardSpeed.GreaterThan(decimal.Zero) {
+		totalCollaterals, e := s.supplyStore.SumOfSupplies(ctx, market.CTokenAssetID)
+		if e != nil {
+			return e
+		}
+
+		delta := compound.RewardIndexDelta(market.SupplyRewardSpeed, blockDelta, totalCollaterals)
+		market.SupplyRewardIndex = market.SupplyRewardIndex.Add(delta).Truncate(16)
+	}
+
+	if market.BorrowRewardSpeed.GreaterThan(decimal.Zero) && market.BorrowIndex.GreaterThan(decimal.Zero) {
+		totalBorrows := market.TotalBorrows.Div(market.BorrowIndex)
+		delta := compound.RewardIndexDelta(market.BorrowRewardSpeed, blockDelta, totalBorrows)
+		market.BorrowRewardIndex = market.BorrowRewardIndex.Add(delta).Truncate(16)
+	}
+
+	return nil
+}
+
+// AccrueFee add the fee paid by user to the market cash, the reserve factor part of the fee goes to the reserves
+func (s *service) AccrueFee(ctx context.Context, tx *db.DB, market *core.Market, fee decimal.Decimal) error {
+	if fee.LessThanOrEqual(decimal.Zero) {
+		return nil
+	}
+
+	market.TotalCash = market.TotalCash.Add(fee).Truncate(16)
+	market.Reserves = market.Reserves.Add(fee.Mul(market.ReserveFactor)).Truncate(16)
+
+	return s.marketStore.Update(ctx, tx, market)
+}
+
 func (s *service) IsMarketClosed(ctx context.Context, market *core.Market) bool {
 	return market.Status == core.MarketStatusClose
 }
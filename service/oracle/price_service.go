@@ -7,23 +7,42 @@ import (
 	"time"
 
 	"compound/core"
+	"compound/internal/compound"
 	"compound/pkg/resthttp"
 
+	"github.com/fox-one/pkg/logger"
 	"github.com/shopspring/decimal"
 )
 
 // PriceService price service
 type PriceService struct {
 	Config       *core.Config
+	PriceStore   core.IPriceStore
 	BlockService core.IBlockService
+	Sources      []core.PriceSource
 }
 
-// New new oracle price service
-func New(config *core.Config, blockSrv core.IBlockService) core.IPriceOracleService {
+// New new oracle price service, fails if any price source is misconfigured
+func New(config *core.Config, priceStr core.IPriceStore, blockSrv core.IBlockService) (core.IPriceOracleService, error) {
+	var sources []core.PriceSource
+	if config.PriceOracle.EndPoint != "" {
+		sources = append(sources, &endPointSource{endPoint: config.PriceOracle.EndPoint})
+	}
+
+	for _, conf := range config.PriceOracle.Sources {
+		source, err := NewPriceSource(conf)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, source)
+	}
+
 	return &PriceService{
 		Config:       config,
+		PriceStore:   priceStr,
 		BlockService: blockSrv,
-	}
+		Sources:      sources,
+	}, nil
 }
 
 // GetCurrentUnderlyingPrice get current price of market
@@ -35,22 +54,79 @@ func (s *PriceService) GetCurrentUnderlyingPrice(ctx context.Context, market *co
 	return market.Price, nil
 }
 
-// PullPriceTicker pull price ticker
-func (s *PriceService) PullPriceTicker(ctx context.Context, assetID string, t time.Time) (*core.PriceTicker, error) {
-	to := t
-	from := t.Add(-1 * time.Hour)
-	url := fmt.Sprintf("%s/api/v2/tickers/%s/avg?from=%d&to=%d", s.Config.PriceOracle.EndPoint, assetID, from.UTC().Unix(), to.UTC().Unix())
-	resp, err := resthttp.Request(ctx).Get(url)
-	if err != nil {
-		return nil, err
+// GetCollateralPrice get the twap of the passed prices in the twap window ending one price block after the current price updated,
+// including the current price and the price in effect at the start of the window,
+// the current price is used if the twap window is not set
+func (s *PriceService) GetCollateralPrice(ctx context.Context, market *core.Market) (decimal.Decimal, error) {
+	price, err := s.GetCurrentUnderlyingPrice(ctx, market)
+	if err != nil || market.TwapWindow <= 0 {
+		return price, err
 	}
-	var price core.PriceTicker
-	err = resthttp.ParseResponse(resp, &price)
+
+	from, to := compound.TWAPWindow(market.PriceUpdatedAt, time.Duration(market.TwapWindow)*time.Second, core.PriceBlockDuration)
+	passed, err := s.PriceStore.ListPassed(ctx, market.AssetID, from, market.PriceUpdatedAt)
 	if err != nil {
-		return nil, err
+		return decimal.Zero, err
+	}
+
+	// the price passed before the window is in effect at the start of the window
+	before, isRecordNotFound, err := s.PriceStore.FindPassedBefore(ctx, market.AssetID, from)
+	if err != nil && !isRecordNotFound {
+		return decimal.Zero, err
+	}
+	if before != nil {
+		passed = append([]*core.Price{before}, passed...)
+	}
+
+	prices := make([]decimal.Decimal, len(passed))
+	passedAts := make([]time.Time, len(passed))
+	for i, p := range passed {
+		prices[i] = p.Price
+		passedAts[i] = p.PassedAt.Time
+	}
+
+	if twap := compound.TWAP(prices, passedAts, from, to); twap.GreaterThan(decimal.Zero) {
+		return twap, nil
+	}
+
+	return price, nil
+}
+
+// PullPriceTicker pull the prices from all sources, drop the stale prices and the outliers, and aggregate the rest by median
+func (s *PriceService) PullPriceTicker(ctx context.Context, market *core.Market, t time.Time) (*core.PriceTicker, error) {
+	log := logger.FromContext(ctx).WithField("market", market.Symbol)
+
+	maxAge := time.Duration(s.Config.PriceOracle.MaxAge) * time.Second
+	prices := make([]decimal.Decimal, 0, len(s.Sources))
+	for _, source := range s.Sources {
+		price, updatedAt, err := source.Pull(ctx, market, t)
+		if err != nil {
+			log.WithError(err).Warningln("pull price error:", source.Name())
+			continue
+		}
+
+		if price.LessThanOrEqual(decimal.Zero) {
+			log.Warningln("invalid price:", source.Name(), ":", price)
+			continue
+		}
+
+		if maxAge > 0 && t.Sub(updatedAt) > maxAge {
+			log.Warningln("stale price:", source.Name(), ":", updatedAt)
+			continue
+		}
+
+		prices = append(prices, price)
+	}
+
+	price, count := compound.AggregatePrices(prices, s.Config.PriceOracle.MaxDeviation)
+	if count == 0 {
+		return nil, errors.New("no valid price")
 	}
 
-	return &price, nil
+	return &core.PriceTicker{
+		Symbol: market.Symbol,
+		Price:  price,
+	}, nil
 }
 
 // PullAllPriceTickers pull all price tickers
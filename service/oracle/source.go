@@ -0,0 +1,259 @@
+package oracle
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+
+	"compound/core"
+	"compound/pkg/resthttp"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// PriceSourceTypeRest generic rest json source
+	PriceSourceTypeRest = "rest"
+	// PriceSourceTypeFile static json file source
+	PriceSourceTypeFile = "file"
+	// PriceSourceTypeCSV csv feed source
+	PriceSourceTypeCSV = "csv"
+)
+
+// NewPriceSource new price source by config
+func NewPriceSource(conf core.PriceSourceConf) (core.PriceSource, error) {
+	switch conf.Type {
+	case PriceSourceTypeRest:
+		if conf.URL == "" || conf.PriceField == "" {
+			return nil, fmt.Errorf("price source %s: url and price_field required", conf.Name)
+		}
+		return &restSource{conf: conf}, nil
+	case PriceSourceTypeFile:
+		if conf.URL == "" {
+			return nil, fmt.Errorf("price source %s: url required", conf.Name)
+		}
+		return &fileSource{conf: conf}, nil
+	case PriceSourceTypeCSV:
+		if conf.URL == "" {
+			return nil, fmt.Errorf("price source %s: url required", conf.Name)
+		}
+		return &csvSource{conf: conf}, nil
+	}
+
+	return nil, fmt.Errorf("price source %s: unknown type %s", conf.Name, conf.Type)
+}
+
+// endPointSource the price oracle end point averaging the tickers of the last hour
+type endPointSource struct {
+	endPoint string
+}
+
+func (s *endPointSource) Name() string {
+	return s.endPoint
+}
+
+func (s *endPointSource) Pull(ctx context.Context, market *core.Market, t time.Time) (decimal.Decimal, time.Time, error) {
+	to := t
+	from := t.Add(-1 * time.Hour)
+	url := fmt.Sprintf("%s/api/v2/tickers/%s/avg?from=%d&to=%d", s.endPoint, market.AssetID, from.UTC().Unix(), to.UTC().Unix())
+	resp, err := resthttp.Request(ctx).Get(url)
+	if err != nil {
+		return decimal.Zero, t, err
+	}
+
+	var ticker core.PriceTicker
+	if err = resthttp.ParseResponse(resp, &ticker); err != nil {
+		return decimal.Zero, t, err
+	}
+
+	return ticker.Price, t, nil
+}
+
+// restSource generic rest json source, the price and the time are picked by the fields
+type restSource struct {
+	conf core.PriceSourceConf
+}
+
+func (s *restSource) Name() string {
+	return s.conf.Name
+}
+
+func (s *restSource) Pull(ctx context.Context, market *core.Market, t time.Time) (decimal.Decimal, time.Time, error) {
+	url := strings.NewReplacer("{asset_id}", market.AssetID, "{symbol}", market.Symbol).Replace(s.conf.URL)
+	resp, err := resthttp.Request(ctx).Get(url)
+	if err != nil {
+		return decimal.Zero, t, err
+	}
+
+	var body interface{}
+	if err = resthttp.ParseResponse(resp, &body); err != nil {
+		return decimal.Zero, t, err
+	}
+
+	value, err := jsonField(body, s.conf.PriceField)
+	if err != nil {
+		return decimal.Zero, t, err
+	}
+
+	price, err := decimal.NewFromString(value)
+	if err != nil {
+		return decimal.Zero, t, err
+	}
+
+	updatedAt := t
+	if s.conf.TimeField != "" {
+		if value, err = jsonField(body, s.conf.TimeField); err != nil {
+			return decimal.Zero, t, err
+		}
+
+		if updatedAt, err = parseUnix(value); err != nil {
+			return decimal.Zero, t, err
+		}
+	}
+
+	return price, updatedAt, nil
+}
+
+// fileSource static json file source, the prices keyed by asset id or symbol
+//
+// 	{"4d8c508b-91c5-375b-92b0-ee702ed2dac5": "1", "USDC": "1"}
+type fileSource struct {
+	conf core.PriceSourceConf
+}
+
+func (s *fileSource) Name() string {
+	return s.conf.Name
+}
+
+func (s *fileSource) Pull(ctx context.Context, market *core.Market, t time.Time) (decimal.Decimal, time.Time, error) {
+	data, err := ioutil.ReadFile(s.conf.URL)
+	if err != nil {
+		return decimal.Zero, t, err
+	}
+
+	var prices map[string]decimal.Decimal
+	if err = json.Unmarshal(data, &prices); err != nil {
+		return decimal.Zero, t, err
+	}
+
+	for _, key := range []string{market.AssetID, market.Symbol} {
+		if price, ok := prices[key]; ok {
+			// the static price never goes stale
+			return price, t, nil
+		}
+	}
+
+	return decimal.Zero, t, errors.New("price not found")
+}
+
+// csvSource csv feed source loaded from the path or url, the rows without a valid price are skipped
+//
+// 	asset_id or symbol,price[,unix timestamp]
+type csvSource struct {
+	conf core.PriceSourceConf
+}
+
+func (s *csvSource) Name() string {
+	return s.conf.Name
+}
+
+func (s *csvSource) Pull(ctx context.Context, market *core.Market, t time.Time) (decimal.Decimal, time.Time, error) {
+	var data []byte
+	if strings.HasPrefix(s.conf.URL, "http://") || strings.HasPrefix(s.conf.URL, "https://") {
+		resp, err := resthttp.Request(ctx).Get(s.conf.URL)
+		if err != nil {
+			return decimal.Zero, t, err
+		}
+		if !resp.IsSuccess() {
+			return decimal.Zero, t, errors.New(resp.Status())
+		}
+		data = resp.Body()
+	} else {
+		var err error
+		if data, err = ioutil.ReadFile(s.conf.URL); err != nil {
+			return decimal.Zero, t, err
+		}
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return decimal.Zero, t, err
+		}
+
+		if len(record) < 2 {
+			continue
+		}
+
+		key := strings.TrimSpace(record[0])
+		if key != market.AssetID && key != market.Symbol {
+			continue
+		}
+
+		price, err := decimal.NewFromString(strings.TrimSpace(record[1]))
+		if err != nil {
+			continue
+		}
+
+		updatedAt := t
+		if len(record) > 2 {
+			if updatedAt, err = parseUnix(strings.TrimSpace(record[2])); err != nil {
+				return decimal.Zero, t, err
+			}
+		}
+
+		return price, updatedAt, nil
+	}
+
+	return decimal.Zero, t, errors.New("price not found")
+}
+
+// jsonField pick the value of the dot separated path, the array elements are picked by index
+func jsonField(body interface{}, path string) (string, error) {
+	value := body
+	for _, key := range strings.Split(path, ".") {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			value = v[key]
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(v) {
+				return "", fmt.Errorf("invalid field %s", path)
+			}
+			value = v[i]
+		default:
+			return "", fmt.Errorf("invalid field %s", path)
+		}
+	}
+
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	}
+
+	return "", fmt.Errorf("invalid field %s", path)
+}
+
+func parseUnix(value string) (time.Time, error) {
+	ts, err := decimal.NewFromString(value)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Unix(ts.IntPart(), 0), nil
+}
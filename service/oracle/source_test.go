@@ -0,0 +1,283 @@
+package oracle
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"compound/core"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testMarket = &core.Market{
+	AssetID: "c6d0c728-2624-429b-8e0d-d9d19b6592fa",
+	Symbol:  "BTC",
+}
+
+func writeTempFile(t *testing.T, name, content string) string {
+	dir, err := ioutil.TempDir("", "oracle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, name)
+	if err = ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestNewPriceSource(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		conf core.PriceSourceConf
+		ok   bool
+	}{
+		{"rest", core.PriceSourceConf{Type: PriceSourceTypeRest, URL: "http://localhost", PriceField: "price"}, true},
+		{"rest without url", core.PriceSourceConf{Type: PriceSourceTypeRest, PriceField: "price"}, false},
+		{"rest without price field", core.PriceSourceConf{Type: PriceSourceTypeRest, URL: "http://localhost"}, false},
+		{"file", core.PriceSourceConf{Type: PriceSourceTypeFile, URL: "prices.json"}, true},
+		{"file without url", core.PriceSourceConf{Type: PriceSourceTypeFile}, false},
+		{"csv", core.PriceSourceConf{Type: PriceSourceTypeCSV, URL: "prices.csv"}, true},
+		{"csv without url", core.PriceSourceConf{Type: PriceSourceTypeCSV}, false},
+		{"unknown type", core.PriceSourceConf{Type: "ws", URL: "ws://localhost"}, false},
+	} {
+		_, err := NewPriceSource(c.conf)
+		assert.Equal(t, c.ok, err == nil, c.name)
+	}
+}
+
+func TestNewMisconfigured(t *testing.T) {
+	config := &core.Config{}
+	config.PriceOracle.Sources = []core.PriceSourceConf{{Name: "bad", Type: "ws"}}
+
+	_, err := New(config, nil, nil)
+	assert.Error(t, err)
+}
+
+func TestRestSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/tickers/BTC":
+			w.Write([]byte(`{"data":{"price":"100.5","ts":1600000000}}`))
+		case "/tickers/" + testMarket.AssetID:
+			w.Write([]byte(`{"data":[{"price":99.5}]}`))
+		case "/invalid/BTC":
+			w.Write([]byte(`{"data":{"price":"abc"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	now := time.Unix(1600000600, 0)
+	for _, c := range []struct {
+		name       string
+		url        string
+		priceField string
+		timeField  string
+		price      string
+		updatedAt  time.Time
+		ok         bool
+	}{
+		{"by symbol with time", "/tickers/{symbol}", "data.price", "data.ts", "100.5", time.Unix(1600000000, 0), true},
+		{"by asset id in array", "/tickers/{asset_id}", "data.0.price", "", "99.5", now, true},
+		{"missing field", "/tickers/{symbol}", "data.last", "", "", now, false},
+		{"missing time field", "/tickers/{symbol}", "data.price", "data.time", "", now, false},
+		{"invalid price", "/invalid/{symbol}", "data.price", "", "", now, false},
+		{"not found", "/unknown/{symbol}", "data.price", "", "", now, false},
+	} {
+		source, err := NewPriceSource(core.PriceSourceConf{
+			Name:       c.name,
+			Type:       PriceSourceTypeRest,
+			URL:        server.URL + c.url,
+			PriceField: c.priceField,
+			TimeField:  c.timeField,
+		})
+		if !assert.NoError(t, err, c.name) {
+			continue
+		}
+
+		price, updatedAt, err := source.Pull(context.Background(), testMarket, now)
+		if !c.ok {
+			assert.Error(t, err, c.name)
+			continue
+		}
+
+		if assert.NoError(t, err, c.name) {
+			assert.Equal(t, c.price, price.String(), c.name)
+			assert.True(t, c.updatedAt.Equal(updatedAt), c.name)
+		}
+	}
+}
+
+func TestFileSource(t *testing.T) {
+	now := time.Unix(1600000600, 0)
+	for _, c := range []struct {
+		name    string
+		content string
+		price   string
+		ok      bool
+	}{
+		{"by asset id", `{"c6d0c728-2624-429b-8e0d-d9d19b6592fa": "100"}`, "100", true},
+		{"by symbol", `{"BTC": "101", "ETH": "10"}`, "101", true},
+		{"asset id first", `{"BTC": "101", "c6d0c728-2624-429b-8e0d-d9d19b6592fa": "100"}`, "100", true},
+		{"not found", `{"ETH": "10"}`, "", false},
+		{"invalid json", `{"BTC": }`, "", false},
+	} {
+		source, err := NewPriceSource(core.PriceSourceConf{
+			Name: c.name,
+			Type: PriceSourceTypeFile,
+			URL:  writeTempFile(t, "prices.json", c.content),
+		})
+		if !assert.NoError(t, err, c.name) {
+			continue
+		}
+
+		price, updatedAt, err := source.Pull(context.Background(), testMarket, now)
+		if !c.ok {
+			assert.Error(t, err, c.name)
+			continue
+		}
+
+		if assert.NoError(t, err, c.name) {
+			assert.Equal(t, c.price, price.String(), c.name)
+			assert.True(t, now.Equal(updatedAt), c.name)
+		}
+	}
+}
+
+func TestCSVSource(t *testing.T) {
+	now := time.Unix(1600000600, 0)
+	for _, c := range []struct {
+		name      string
+		content   string
+		price     string
+		updatedAt time.Time
+		ok        bool
+	}{
+		{"by symbol", "ETH,10\nBTC,100\n", "100", now, true},
+		{"by asset id with time", "c6d0c728-2624-429b-8e0d-d9d19b6592fa, 100.5 ,1600000000\n", "100.5", time.Unix(1600000000, 0), true},
+		{"invalid price skipped", "BTC,abc\nBTC,99\n", "99", now, true},
+		{"short rows skipped", "BTC\nBTC,98\n", "98", now, true},
+		{"invalid time", "BTC,100,yesterday\n", "", now, false},
+		{"not found", "ETH,10\n", "", now, false},
+	} {
+		source, err := NewPriceSource(core.PriceSourceConf{
+			Name: c.name,
+			Type: PriceSourceTypeCSV,
+			URL:  writeTempFile(t, "prices.csv", c.content),
+		})
+		if !assert.NoError(t, err, c.name) {
+			continue
+		}
+
+		price, updatedAt, err := source.Pull(context.Background(), testMarket, now)
+		if !c.ok {
+			assert.Error(t, err, c.name)
+			continue
+		}
+
+		if assert.NoError(t, err, c.name) {
+			assert.Equal(t, c.price, price.String(), c.name)
+			assert.True(t, c.updatedAt.Equal(updatedAt), c.name)
+		}
+	}
+}
+
+func TestCSVSourceURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/prices.csv" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("BTC,100\n"))
+	}))
+	defer server.Close()
+
+	now := time.Unix(1600000600, 0)
+	source, err := NewPriceSource(core.PriceSourceConf{Type: PriceSourceTypeCSV, URL: server.URL + "/prices.csv"})
+	if assert.NoError(t, err) {
+		price, _, err := source.Pull(context.Background(), testMarket, now)
+		assert.NoError(t, err)
+		assert.Equal(t, "100", price.String())
+	}
+
+	source, err = NewPriceSource(core.PriceSourceConf{Type: PriceSourceTypeCSV, URL: server.URL + "/missing.csv"})
+	if assert.NoError(t, err) {
+		_, _, err = source.Pull(context.Background(), testMarket, now)
+		assert.Error(t, err)
+	}
+}
+
+func TestJSONField(t *testing.T) {
+	body := map[string]interface{}{
+		"price": "100",
+		"data": map[string]interface{}{
+			"last":  101.25,
+			"items": []interface{}{map[string]interface{}{"price": "102"}},
+			"ok":    true,
+		},
+	}
+
+	for _, c := range []struct {
+		name  string
+		path  string
+		value string
+		ok    bool
+	}{
+		{"string", "price", "100", true},
+		{"number", "data.last", "101.25", true},
+		{"array index", "data.items.0.price", "102", true},
+		{"array index out of range", "data.items.1.price", "", false},
+		{"array key not index", "data.items.first", "", false},
+		{"missing", "data.close", "", false},
+		{"not a leaf", "data", "", false},
+		{"bool", "data.ok", "", false},
+		{"through a leaf", "price.value", "", false},
+	} {
+		value, err := jsonField(body, c.path)
+		if !c.ok {
+			assert.Error(t, err, c.name)
+			continue
+		}
+
+		if assert.NoError(t, err, c.name) {
+			assert.Equal(t, c.value, value, c.name)
+		}
+	}
+}
+
+func TestParseUnix(t *testing.T) {
+	for _, c := range []struct {
+		name  string
+		value string
+		unix  int64
+		ok    bool
+	}{
+		{"seconds", "1600000000", 1600000000, true},
+		{"fraction truncated", "1600000000.9", 1600000000, true},
+		{"scientific", "1.6e9", 1600000000, true},
+		{"invalid", "yesterday", 0, false},
+		{"empty", "", 0, false},
+	} {
+		ts, err := parseUnix(c.value)
+		if !c.ok {
+			assert.Error(t, err, c.name)
+			continue
+		}
+
+		if assert.NoError(t, err, c.name) {
+			assert.Equal(t, c.unix, ts.Unix(), c.name)
+		}
+	}
+}
@@ -41,6 +41,90 @@ func generateButtons(ctx context.Context, marketStore core.IMarketStore, p *core
 			return buttons
 		}
 		buttons = appendAsset(buttons, "Asset", market.AssetID)
+	case core.ActionTypeProposalUpdateInterestRateModel:
+		var action proposal.UpdateInterestRateModelReq
+		_ = json.Unmarshal(p.Content, &action)
+		symbol := strings.ToUpper(action.Symbol)
+		market, _, e := marketStore.FindBySymbol(ctx, symbol)
+		if e != nil {
+			return buttons
+		}
+		buttons = appendAsset(buttons, "Asset", market.AssetID)
+	case core.ActionTypeProposalUpdateIsolation:
+		var action proposal.UpdateIsolationReq
+		_ = json.Unmarshal(p.Content, &action)
+		symbol := strings.ToUpper(action.Symbol)
+		market, _, e := marketStore.FindBySymbol(ctx, symbol)
+		if e != nil {
+			return buttons
+		}
+		buttons = appendAsset(buttons, "Asset", market.AssetID)
+	case core.ActionTypeProposalUpdateCategory:
+	case core.ActionTypeProposalSetMarketCategory:
+		var action proposal.MarketCategoryReq
+		_ = json.Unmarshal(p.Content, &action)
+		symbol := strings.ToUpper(action.Symbol)
+		market, _, e := marketStore.FindBySymbol(ctx, symbol)
+		if e != nil {
+			return buttons
+		}
+		buttons = appendAsset(buttons, "Asset", market.AssetID)
+	case core.ActionTypeProposalUpdateRewardSpeed:
+		var action proposal.UpdateRewardSpeedReq
+		_ = json.Unmarshal(p.Content, &action)
+		symbol := strings.ToUpper(action.Symbol)
+		market, _, e := marketStore.FindBySymbol(ctx, symbol)
+		if e != nil {
+			return buttons
+		}
+		buttons = appendAsset(buttons, "Asset", market.AssetID)
+	case core.ActionTypeProposalUpdateLiquidationMode:
+		var action proposal.UpdateLiquidationModeReq
+		_ = json.Unmarshal(p.Content, &action)
+		symbol := strings.ToUpper(action.Symbol)
+		market, _, e := marketStore.FindBySymbol(ctx, symbol)
+		if e != nil {
+			return buttons
+		}
+		buttons = appendAsset(buttons, "Asset", market.AssetID)
+	case core.ActionTypeProposalUpdatePriceGuard:
+		var action proposal.UpdatePriceGuardReq
+		_ = json.Unmarshal(p.Content, &action)
+		symbol := strings.ToUpper(action.Symbol)
+		market, _, e := marketStore.FindBySymbol(ctx, symbol)
+		if e != nil {
+			return buttons
+		}
+		buttons = appendAsset(buttons, "Asset", market.AssetID)
+	case core.ActionTypeProposalConfirmPrice:
+		var action proposal.ConfirmPriceReq
+		_ = json.Unmarshal(p.Content, &action)
+		symbol := strings.ToUpper(action.Symbol)
+		market, _, e := marketStore.FindBySymbol(ctx, symbol)
+		if e != nil {
+			return buttons
+		}
+		buttons = appendAsset(buttons, "Asset", market.AssetID)
+	case core.ActionTypeProposalUpdatePriceConsensus:
+		var action proposal.UpdatePriceConsensusReq
+		_ = json.Unmarshal(p.Content, &action)
+		symbol := strings.ToUpper(action.Symbol)
+		market, _, e := marketStore.FindBySymbol(ctx, symbol)
+		if e != nil {
+			return buttons
+		}
+		buttons = appendAsset(buttons, "Asset", market.AssetID)
+	case core.ActionTypeProposalStartAuction:
+		var action proposal.StartAuctionReq
+		_ = json.Unmarshal(p.Content, &action)
+		symbol := strings.ToUpper(action.Symbol)
+		market, _, e := marketStore.FindBySymbol(ctx, symbol)
+		if e != nil {
+			return buttons
+		}
+		buttons = appendAsset(buttons, "Asset", market.AssetID)
+		buttons = appendAsset(buttons, "Bid Asset", action.BidAsset)
+		buttons = appendUser(buttons, "Opponent", action.Opponent)
 	case core.ActionTypeProposalWithdrawReserves:
 		var action proposal.WithdrawReq
 		_ = json.Unmarshal(p.Content, &action)
@@ -58,6 +142,26 @@ func generateButtons(ctx context.Context, marketStore core.IMarketStore, p *core
 	case core.ActionTypeProposalRemoveScope:
 	case core.ActionTypeProposalAddAllowList:
 	case core.ActionTypeProposalRemoveAllowList:
+	case core.ActionTypeProposalVeto:
+	case core.ActionTypeProposalAddMember:
+		var action proposal.AddMemberReq
+		_ = json.Unmarshal(p.Content, &action)
+		buttons = appendUser(buttons, "Member", action.ClientID)
+	case core.ActionTypeProposalRemoveMember:
+		var action proposal.RemoveMemberReq
+		_ = json.Unmarshal(p.Content, &action)
+		buttons = appendUser(buttons, "Member", action.ClientID)
+	case core.ActionTypeProposalUpdateThreshold:
+	case core.ActionTypeProposalBatch:
+		var action proposal.BatchReq
+		_ = json.Unmarshal(p.Content, &action)
+		for _, item := range action.Items {
+			itemProposal := *p
+			itemProposal.Action = item.Action
+			itemProposal.Content = []byte(item.Content)
+			// without the creator button
+			buttons = append(buttons, generateButtons(ctx, marketStore, &itemProposal)[1:]...)
+		}
 	}
 
 	return buttons
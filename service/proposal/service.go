@@ -6,6 +6,7 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
 
 	"github.com/fox-one/mixin-sdk-go"
 	"github.com/fox-one/pkg/uuid"
@@ -36,31 +37,35 @@ type service struct {
 func (p *service) ProposalCreated(ctx context.Context, proposal *core.Proposal, by *core.Member) error {
 	buttons := generateButtons(ctx, p.marketStore, proposal)
 
-	trace, _ := uuid.FromString(proposal.TraceID)
-	uid, _ := uuid.FromString(p.system.ClientID)
-	memo, err := mtg.Encode(uid, trace, int(core.ActionTypeProposalVote))
-	if err != nil {
-		return err
-	}
+	// the rejected proposal is never voted
+	if proposal.Status != core.ProposalStatusRejected {
+		trace, _ := uuid.FromString(proposal.TraceID)
+		uid, _ := uuid.FromString(p.system.ClientID)
+		memo, err := mtg.Encode(uid, trace, int(core.ActionTypeProposalVote))
+		if err != nil {
+			return err
+		}
 
-	sign := mtg.Sign(memo, p.system.SignKey)
-	memo = mtg.Pack(memo, sign)
+		sign := mtg.Sign(memo, p.system.SignKey)
+		memo = mtg.Pack(memo, sign)
 
-	input := mixin.TransferInput{
-		AssetID: p.system.VoteAsset,
-		Amount:  p.system.VoteAmount,
-		TraceID: uuid.Modify(proposal.TraceID, p.system.ClientID),
-		Memo:    base64.StdEncoding.EncodeToString(memo),
-	}
-	input.OpponentMultisig.Receivers = p.system.MemberIDs()
-	input.OpponentMultisig.Threshold = p.system.Threshold
+		input := mixin.TransferInput{
+			AssetID: p.system.VoteAsset,
+			Amount:  p.system.VoteAmount,
+			TraceID: uuid.Modify(proposal.TraceID, p.system.ClientID),
+			Memo:    base64.StdEncoding.EncodeToString(memo),
+		}
+		input.OpponentMultisig.Receivers = p.system.MemberIDs()
+		input.OpponentMultisig.Threshold = p.system.Threshold
+
+		payment, err := p.client.VerifyPayment(ctx, input)
+		if err != nil {
+			return err
+		}
 
-	payment, err := p.client.VerifyPayment(ctx, input)
-	if err != nil {
-		return err
+		buttons = appendCode(buttons, "Vote", payment.CodeID)
 	}
 
-	buttons = appendCode(buttons, "Vote", payment.CodeID)
 	buttonsData, _ := json.Marshal(buttons)
 
 	post := renderProposal(proposal)
@@ -115,15 +120,40 @@ func (p *service) ProposalApproved(ctx context.Context, proposal *core.Proposal,
 
 // ProposalPassed send proposal approved message to all the node managers
 func (p *service) ProposalPassed(ctx context.Context, proposal *core.Proposal) error {
+	return p.quoteProposal(ctx, proposal, passedTpl, "Proposal Passed")
+}
+
+// ProposalExpired send proposal expired message to all the node managers
+func (p *service) ProposalExpired(ctx context.Context, proposal *core.Proposal) error {
+	return p.quoteProposal(ctx, proposal, expiredTpl, "Proposal Expired")
+}
+
+// ProposalCancelled send proposal cancelled message to all the node managers
+func (p *service) ProposalCancelled(ctx context.Context, proposal *core.Proposal) error {
+	return p.quoteProposal(ctx, proposal, cancelledTpl, "Proposal Cancelled")
+}
+
+// ProposalQueued send proposal queued message to all the node managers
+func (p *service) ProposalQueued(ctx context.Context, proposal *core.Proposal) error {
+	return p.quoteProposal(ctx, proposal, renderQueued(proposal), "Proposal Queued")
+}
+
+// ProposalVetoed send proposal vetoed message to all the node managers
+func (p *service) ProposalVetoed(ctx context.Context, proposal *core.Proposal) error {
+	return p.quoteProposal(ctx, proposal, vetoedTpl, "Proposal Vetoed")
+}
+
+// quoteProposal send the text quoting the proposal message to all the node managers
+func (p *service) quoteProposal(ctx context.Context, proposal *core.Proposal, text, tag string) error {
 	var messages []*core.Message
 
-	post := []byte(passedTpl)
+	post := []byte(text)
 	for _, admin := range p.system.Admins {
 		quote := uuid.Modify(proposal.TraceID, p.system.ClientID+admin)
 		msg := &mixin.MessageRequest{
 			RecipientID:    admin,
 			ConversationID: mixin.UniqueConversationID(p.system.ClientID, admin),
-			MessageID:      uuid.Modify(quote, "Proposal Passed"),
+			MessageID:      uuid.Modify(quote, tag),
 			Category:       mixin.MessageCategoryPlainText,
 			Data:           base64.StdEncoding.EncodeToString(post),
 			QuoteMessageID: quote,
@@ -134,3 +164,44 @@ func (p *service) ProposalPassed(ctx context.Context, proposal *core.Proposal) e
 
 	return p.messages.Create(ctx, messages)
 }
+
+// BadDebtWrittenOff send the bad debt write-off report to all the node managers
+func (p *service) BadDebtWrittenOff(ctx context.Context, transaction *core.Transaction) error {
+	var messages []*core.Message
+
+	post := renderBadDebt(transaction)
+	for _, admin := range p.system.Admins {
+		msg := &mixin.MessageRequest{
+			RecipientID:    admin,
+			ConversationID: mixin.UniqueConversationID(p.system.ClientID, admin),
+			MessageID:      uuid.Modify(transaction.TraceID, p.system.ClientID+admin),
+			Category:       mixin.MessageCategoryPlainPost,
+			Data:           base64.StdEncoding.EncodeToString(post),
+		}
+
+		messages = append(messages, core.BuildMessage(msg))
+	}
+
+	return p.messages.Create(ctx, messages)
+}
+
+// PriceStale alert all the node managers that the market price is stale, once per price update
+func (p *service) PriceStale(ctx context.Context, market *core.Market) error {
+	var messages []*core.Message
+
+	text := renderPriceStale(market)
+	traceID := uuid.Modify(market.AssetID, fmt.Sprintf("price stale %d", market.PriceUpdatedAt.Unix()))
+	for _, admin := range p.system.Admins {
+		msg := &mixin.MessageRequest{
+			RecipientID:    admin,
+			ConversationID: mixin.UniqueConversationID(p.system.ClientID, admin),
+			MessageID:      uuid.Modify(traceID, p.system.ClientID+admin),
+			Category:       mixin.MessageCategoryPlainText,
+			Data:           base64.StdEncoding.EncodeToString(text),
+		}
+
+		messages = append(messages, core.BuildMessage(msg))
+	}
+
+	return p.messages.Create(ctx, messages)
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"text/template"
+	"time"
 )
 
 func codeBlock(data []byte, tag string) []byte {
@@ -20,13 +21,16 @@ func codeBlock(data []byte, tag string) []byte {
 }
 
 const proposalTpl = `### #{{.ID}} NEW PROPOSAL "{{.Action}}"
-
+{{if .Reason}}
+❌ Rejected: {{.Reason}}
+{{end}}
 {{.Proposal}}
 `
 
 type view struct {
 	ID       int64
 	Action   string
+	Reason   string
 	Proposal string
 }
 
@@ -36,6 +40,10 @@ func renderProposal(p *core.Proposal) []byte {
 		Action: p.Action.String(),
 	}
 
+	if p.Status == core.ProposalStatusRejected {
+		v.Reason = p.Reason
+	}
+
 	data, _ := json.MarshalIndent(p, "", "  ")
 	v.Proposal = string(codeBlock(data, "json"))
 
@@ -75,3 +83,74 @@ func renderApprovedBy(p *core.Proposal, member *core.Member) []byte {
 }
 
 const passedTpl = "🎉 Proposal Passed"
+
+const expiredTpl = "⌛ Proposal Expired"
+
+const cancelledTpl = "🚫 Proposal Cancelled"
+
+const vetoedTpl = "🛑 Proposal Vetoed"
+
+const queuedTpl = "⏳ Proposal Queued, executable at {{.ExecutableAt}}"
+
+func renderQueued(p *core.Proposal) string {
+	t, err := template.New("-").Parse(queuedTpl)
+	if err != nil {
+		panic(err)
+	}
+
+	var b bytes.Buffer
+	if err := t.Execute(&b, map[string]interface{}{
+		"ExecutableAt": p.ExecutableAt.Time.Format(time.RFC3339),
+	}); err != nil {
+		panic(err)
+	}
+
+	return b.String()
+}
+
+const badDebtTpl = `### ⚠️ BAD DEBT WRITTEN OFF
+
+{{.Transaction}}
+`
+
+func renderBadDebt(transaction *core.Transaction) []byte {
+	t, err := template.New("-").Parse(badDebtTpl)
+	if err != nil {
+		panic(err)
+	}
+
+	data, _ := json.MarshalIndent(transaction, "", "  ")
+
+	var b bytes.Buffer
+	if err := t.Execute(&b, map[string]interface{}{
+		"Transaction": string(codeBlock(data, "json")),
+	}); err != nil {
+		panic(err)
+	}
+
+	return b.Bytes()
+}
+
+const priceStaleTpl = `⚠️ PRICE STALE {{.Symbol}}
+
+The price {{.Price}} was updated at {{.UpdatedAt}}, older than the max price age {{.MaxPriceAge}}s. Borrow, unpledge, redeem and liquidation are paused.
+`
+
+func renderPriceStale(market *core.Market) []byte {
+	t, err := template.New("-").Parse(priceStaleTpl)
+	if err != nil {
+		panic(err)
+	}
+
+	var b bytes.Buffer
+	if err := t.Execute(&b, map[string]interface{}{
+		"Symbol":      market.Symbol,
+		"Price":       market.Price,
+		"UpdatedAt":   market.PriceUpdatedAt.Format(time.RFC3339),
+		"MaxPriceAge": market.MaxPriceAge,
+	}); err != nil {
+		panic(err)
+	}
+
+	return b.Bytes()
+}
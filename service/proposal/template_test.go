@@ -3,6 +3,7 @@ package proposal
 import (
 	"compound/core"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/fox-one/pkg/uuid"
@@ -22,3 +23,21 @@ func TestRenderProposal(t *testing.T) {
 	view := renderProposal(p)
 	fmt.Println(string(view))
 }
+
+func TestRenderRejectedProposal(t *testing.T) {
+	p := &core.Proposal{
+		TraceID: uuid.New(),
+		Creator: uuid.New(),
+		AssetID: uuid.New(),
+		Amount:  decimal.New(7, 2),
+		Action:  core.ActionTypeProposalUpdateMarketAdvance,
+		Status:  core.ProposalStatusRejected,
+		Reason:  "kink 1.2 out of [0, 1]",
+		Content: []byte(`{"kink":"1.2"}`),
+	}
+
+	view := renderProposal(p)
+	if !strings.Contains(string(view), "❌ Rejected: kink 1.2 out of [0, 1]") {
+		t.Fatalf("reason not rendered: %s", view)
+	}
+}
@@ -0,0 +1,132 @@
+package reward
+
+import (
+	"compound/core"
+	"compound/internal/compound"
+	"context"
+
+	"github.com/fox-one/pkg/store/db"
+	"github.com/shopspring/decimal"
+)
+
+type rewardService struct {
+	marketStore core.IMarketStore
+	supplyStore core.ISupplyStore
+	borrowStore core.IBorrowStore
+	rewardStore core.IRewardStore
+}
+
+// New new reward service
+func New(
+	marketStore core.IMarketStore,
+	supplyStore core.ISupplyStore,
+	borrowStore core.IBorrowStore,
+	rewardStore core.IRewardStore,
+) core.IRewardService {
+	return &rewardService{
+		marketStore: marketStore,
+		supplyStore: supplyStore,
+		borrowStore: borrowStore,
+		rewardStore: rewardStore,
+	}
+}
+
+// Distribute accrue the rewards of the user in the market
+//
+// 	supply_accrued = supply.collaterals * (market.supply_reward_index - reward.supply_index)
+// 	borrow_accrued = borrow.balance / market.borrow_index * (market.borrow_reward_index - reward.borrow_index)
+func (s *rewardService) Distribute(ctx context.Context, tx *db.DB, market *core.Market, userID string) (*core.Reward, error) {
+	reward, isRecordNotFound, e := s.rewardStore.Find(ctx, userID, market.AssetID)
+	if e != nil {
+		if !isRecordNotFound {
+			return nil, e
+		}
+
+		reward = &core.Reward{
+			UserID:      userID,
+			AssetID:     market.AssetID,
+			SupplyIndex: decimal.Zero,
+			BorrowIndex: decimal.Zero,
+			Accrued:     decimal.Zero,
+		}
+	}
+
+	accrued, e := s.accrued(ctx, market, userID, reward)
+	if e != nil {
+		return nil, e
+	}
+
+	reward.Accrued = reward.Accrued.Add(accrued).Truncate(16)
+	reward.SupplyIndex = market.SupplyRewardIndex
+	reward.BorrowIndex = market.BorrowRewardIndex
+
+	if isRecordNotFound {
+		if e = s.rewardStore.Save(ctx, tx, reward); e != nil {
+			return nil, e
+		}
+
+		return reward, nil
+	}
+
+	if e = s.rewardStore.Update(ctx, tx, reward); e != nil {
+		return nil, e
+	}
+
+	return reward, nil
+}
+
+// Accrued the claimable rewards of the user up to the current market reward indexes
+func (s *rewardService) Accrued(ctx context.Context, userID string) (decimal.Decimal, error) {
+	markets, e := s.marketStore.All(ctx)
+	if e != nil {
+		return decimal.Zero, e
+	}
+
+	total := decimal.Zero
+	for _, market := range markets {
+		reward, isRecordNotFound, e := s.rewardStore.Find(ctx, userID, market.AssetID)
+		if e != nil {
+			if !isRecordNotFound {
+				return decimal.Zero, e
+			}
+
+			reward = &core.Reward{}
+		}
+
+		accrued, e := s.accrued(ctx, market, userID, reward)
+		if e != nil {
+			return decimal.Zero, e
+		}
+
+		total = total.Add(reward.Accrued).Add(accrued)
+	}
+
+	return total.Truncate(8), nil
+}
+
+func (s *rewardService) accrued(ctx context.Context, market *core.Market, userID string, reward *core.Reward) (decimal.Decimal, error) {
+	accrued := decimal.Zero
+
+	supply, isRecordNotFound, e := s.supplyStore.Find(ctx, userID, market.CTokenAssetID)
+	if e != nil && !isRecordNotFound {
+		return decimal.Zero, e
+	}
+	if supply != nil {
+		accrued = accrued.Add(compound.RewardAccrued(supply.Collaterals, market.SupplyRewardIndex, reward.SupplyIndex))
+	}
+
+	borrow, isRecordNotFound, e := s.borrowStore.Find(ctx, userID, market.AssetID)
+	if e != nil && !isRecordNotFound {
+		return decimal.Zero, e
+	}
+	if borrow != nil && market.BorrowIndex.GreaterThan(decimal.Zero) {
+		balance, e := borrow.Balance(ctx, market)
+		if e != nil {
+			return decimal.Zero, e
+		}
+
+		accrued = accrued.Add(compound.RewardAccrued(balance.Div(market.BorrowIndex), market.BorrowRewardIndex, reward.BorrowIndex))
+	}
+
+	return accrued, nil
+}
@@ -0,0 +1,66 @@
+package wallet
+
+import (
+	"time"
+
+	"compound/core"
+)
+
+// backfill the pull offsets of the new member sets
+//
+// the outputs sent to a new member set before the membership was saved are older than the sync offset,
+// so the member set is pulled from its effective time until it catches up with the offset
+type backfill struct {
+	cursors map[string]time.Time
+	synced  map[string]bool
+}
+
+func newBackfill() *backfill {
+	return &backfill{
+		cursors: map[string]time.Time{},
+		synced:  map[string]bool{},
+	}
+}
+
+// offset the offset to pull the outputs of the membership from
+func (b *backfill) offset(m *core.Membership, offset time.Time) time.Time {
+	if b.synced[m.TraceID] {
+		return offset
+	}
+
+	from := m.EffectiveAt
+	if cursor, ok := b.cursors[m.TraceID]; ok {
+		from = cursor
+	}
+
+	if !from.Before(offset) {
+		b.synced[m.TraceID] = true
+		delete(b.cursors, m.TraceID)
+		return offset
+	}
+
+	return from
+}
+
+// update move the cursor of the membership to the last output returned,
+// the membership is synced once all the outputs pulled are returned and there are no more
+func (b *backfill) update(m *core.Membership, pulled []*core.Output, returned map[string]bool, limit int) {
+	if b.synced[m.TraceID] {
+		return
+	}
+
+	all := len(pulled) < limit
+	for _, output := range pulled {
+		if !returned[output.TraceID] {
+			all = false
+			break
+		}
+
+		b.cursors[m.TraceID] = output.UpdatedAt
+	}
+
+	if all {
+		b.synced[m.TraceID] = true
+		delete(b.cursors, m.TraceID)
+	}
+}
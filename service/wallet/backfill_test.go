@@ -0,0 +1,54 @@
+package wallet
+
+import (
+	"testing"
+	"time"
+
+	"compound/core"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackfill(t *testing.T) {
+	var (
+		effectiveAt = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+		offset      = effectiveAt.Add(time.Hour)
+		m           = &core.Membership{TraceID: "membership", EffectiveAt: effectiveAt}
+	)
+
+	output := func(id string, d time.Duration) *core.Output {
+		return &core.Output{TraceID: id, UpdatedAt: effectiveAt.Add(d)}
+	}
+
+	t.Run("synced before the offset", func(t *testing.T) {
+		b := newBackfill()
+		assert.Equal(t, effectiveAt, b.offset(m, effectiveAt))
+		assert.Equal(t, offset, b.offset(m, offset))
+	})
+
+	t.Run("backfilled from the effective time", func(t *testing.T) {
+		b := newBackfill()
+		assert.Equal(t, effectiveAt, b.offset(m, offset))
+
+		// the last output is cut by the limit of the pull
+		pulled := []*core.Output{output("1", time.Minute), output("2", 2*time.Minute), output("3", 3*time.Minute)}
+		b.update(m, pulled, map[string]bool{"1": true, "2": true}, 3)
+		assert.Equal(t, effectiveAt.Add(2*time.Minute), b.offset(m, offset))
+
+		pulled = []*core.Output{output("2", 2*time.Minute), output("3", 3*time.Minute)}
+		b.update(m, pulled, map[string]bool{"2": true, "3": true}, 3)
+		assert.Equal(t, offset, b.offset(m, offset))
+
+		// pulled from the offset after synced
+		assert.Equal(t, offset.Add(-time.Minute), b.offset(m, offset.Add(-time.Minute)))
+	})
+
+	t.Run("cursor catches up with the offset", func(t *testing.T) {
+		b := newBackfill()
+		assert.Equal(t, effectiveAt, b.offset(m, offset))
+
+		pulled := []*core.Output{output("1", time.Hour), output("2", 2*time.Hour)}
+		b.update(m, pulled, map[string]bool{"1": true}, 2)
+		assert.Equal(t, offset, b.offset(m, offset))
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	"compound/core"
@@ -20,7 +21,7 @@ type Config struct {
 	Threshold uint8    `valid:"required"`
 }
 
-func New(client *mixin.Client, cfg Config) core.WalletService {
+func New(client *mixin.Client, cfg Config, membershipStr core.IMembershipStore) core.WalletService {
 	if _, err := govalidator.ValidateStruct(cfg); err != nil {
 		panic(err)
 	}
@@ -30,6 +31,9 @@ func New(client *mixin.Client, cfg Config) core.WalletService {
 		members:   cfg.Members,
 		threshold: cfg.Threshold,
 		pin:       cfg.Pin,
+		// the member sets changed by the proposals
+		membershipStore: membershipStr,
+		backfill:        newBackfill(),
 	}
 }
 
@@ -38,10 +42,80 @@ type walletService struct {
 	members   []string
 	threshold uint8
 	pin       string
+
+	membershipStore core.IMembershipStore
+	backfill        *backfill
 }
 
+// Pull fetch the outputs of all the member sets, the outputs of the retired member sets are spent until merged into the current one
 func (s *walletService) Pull(ctx context.Context, offset time.Time, limit int) ([]*core.Output, error) {
-	outputs, err := s.client.ReadMultisigOutputs(ctx, s.members, s.threshold, offset, limit)
+	memberships, err := s.membershipStore.All(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		results = make([]*core.Output, 0, limit)
+		pulled  = map[string]bool{}
+		// the outputs of the memberships in backfill
+		backfills = map[*core.Membership][]*core.Output{}
+	)
+
+	pull := func(members []string, threshold uint8, offset time.Time) ([]*core.Output, error) {
+		key := fmt.Sprintf("%s:%d", mixin.HashMembers(members), threshold)
+		if pulled[key] {
+			return nil, nil
+		}
+		pulled[key] = true
+
+		outputs, err := s.pull(ctx, members, threshold, offset, limit)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, outputs...)
+		return outputs, nil
+	}
+
+	if _, err := pull(s.members, s.threshold, offset); err != nil {
+		return nil, err
+	}
+
+	for _, m := range memberships {
+		from := s.backfill.offset(m, offset)
+		outputs, err := pull(m.MemberIDs(), m.Threshold, from)
+		if err != nil {
+			return nil, err
+		}
+
+		if from.Before(offset) {
+			backfills[m] = outputs
+		}
+	}
+
+	// the outputs after the limit are pulled again from the offset of the last one
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].UpdatedAt.Before(results[j].UpdatedAt)
+	})
+
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	returned := make(map[string]bool, len(results))
+	for _, output := range results {
+		returned[output.TraceID] = true
+	}
+
+	for m, outputs := range backfills {
+		s.backfill.update(m, outputs, returned, limit)
+	}
+
+	return results, nil
+}
+
+func (s *walletService) pull(ctx context.Context, members []string, threshold uint8, offset time.Time, limit int) ([]*core.Output, error) {
+	outputs, err := s.client.ReadMultisigOutputs(ctx, members, threshold, offset, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -76,7 +150,7 @@ func (s *walletService) Spent(ctx context.Context, outputs []*core.Output, trans
 			return nil, err
 		}
 
-		if err := s.validateTransaction(tx, transfer); err != nil {
+		if err := s.validateTransaction(tx, transfer, outputs[0].UTXO); err != nil {
 			return nil, fmt.Errorf("validateTransaction failed: %w", err)
 		}
 
@@ -204,7 +278,9 @@ func (s *walletService) validateMultisig(req *mixin.MultisigRequest, transfer *c
 }
 
 // validateTransaction validate spent Tx
-func (s *walletService) validateTransaction(tx *mixin.Transaction, transfer *core.Transfer) error {
+//
+// the change goes back to the member set of the spent utxo
+func (s *walletService) validateTransaction(tx *mixin.Transaction, transfer *core.Transfer, utxo *mixin.MultisigUTXO) error {
 	if string(tx.Extra) != transfer.Memo {
 		return fmt.Errorf("memo not match, expect %q got %q", transfer.Memo, string(tx.Extra))
 	}
@@ -228,11 +304,11 @@ func (s *walletService) validateTransaction(tx *mixin.Transaction, transfer *cor
 				return errors.New("receivers not match")
 			}
 		case 1: // 检查找零
-			if expect, got := mixin.NewThresholdScript(s.threshold).String(), output.Script.String(); expect != got {
+			if expect, got := mixin.NewThresholdScript(utxo.Threshold).String(), output.Script.String(); expect != got {
 				return fmt.Errorf("first output script not matched, expect %s got %s", expect, got)
 			}
 
-			if len(output.Keys) != len(s.members) {
+			if len(output.Keys) != len(utxo.Members) {
 				return errors.New("receivers not match")
 			}
 		default:
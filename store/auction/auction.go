@@ -0,0 +1,94 @@
+package auction
+
+import (
+	"compound/core"
+	"compound/pkg/dbtx"
+	"context"
+	"time"
+
+	"github.com/fox-one/pkg/store/db"
+	"github.com/jinzhu/gorm"
+)
+
+type auctionStore struct {
+	db *db.DB
+}
+
+// New new auction store
+func New(db *db.DB) core.IAuctionStore {
+	return &auctionStore{
+		db: db,
+	}
+}
+
+func init() {
+	db.RegisterMigrate(func(db *db.DB) error {
+		tx := db.Update().Model(core.Auction{})
+		if err := tx.AutoMigrate(core.Auction{}).Error; err != nil {
+			return err
+		}
+
+		tx = db.Update().Model(core.AuctionBid{})
+		if err := tx.AutoMigrate(core.AuctionBid{}).Error; err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (s *auctionStore) Save(ctx context.Context, tx *db.DB, auction *core.Auction) error {
+	return tx.Update().Where("trace_id=?", auction.TraceID).Create(auction).Error
+}
+
+func (s *auctionStore) Find(ctx context.Context, traceID string) (*core.Auction, bool, error) {
+	var auction core.Auction
+	if e := dbtx.View(ctx, s.db).Where("trace_id=?", traceID).First(&auction).Error; e != nil {
+		return nil, gorm.IsRecordNotFoundError(e), e
+	}
+
+	return &auction, false, nil
+}
+
+func (s *auctionStore) All(ctx context.Context) ([]*core.Auction, error) {
+	var auctions []*core.Auction
+	if e := dbtx.View(ctx, s.db).Order("id DESC").Find(&auctions).Error; e != nil {
+		return nil, e
+	}
+
+	return auctions, nil
+}
+
+func (s *auctionStore) ListExpired(ctx context.Context, t time.Time) ([]*core.Auction, error) {
+	var auctions []*core.Auction
+	if e := dbtx.View(ctx, s.db).Where("status=? and end_at<=?", core.AuctionStatusOpen, t).Order("id ASC").Find(&auctions).Error; e != nil {
+		return nil, e
+	}
+
+	return auctions, nil
+}
+
+func (s *auctionStore) Update(ctx context.Context, tx *db.DB, auction *core.Auction) error {
+	version := auction.Version
+	auction.Version++
+	return tx.Update().Model(core.Auction{}).Where("trace_id=? and version=?", auction.TraceID, version).Updates(auction).Error
+}
+
+func (s *auctionStore) SaveBid(ctx context.Context, tx *db.DB, bid *core.AuctionBid) error {
+	return tx.Update().Where("trace_id=?", bid.TraceID).Create(bid).Error
+}
+
+func (s *auctionStore) ListBids(ctx context.Context, auctionID string) ([]*core.AuctionBid, error) {
+	var bids []*core.AuctionBid
+	if e := dbtx.View(ctx, s.db).Where("auction_id=?", auctionID).Order("id ASC").Find(&bids).Error; e != nil {
+		return nil, e
+	}
+
+	return bids, nil
+}
+
+func (s *auctionStore) UpdateBid(ctx context.Context, tx *db.DB, bid *core.AuctionBid) error {
+	version := bid.Version
+	bid.Version++
+	return tx.Update().Model(core.AuctionBid{}).Where("trace_id=? and version=?", bid.TraceID, version).Updates(bid).Error
+}
@@ -2,10 +2,12 @@ package borrow
 
 import (
 	"compound/core"
+	"compound/pkg/dbtx"
 	"context"
 
 	"github.com/fox-one/pkg/store/db"
 	"github.com/jinzhu/gorm"
+	"github.com/shopspring/decimal"
 )
 
 type borrowStore struct {
@@ -40,7 +42,7 @@ func (s *borrowStore) Save(ctx context.Context, tx *db.DB, borrow *core.Borrow)
 
 func (s *borrowStore) Find(ctx context.Context, userID string, assetID string) (*core.Borrow, bool, error) {
 	var borrow core.Borrow
-	if e := s.db.View().Where("user_id=? and asset_id=?", userID, assetID).First(&borrow).Error; e != nil {
+	if e := dbtx.View(ctx, s.db).Where("user_id=? and asset_id=?", userID, assetID).First(&borrow).Error; e != nil {
 		return nil, gorm.IsRecordNotFoundError(e), e
 	}
 
@@ -49,7 +51,7 @@ func (s *borrowStore) Find(ctx context.Context, userID string, assetID string) (
 
 func (s *borrowStore) FindByUser(ctx context.Context, userID string) ([]*core.Borrow, error) {
 	var borrows []*core.Borrow
-	if e := s.db.View().Where("user_id=?", userID).Find(&borrows).Error; e != nil {
+	if e := dbtx.View(ctx, s.db).Where("user_id=?", userID).Find(&borrows).Error; e != nil {
 		return nil, e
 	}
 
@@ -58,7 +60,17 @@ func (s *borrowStore) FindByUser(ctx context.Context, userID string) ([]*core.Bo
 
 func (s *borrowStore) FindByAssetID(ctx context.Context, assetID string) ([]*core.Borrow, error) {
 	var borrows []*core.Borrow
-	if e := s.db.View().Where("asset_id=?", assetID).Find(&borrows).Error; e != nil {
+	if e := dbtx.View(ctx, s.db).Where("asset_id=?", assetID).Find(&borrows).Error; e != nil {
+		return nil, e
+	}
+
+	return borrows, nil
+}
+
+func (s *borrowStore) ListStableBelow(ctx context.Context, assetID string, rate decimal.Decimal, limit int) ([]*core.Borrow, error) {
+	var borrows []*core.Borrow
+	if e := dbtx.View(ctx, s.db).Where("asset_id=? and rate_mode=? and principal>0 and stable_rate<?", assetID, core.BorrowRateModeStable, rate).
+		Order("stable_rate, id").Limit(limit).Find(&borrows).Error; e != nil {
 		return nil, e
 	}
 
@@ -77,7 +89,7 @@ func (s *borrowStore) Update(ctx context.Context, tx *db.DB, borrow *core.Borrow
 
 func (s *borrowStore) All(ctx context.Context) ([]*core.Borrow, error) {
 	var borrows []*core.Borrow
-	if e := s.db.View().Find(&borrows).Error; e != nil {
+	if e := dbtx.View(ctx, s.db).Find(&borrows).Error; e != nil {
 		return nil, e
 	}
 
@@ -86,7 +98,7 @@ func (s *borrowStore) All(ctx context.Context) ([]*core.Borrow, error) {
 
 func (s *borrowStore) CountOfBorrowers(ctx context.Context, assetID string) (int64, error) {
 	var count int64
-	if e := s.db.View().Model(core.Borrow{}).Select("count(user_id)").Where("asset_id=?", assetID).Row().Scan(&count); e != nil {
+	if e := dbtx.View(ctx, s.db).Model(core.Borrow{}).Select("count(user_id)").Where("asset_id=?", assetID).Row().Scan(&count); e != nil {
 		return 0, e
 	}
 
@@ -95,7 +107,7 @@ func (s *borrowStore) CountOfBorrowers(ctx context.Context, assetID string) (int
 
 func (s *borrowStore) Users(ctx context.Context) ([]string, error) {
 	var users []string
-	if e := s.db.View().Model(core.Borrow{}).Select("distinct user_id").Pluck("user_id", &users).Error; e != nil {
+	if e := dbtx.View(ctx, s.db).Model(core.Borrow{}).Select("distinct user_id").Pluck("user_id", &users).Error; e != nil {
 		return nil, e
 	}
 
@@ -0,0 +1,65 @@
+package category
+
+import (
+	"compound/core"
+	"compound/pkg/dbtx"
+	"context"
+	"errors"
+
+	"github.com/fox-one/pkg/store/db"
+	"github.com/jinzhu/gorm"
+)
+
+type categoryStore struct {
+	db *db.DB
+}
+
+// New new e-mode category store
+func New(db *db.DB) core.ICategoryStore {
+	return &categoryStore{
+		db: db,
+	}
+}
+
+func init() {
+	db.RegisterMigrate(func(db *db.DB) error {
+		tx := db.Update().Model(core.Category{})
+		if err := tx.AutoMigrate(core.Category{}).Error; err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (s *categoryStore) Save(ctx context.Context, tx *db.DB, category *core.Category) error {
+	return tx.Update().Where("name=?", category.Name).Create(category).Error
+}
+
+func (s *categoryStore) Find(ctx context.Context, name string) (*core.Category, bool, error) {
+	if name == "" {
+		return nil, true, errors.New("invalid name")
+	}
+
+	var category core.Category
+	if e := dbtx.View(ctx, s.db).Where("name=?", name).First(&category).Error; e != nil {
+		return nil, gorm.IsRecordNotFoundError(e), e
+	}
+
+	return &category, false, nil
+}
+
+func (s *categoryStore) All(ctx context.Context) ([]*core.Category, error) {
+	var categories []*core.Category
+	if e := s.db.View().Order("id ASC").Find(&categories).Error; e != nil {
+		return nil, e
+	}
+
+	return categories, nil
+}
+
+func (s *categoryStore) Update(ctx context.Context, tx *db.DB, category *core.Category) error {
+	version := category.Version
+	category.Version++
+	return tx.Update().Model(core.Category{}).Where("name=? and version=?", category.Name, version).Updates(category).Error
+}
@@ -0,0 +1,69 @@
+package delegation
+
+import (
+	"compound/core"
+	"compound/pkg/dbtx"
+	"context"
+
+	"github.com/fox-one/pkg/store/db"
+	"github.com/jinzhu/gorm"
+)
+
+type delegationStore struct {
+	db *db.DB
+}
+
+// New new delegation store
+func New(db *db.DB) core.IDelegationStore {
+	return &delegationStore{
+		db: db,
+	}
+}
+
+func init() {
+	db.RegisterMigrate(func(db *db.DB) error {
+		tx := db.Update().Model(core.Delegation{})
+		if err := tx.AutoMigrate(core.Delegation{}).Error; err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (s *delegationStore) Save(ctx context.Context, tx *db.DB, delegation *core.Delegation) error {
+	return tx.Update().Where("delegator=? and delegatee=? and asset_id=?", delegation.Delegator, delegation.Delegatee, delegation.AssetID).Create(delegation).Error
+}
+
+func (s *delegationStore) Find(ctx context.Context, delegator, delegatee, assetID string) (*core.Delegation, bool, error) {
+	var delegation core.Delegation
+	if e := dbtx.View(ctx, s.db).Where("delegator=? and delegatee=? and asset_id=?", delegator, delegatee, assetID).First(&delegation).Error; e != nil {
+		return nil, gorm.IsRecordNotFoundError(e), e
+	}
+
+	return &delegation, false, nil
+}
+
+func (s *delegationStore) FindByDelegator(ctx context.Context, delegator string) ([]*core.Delegation, error) {
+	var delegations []*core.Delegation
+	if e := dbtx.View(ctx, s.db).Where("delegator=?", delegator).Order("id ASC").Find(&delegations).Error; e != nil {
+		return nil, e
+	}
+
+	return delegations, nil
+}
+
+func (s *delegationStore) FindByDelegatee(ctx context.Context, delegatee string) ([]*core.Delegation, error) {
+	var delegations []*core.Delegation
+	if e := dbtx.View(ctx, s.db).Where("delegatee=?", delegatee).Order("id ASC").Find(&delegations).Error; e != nil {
+		return nil, e
+	}
+
+	return delegations, nil
+}
+
+func (s *delegationStore) Update(ctx context.Context, tx *db.DB, delegation *core.Delegation) error {
+	version := delegation.Version
+	delegation.Version++
+	return tx.Update().Model(core.Delegation{}).Where("delegator=? and delegatee=? and asset_id=? and version=?", delegation.Delegator, delegation.Delegatee, delegation.AssetID, version).Updates(delegation).Error
+}
@@ -0,0 +1,70 @@
+package flashloan
+
+import (
+	"compound/core"
+	"compound/pkg/dbtx"
+	"context"
+	"time"
+
+	"github.com/fox-one/pkg/store/db"
+	"github.com/jinzhu/gorm"
+)
+
+type flashLoanStore struct {
+	db *db.DB
+}
+
+// New new flash loan store
+func New(db *db.DB) core.IFlashLoanStore {
+	return &flashLoanStore{
+		db: db,
+	}
+}
+
+func init() {
+	db.RegisterMigrate(func(db *db.DB) error {
+		tx := db.Update().Model(core.FlashLoan{})
+		if err := tx.AutoMigrate(core.FlashLoan{}).Error; err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (s *flashLoanStore) Save(ctx context.Context, tx *db.DB, loan *core.FlashLoan) error {
+	return tx.Update().Where("trace_id=?", loan.TraceID).Create(loan).Error
+}
+
+func (s *flashLoanStore) FindPending(ctx context.Context, userID, assetID string) (*core.FlashLoan, bool, error) {
+	var loan core.FlashLoan
+	if e := dbtx.View(ctx, s.db).Where("user_id=? and asset_id=? and status=?", userID, assetID, core.FlashLoanStatusPending).First(&loan).Error; e != nil {
+		return nil, gorm.IsRecordNotFoundError(e), e
+	}
+
+	return &loan, false, nil
+}
+
+func (s *flashLoanStore) FindPendingByUser(ctx context.Context, userID string) ([]*core.FlashLoan, error) {
+	var loans []*core.FlashLoan
+	if e := dbtx.View(ctx, s.db).Where("user_id=? and status=?", userID, core.FlashLoanStatusPending).Find(&loans).Error; e != nil {
+		return nil, e
+	}
+
+	return loans, nil
+}
+
+func (s *flashLoanStore) ListExpired(ctx context.Context, t time.Time) ([]*core.FlashLoan, error) {
+	var loans []*core.FlashLoan
+	if e := dbtx.View(ctx, s.db).Where("status=? and expired_at<?", core.FlashLoanStatusPending, t).Order("id ASC").Find(&loans).Error; e != nil {
+		return nil, e
+	}
+
+	return loans, nil
+}
+
+func (s *flashLoanStore) Update(ctx context.Context, tx *db.DB, loan *core.FlashLoan) error {
+	version := loan.Version
+	loan.Version++
+	return tx.Update().Model(core.FlashLoan{}).Where("trace_id=? and version=?", loan.TraceID, version).Updates(loan).Error
+}
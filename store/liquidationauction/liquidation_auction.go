@@ -0,0 +1,60 @@
+package liquidationauction
+
+import (
+	"compound/core"
+	"compound/pkg/dbtx"
+	"context"
+
+	"github.com/fox-one/pkg/store/db"
+	"github.com/jinzhu/gorm"
+)
+
+type liquidationAuctionStore struct {
+	db *db.DB
+}
+
+// New new liquidation auction store
+func New(db *db.DB) core.ILiquidationAuctionStore {
+	return &liquidationAuctionStore{
+		db: db,
+	}
+}
+
+func init() {
+	db.RegisterMigrate(func(db *db.DB) error {
+		tx := db.Update().Model(core.LiquidationAuction{})
+		if err := tx.AutoMigrate(core.LiquidationAuction{}).Error; err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (s *liquidationAuctionStore) Save(ctx context.Context, tx *db.DB, auction *core.LiquidationAuction) error {
+	return tx.Update().Where("trace_id=?", auction.TraceID).Create(auction).Error
+}
+
+func (s *liquidationAuctionStore) FindOpen(ctx context.Context, userID string) (*core.LiquidationAuction, bool, error) {
+	var auction core.LiquidationAuction
+	if e := dbtx.View(ctx, s.db).Where("user_id=? and status=?", userID, core.LiquidationAuctionStatusOpen).First(&auction).Error; e != nil {
+		return nil, gorm.IsRecordNotFoundError(e), e
+	}
+
+	return &auction, false, nil
+}
+
+func (s *liquidationAuctionStore) ListOpen(ctx context.Context) ([]*core.LiquidationAuction, error) {
+	var auctions []*core.LiquidationAuction
+	if e := dbtx.View(ctx, s.db).Where("status=?", core.LiquidationAuctionStatusOpen).Order("id ASC").Find(&auctions).Error; e != nil {
+		return nil, e
+	}
+
+	return auctions, nil
+}
+
+func (s *liquidationAuctionStore) Update(ctx context.Context, tx *db.DB, auction *core.LiquidationAuction) error {
+	version := auction.Version
+	auction.Version++
+	return tx.Update().Model(core.LiquidationAuction{}).Where("trace_id=? and version=?", auction.TraceID, version).Updates(auction).Error
+}
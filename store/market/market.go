@@ -2,11 +2,13 @@ package market
 
 import (
 	"compound/core"
+	"compound/pkg/dbtx"
 	"context"
 	"errors"
 
 	"github.com/fox-one/pkg/store/db"
 	"github.com/jinzhu/gorm"
+	"github.com/shopspring/decimal"
 )
 
 type marketStore struct {
@@ -38,7 +40,7 @@ func (s *marketStore) Find(ctx context.Context, assetID string) (*core.Market, b
 	}
 
 	var market core.Market
-	if err := s.db.View().Where("asset_id=?", assetID).First(&market).Error; err != nil {
+	if err := dbtx.View(ctx, s.db).Where("asset_id=?", assetID).First(&market).Error; err != nil {
 		return nil, gorm.IsRecordNotFoundError(err), err
 	}
 
@@ -51,7 +53,7 @@ func (s *marketStore) FindBySymbol(ctx context.Context, symbol string) (*core.Ma
 	}
 
 	var market core.Market
-	if err := s.db.View().Where("symbol=?", symbol).First(&market).Error; err != nil {
+	if err := dbtx.View(ctx, s.db).Where("symbol=?", symbol).First(&market).Error; err != nil {
 		return nil, gorm.IsRecordNotFoundError(err), err
 	}
 
@@ -64,7 +66,7 @@ func (s *marketStore) FindByCToken(ctx context.Context, ctokenAssetID string) (*
 	}
 
 	var market core.Market
-	if err := s.db.View().Where("c_token_asset_id=?", ctokenAssetID).First(&market).Error; err != nil {
+	if err := dbtx.View(ctx, s.db).Where("c_token_asset_id=?", ctokenAssetID).First(&market).Error; err != nil {
 		return nil, gorm.IsRecordNotFoundError(err), err
 	}
 
@@ -73,7 +75,7 @@ func (s *marketStore) FindByCToken(ctx context.Context, ctokenAssetID string) (*
 
 func (s *marketStore) All(ctx context.Context) ([]*core.Market, error) {
 	var markets []*core.Market
-	if err := s.db.View().Find(&markets).Error; err != nil {
+	if err := dbtx.View(ctx, s.db).Find(&markets).Error; err != nil {
 		return nil, err
 	}
 	return markets, nil
@@ -97,8 +99,41 @@ func (s *marketStore) AllAsMap(ctx context.Context) (map[string]*core.Market, er
 func (s *marketStore) Update(ctx context.Context, tx *db.DB, market *core.Market) error {
 	version := market.Version
 	market.Version++
-	if err := tx.Update().Model(core.Market{}).Where("asset_id=? and version=?", market.AssetID, version).Updates(market).Error; err != nil {
-		return err
+	update := tx.Update().Model(core.Market{}).Where("asset_id=? and version=?", market.AssetID, version).Updates(market)
+	if update.Error != nil {
+		return update.Error
+	}
+
+	// the market was updated by another copy read before
+	if update.RowsAffected == 0 {
+		return db.ErrOptimisticLock
+	}
+
+	// the blank values are skipped by Updates
+	blanks := make(map[string]interface{})
+	if market.FlashLoans.IsZero() {
+		blanks["flash_loans"] = decimal.Zero
+	}
+	if market.Category == "" {
+		blanks["category"] = ""
+	}
+	if market.TwapWindow == 0 {
+		blanks["twap_window"] = 0
+	}
+	if market.MaxPriceAge == 0 {
+		blanks["max_price_age"] = 0
+	}
+	if market.MaxPriceChange.IsZero() {
+		blanks["max_price_change"] = decimal.Zero
+	}
+	if market.PriceTolerance.IsZero() {
+		blanks["price_tolerance"] = decimal.Zero
+	}
+
+	for column, value := range blanks {
+		if err := tx.Update().Model(core.Market{}).Where("asset_id=?", market.AssetID).UpdateColumn(column, value).Error; err != nil {
+			return err
+		}
 	}
 
 	return nil
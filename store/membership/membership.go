@@ -0,0 +1,54 @@
+package membership
+
+import (
+	"compound/core"
+	"context"
+	"time"
+
+	"github.com/fox-one/pkg/store/db"
+	"github.com/jinzhu/gorm"
+)
+
+type membershipStore struct {
+	db *db.DB
+}
+
+// New new membership store
+func New(db *db.DB) core.IMembershipStore {
+	return &membershipStore{
+		db: db,
+	}
+}
+
+func init() {
+	db.RegisterMigrate(func(db *db.DB) error {
+		tx := db.Update().Model(core.Membership{})
+		if err := tx.AutoMigrate(core.Membership{}).Error; err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (s *membershipStore) Save(ctx context.Context, tx *db.DB, membership *core.Membership) error {
+	return tx.Update().Where("trace_id=?", membership.TraceID).FirstOrCreate(membership).Error
+}
+
+func (s *membershipStore) FindEffective(ctx context.Context, t time.Time) (*core.Membership, bool, error) {
+	var membership core.Membership
+	if e := s.db.View().Where("effective_at < ?", t).Order("effective_at DESC, id DESC").First(&membership).Error; e != nil {
+		return nil, gorm.IsRecordNotFoundError(e), e
+	}
+
+	return &membership, false, nil
+}
+
+func (s *membershipStore) All(ctx context.Context) ([]*core.Membership, error) {
+	var memberships []*core.Membership
+	if e := s.db.View().Order("id ASC").Find(&memberships).Error; e != nil {
+		return nil, e
+	}
+
+	return memberships, nil
+}
@@ -2,6 +2,7 @@ package price
 
 import (
 	"compound/core"
+	"compound/pkg/dbtx"
 	"context"
 	"time"
 
@@ -38,7 +39,7 @@ func (s *priceStore) Create(ctx context.Context, tx *db.DB, price *core.Price) e
 
 func (s *priceStore) FindByAssetBlock(ctx context.Context, assetID string, blockNumber int64) (*core.Price, bool, error) {
 	var price core.Price
-	if e := s.db.View().Where("asset_id=? and block_number=?", assetID, blockNumber).Find(&price).Error; e != nil {
+	if e := dbtx.View(ctx, s.db).Where("asset_id=? and block_number=?", assetID, blockNumber).Find(&price).Error; e != nil {
 		return nil, gorm.IsRecordNotFoundError(e), e
 	}
 	return &price, false, nil
@@ -50,6 +51,30 @@ func (s *priceStore) Update(ctx context.Context, tx *db.DB, price *core.Price) e
 	return tx.Update().Model(core.Price{}).Where("asset_id=? and block_number=? and version=?", price.AssetID, price.BlockNumber, version).Updates(price).Error
 }
 
+func (s *priceStore) FindLatestPassed(ctx context.Context, assetID string, blockNumber int64) (*core.Price, bool, error) {
+	var price core.Price
+	if e := dbtx.View(ctx, s.db).Where("asset_id=? and block_number<? and passed_at is not null", assetID, blockNumber).Order("block_number desc").First(&price).Error; e != nil {
+		return nil, gorm.IsRecordNotFoundError(e), e
+	}
+	return &price, false, nil
+}
+
+func (s *priceStore) FindPassedBefore(ctx context.Context, assetID string, t time.Time) (*core.Price, bool, error) {
+	var price core.Price
+	if e := dbtx.View(ctx, s.db).Where("asset_id=? and passed_at<? and status<>?", assetID, t, core.PriceStatusPending).Order("passed_at desc").First(&price).Error; e != nil {
+		return nil, gorm.IsRecordNotFoundError(e), e
+	}
+	return &price, false, nil
+}
+
+func (s *priceStore) ListPassed(ctx context.Context, assetID string, from, to time.Time) ([]*core.Price, error) {
+	var prices []*core.Price
+	if e := dbtx.View(ctx, s.db).Where("asset_id=? and passed_at>=? and passed_at<=? and status<>?", assetID, from, to, core.PriceStatusPending).Order("passed_at").Find(&prices).Error; e != nil {
+		return nil, e
+	}
+	return prices, nil
+}
+
 func (s *priceStore) DeleteByTime(ctx context.Context, t time.Time) error {
 	return s.db.Update().Where("created_at < ?", t).Delete(core.Price{}).Error
 }
@@ -2,8 +2,10 @@ package proposal
 
 import (
 	"context"
+	"time"
 
 	"compound/core"
+	"compound/pkg/dbtx"
 
 	"github.com/fox-one/pkg/store/db"
 	"github.com/jinzhu/gorm"
@@ -21,6 +23,16 @@ func init() {
 			return err
 		}
 
+		// the content of the batch proposal may exceed the varchar(1024) column created before
+		if err := tx.ModifyColumn("content", "text").Error; err != nil {
+			return err
+		}
+
+		// the proposals passed before the statuses added were all executed
+		if err := tx.Where("passed_at is not null and status in (?)", []core.ProposalStatus{core.ProposalStatusCreated, core.ProposalStatusPassed}).UpdateColumn("status", core.ProposalStatusExecuted).Error; err != nil {
+			return err
+		}
+
 		return nil
 	})
 }
@@ -35,12 +47,12 @@ type proposalStore struct {
 }
 
 func (s *proposalStore) Create(ctx context.Context, proposal *core.Proposal) error {
-	return s.db.Update().Where("trace_id = ?", proposal.TraceID).FirstOrCreate(proposal).Error
+	return dbtx.Update(ctx, s.db).Where("trace_id = ?", proposal.TraceID).FirstOrCreate(proposal).Error
 }
 
 func (s *proposalStore) Find(ctx context.Context, trace string) (*core.Proposal, bool, error) {
 	var proposal core.Proposal
-	if err := s.db.View().Where("trace_id = ?", trace).First(&proposal).Error; err != nil {
+	if err := dbtx.View(ctx, s.db).Where("trace_id = ?", trace).First(&proposal).Error; err != nil {
 		return nil, gorm.IsRecordNotFoundError(err), err
 	}
 
@@ -51,6 +63,9 @@ func toUpdateParams(proposal *core.Proposal) map[string]interface{} {
 	return map[string]interface{}{
 		"passed_at": proposal.PassedAt,
 		"votes":     proposal.Votes,
+		"status":    proposal.Status,
+
+		"executable_at": proposal.ExecutableAt,
 	}
 }
 
@@ -58,7 +73,7 @@ func (s *proposalStore) Update(ctx context.Context, proposal *core.Proposal) err
 	updates := toUpdateParams(proposal)
 	updates["version"] = proposal.Version + 1
 
-	tx := s.db.Update().Model(proposal).Where("version = ?", proposal.Version).Updates(updates)
+	tx := dbtx.Update(ctx, s.db).Model(proposal).Where("version = ?", proposal.Version).Updates(updates)
 	if tx.Error != nil {
 		return tx.Error
 	}
@@ -72,7 +87,34 @@ func (s *proposalStore) Update(ctx context.Context, proposal *core.Proposal) err
 
 func (s *proposalStore) List(ctx context.Context, fromID int64, limit int) ([]*core.Proposal, error) {
 	var proposals []*core.Proposal
-	if err := s.db.View().Where("id > ?", fromID).Limit(limit).Find(&proposals).Error; err != nil {
+	if err := dbtx.View(ctx, s.db).Where("id > ?", fromID).Limit(limit).Find(&proposals).Error; err != nil {
+		return nil, err
+	}
+
+	return proposals, nil
+}
+
+func (s *proposalStore) ListExpired(ctx context.Context, t time.Time) ([]*core.Proposal, error) {
+	var proposals []*core.Proposal
+	if err := dbtx.View(ctx, s.db).Where("status=? and expires_at<=?", core.ProposalStatusCreated, t).Order("id").Find(&proposals).Error; err != nil {
+		return nil, err
+	}
+
+	return proposals, nil
+}
+
+func (s *proposalStore) ListQueued(ctx context.Context) ([]*core.Proposal, error) {
+	var proposals []*core.Proposal
+	if err := dbtx.View(ctx, s.db).Where("status=?", core.ProposalStatusQueued).Order("executable_at, id").Find(&proposals).Error; err != nil {
+		return nil, err
+	}
+
+	return proposals, nil
+}
+
+func (s *proposalStore) ListExecutable(ctx context.Context, t time.Time) ([]*core.Proposal, error) {
+	var proposals []*core.Proposal
+	if err := dbtx.View(ctx, s.db).Where("status=? and executable_at<=?", core.ProposalStatusQueued, t).Order("executable_at, id").Find(&proposals).Error; err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,95 @@
+package reward
+
+import (
+	"compound/core"
+	"compound/pkg/dbtx"
+	"context"
+
+	"github.com/fox-one/pkg/store/db"
+	"github.com/jinzhu/gorm"
+)
+
+type rewardStore struct {
+	db *db.DB
+}
+
+// New new reward store
+func New(db *db.DB) core.IRewardStore {
+	return &rewardStore{
+		db: db,
+	}
+}
+
+func init() {
+	db.RegisterMigrate(func(db *db.DB) error {
+		tx := db.Update().Model(core.Reward{})
+		if err := tx.AutoMigrate(core.Reward{}).Error; err != nil {
+			return err
+		}
+
+		tx = db.Update().Model(core.RewardPool{})
+		if err := tx.AutoMigrate(core.RewardPool{}).Error; err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (s *rewardStore) Save(ctx context.Context, tx *db.DB, reward *core.Reward) error {
+	return tx.Update().Where("user_id=? and asset_id=?", reward.UserID, reward.AssetID).Create(reward).Error
+}
+
+func (s *rewardStore) Find(ctx context.Context, userID, assetID string) (*core.Reward, bool, error) {
+	var reward core.Reward
+	if e := dbtx.View(ctx, s.db).Where("user_id=? and asset_id=?", userID, assetID).First(&reward).Error; e != nil {
+		return nil, gorm.IsRecordNotFoundError(e), e
+	}
+
+	return &reward, false, nil
+}
+
+func (s *rewardStore) FindByUser(ctx context.Context, userID string) ([]*core.Reward, error) {
+	var rewards []*core.Reward
+	if e := dbtx.View(ctx, s.db).Where("user_id=?", userID).Order("id ASC").Find(&rewards).Error; e != nil {
+		return nil, e
+	}
+
+	return rewards, nil
+}
+
+func (s *rewardStore) Update(ctx context.Context, tx *db.DB, reward *core.Reward) error {
+	version := reward.Version
+	reward.Version++
+	return tx.Update().Model(core.Reward{}).Where("user_id=? and asset_id=? and version=?", reward.UserID, reward.AssetID, version).Updates(reward).Error
+}
+
+func (s *rewardStore) SavePool(ctx context.Context, tx *db.DB, pool *core.RewardPool) error {
+	return tx.Update().Where("asset_id=?", pool.AssetID).Create(pool).Error
+}
+
+func (s *rewardStore) FindPool(ctx context.Context, assetID string) (*core.RewardPool, bool, error) {
+	var pool core.RewardPool
+	if e := dbtx.View(ctx, s.db).Where("asset_id=?", assetID).First(&pool).Error; e != nil {
+		return nil, gorm.IsRecordNotFoundError(e), e
+	}
+
+	return &pool, false, nil
+}
+
+func (s *rewardStore) UpdatePool(ctx context.Context, tx *db.DB, pool *core.RewardPool) error {
+	version := pool.Version
+	pool.Version++
+	update := tx.Update().Model(core.RewardPool{}).Where("asset_id=? and version=?", pool.AssetID, version).
+		Updates(map[string]interface{}{"balance": pool.Balance, "version": pool.Version})
+	if update.Error != nil {
+		return update.Error
+	}
+
+	// the pool was updated by another copy read before
+	if update.RowsAffected == 0 {
+		return db.ErrOptimisticLock
+	}
+
+	return nil
+}
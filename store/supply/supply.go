@@ -2,6 +2,7 @@ package supply
 
 import (
 	"compound/core"
+	"compound/pkg/dbtx"
 	"context"
 
 	"github.com/fox-one/pkg/store/db"
@@ -40,7 +41,7 @@ func (s *supplyStore) Save(ctx context.Context, tx *db.DB, supply *core.Supply)
 }
 func (s *supplyStore) Find(ctx context.Context, userID string, ctokenAssetID string) (*core.Supply, bool, error) {
 	var supply core.Supply
-	if e := s.db.View().Where("user_id=? and c_token_asset_id=?", userID, ctokenAssetID).First(&supply).Error; e != nil {
+	if e := dbtx.View(ctx, s.db).Where("user_id=? and c_token_asset_id=?", userID, ctokenAssetID).First(&supply).Error; e != nil {
 		return nil, gorm.IsRecordNotFoundError(e), e
 	}
 
@@ -49,7 +50,7 @@ func (s *supplyStore) Find(ctx context.Context, userID string, ctokenAssetID str
 
 func (s *supplyStore) FindByUser(ctx context.Context, userID string) ([]*core.Supply, error) {
 	var supplies []*core.Supply
-	if e := s.db.View().Where("user_id=?", userID).Find(&supplies).Error; e != nil {
+	if e := dbtx.View(ctx, s.db).Where("user_id=?", userID).Find(&supplies).Error; e != nil {
 		return nil, e
 	}
 
@@ -68,7 +69,7 @@ func (s *supplyStore) Update(ctx context.Context, tx *db.DB, supply *core.Supply
 
 func (s *supplyStore) All(ctx context.Context) ([]*core.Supply, error) {
 	var supplies []*core.Supply
-	if e := s.db.View().Find(&supplies).Error; e != nil {
+	if e := dbtx.View(ctx, s.db).Find(&supplies).Error; e != nil {
 		return nil, e
 	}
 
@@ -77,7 +78,7 @@ func (s *supplyStore) All(ctx context.Context) ([]*core.Supply, error) {
 
 func (s *supplyStore) FindByCTokenAssetID(ctx context.Context, assetID string) ([]*core.Supply, error) {
 	var supplies []*core.Supply
-	if e := s.db.View().Where("c_token_asset_id=?", assetID).Find(&supplies).Error; e != nil {
+	if e := dbtx.View(ctx, s.db).Where("c_token_asset_id=?", assetID).Find(&supplies).Error; e != nil {
 		return nil, e
 	}
 
@@ -85,7 +86,7 @@ func (s *supplyStore) FindByCTokenAssetID(ctx context.Context, assetID string) (
 }
 func (s *supplyStore) SumOfSupplies(ctx context.Context, ctokenAssetID string) (decimal.Decimal, error) {
 	var sum decimal.Decimal
-	if e := s.db.View().Model(core.Supply{}).Select("sum(collaterals)").Where("c_token_asset_id=?", ctokenAssetID).Row().Scan(&sum); e != nil {
+	if e := dbtx.View(ctx, s.db).Model(core.Supply{}).Select("coalesce(sum(collaterals), 0)").Where("c_token_asset_id=?", ctokenAssetID).Row().Scan(&sum); e != nil {
 		return decimal.Zero, e
 	}
 
@@ -95,7 +96,7 @@ func (s *supplyStore) SumOfSupplies(ctx context.Context, ctokenAssetID string) (
 
 func (s *supplyStore) CountOfSuppliers(ctx context.Context, ctokenAssetID string) (int64, error) {
 	var count int64
-	if e := s.db.View().Model(core.Supply{}).Select("count(user_id)").Where("c_token_asset_id=?", ctokenAssetID).Row().Scan(&count); e != nil {
+	if e := dbtx.View(ctx, s.db).Model(core.Supply{}).Select("count(user_id)").Where("c_token_asset_id=?", ctokenAssetID).Row().Scan(&count); e != nil {
 		return 0, e
 	}
 
@@ -104,7 +105,7 @@ func (s *supplyStore) CountOfSuppliers(ctx context.Context, ctokenAssetID string
 
 func (s *supplyStore) Users(ctx context.Context) ([]string, error) {
 	var users []string
-	if e := s.db.View().Model(core.Supply{}).Select("distinct user_id").Pluck("user_id", &users).Error; e != nil {
+	if e := dbtx.View(ctx, s.db).Model(core.Supply{}).Select("distinct user_id").Pluck("user_id", &users).Error; e != nil {
 		return nil, e
 	}
 
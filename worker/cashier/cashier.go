@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"compound/core"
 	"compound/worker"
@@ -21,6 +22,7 @@ type Cashier struct {
 	worker.TickWorker
 	walletStore   core.WalletStore
 	walletService core.WalletService
+	memberships   core.IMembershipStore
 	system        *core.System
 }
 
@@ -28,11 +30,13 @@ type Cashier struct {
 func New(
 	walletStr core.WalletStore,
 	walletSrv core.WalletService,
+	membershipStr core.IMembershipStore,
 	system *core.System,
 ) *Cashier {
 	cashier := Cashier{
 		walletStore:   walletStr,
 		walletService: walletSrv,
+		memberships:   membershipStr,
 		system:        system,
 	}
 
@@ -76,46 +80,100 @@ func (w *Cashier) handleTransfer(ctx context.Context, transfer *core.Transfer) e
 		return err
 	}
 
+	members, threshold, err := w.currentMembers(ctx, transfer.CreatedAt)
+	if err != nil {
+		log.WithError(err).Errorln("memberships.FindEffective")
+		return err
+	}
+
+	current := groupKey(members, threshold)
+
+	// one transaction only spends the outputs of the same member set
 	var (
-		idx    int
-		sum    decimal.Decimal
-		traces []string
+		keys   []string
+		groups = map[string][]*core.Output{}
 	)
 
 	for _, output := range outputs {
-		sum = sum.Add(output.Amount)
-		traces = append(traces, output.TraceID)
-		idx++
+		key := current
+		if output.UTXO != nil {
+			key = groupKey(output.UTXO.Members, output.UTXO.Threshold)
+		}
+
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], output)
+	}
+
+	sums := make(map[string]decimal.Decimal, len(keys))
+	for _, key := range keys {
+		var (
+			idx int
+			sum decimal.Decimal
+		)
+
+		for _, output := range groups[key] {
+			sum = sum.Add(output.Amount)
+			idx++
+
+			if sum.GreaterThanOrEqual(transfer.Amount) {
+				break
+			}
+		}
+
+		groups[key] = groups[key][:idx]
+		sums[key] = sum
 
 		if sum.GreaterThanOrEqual(transfer.Amount) {
-			break
+			return w.spent(ctx, groups[key], transfer)
 		}
 	}
 
-	outputs = outputs[:idx]
+	for _, key := range keys {
+		// merge outputs, the outputs of the retired member set are merged into the current one
+		if len(outputs) == limit || key != current {
+			var traces []string
+			for _, output := range groups[key] {
+				traces = append(traces, output.TraceID)
+			}
 
-	if sum.LessThan(transfer.Amount) {
-		// merge outputs
-		if len(outputs) == limit {
 			traceID := uuid.Modify(transfer.TraceID, mixin.HashMembers(traces))
 			merge := &core.Transfer{
 				TraceID:   traceID,
 				AssetID:   transfer.AssetID,
-				Amount:    sum,
-				Opponents: w.system.MemberIDs(),
-				Threshold: w.system.Threshold,
+				Amount:    sums[key],
+				Opponents: members,
+				Threshold: threshold,
 				Memo:      fmt.Sprintf("merge for %s", transfer.TraceID),
 			}
 
-			return w.spent(ctx, outputs, merge)
+			return w.spent(ctx, groups[key], merge)
 		}
+	}
 
-		err := errors.New("insufficient balance")
-		log.WithError(err).Errorln("handle transfer", transfer.ID)
-		return err
+	err = errors.New("insufficient balance")
+	log.WithError(err).Errorln("handle transfer", transfer.ID)
+	return err
+}
+
+// currentMembers the member set and threshold effective for the output that made the transfer at t, or the configured ones,
+// so that all the nodes spend to the same member set whenever they handle the transfer
+func (w *Cashier) currentMembers(ctx context.Context, t time.Time) ([]string, uint8, error) {
+	membership, isRecordNotFound, err := w.memberships.FindEffective(ctx, t)
+	if err != nil {
+		if isRecordNotFound {
+			return w.system.MemberIDs(), w.system.Threshold, nil
+		}
+
+		return nil, 0, err
 	}
 
-	return w.spent(ctx, outputs, transfer)
+	return membership.MemberIDs(), membership.Threshold, nil
+}
+
+func groupKey(members []string, threshold uint8) string {
+	return fmt.Sprintf("%s:%d", mixin.HashMembers(members), threshold)
 }
 
 func (w *Cashier) spent(ctx context.Context, outputs []*core.Output, transfer *core.Transfer) error {
@@ -0,0 +1,67 @@
+package cashier
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"compound/core"
+
+	"github.com/fox-one/pkg/store/db"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+)
+
+type memberships []*core.Membership
+
+func (s memberships) Save(_ context.Context, _ *db.DB, _ *core.Membership) error {
+	return nil
+}
+
+func (s memberships) FindEffective(_ context.Context, t time.Time) (*core.Membership, bool, error) {
+	var effective *core.Membership
+	for _, m := range s {
+		if m.EffectiveAt.Before(t) {
+			effective = m
+		}
+	}
+
+	if effective == nil {
+		return nil, true, gorm.ErrRecordNotFound
+	}
+
+	return effective, false, nil
+}
+
+func (s memberships) All(_ context.Context) ([]*core.Membership, error) {
+	return s, nil
+}
+
+func TestCurrentMembers(t *testing.T) {
+	effectiveAt := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	w := New(nil, nil, memberships{
+		core.NewMembership("membership", []*core.Member{{ClientID: "a"}, {ClientID: "b"}, {ClientID: "c"}}, 2, effectiveAt),
+	}, &core.System{
+		Members:   []*core.Member{{ClientID: "a"}, {ClientID: "b"}},
+		Threshold: 1,
+	})
+
+	for _, c := range []struct {
+		name      string
+		t         time.Time
+		members   []string
+		threshold uint8
+	}{
+		{"before the membership", effectiveAt.Add(-time.Second), []string{"a", "b"}, 1},
+		{"at the effective time", effectiveAt, []string{"a", "b"}, 1},
+		{"after the membership", effectiveAt.Add(time.Second), []string{"a", "b", "c"}, 2},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			members, threshold, err := w.currentMembers(context.Background(), c.t)
+			assert.Nil(t, err)
+			assert.Equal(t, c.members, members)
+			assert.Equal(t, c.threshold, threshold)
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"compound/worker"
 	"context"
 	"errors"
+	"time"
 
 	"github.com/fox-one/pkg/logger"
 )
@@ -12,15 +13,22 @@ import (
 // Messager message worker
 type Messager struct {
 	worker.TickWorker
-	messageStore   core.MessageStore
-	messageService core.MessageService
+	messageStore    core.MessageStore
+	messageService  core.MessageService
+	marketStore     core.IMarketStore
+	proposalService core.ProposalService
+	// the price updated at of the stale markets alerted
+	staleAlerted map[string]time.Time
 }
 
 // New new message worker
-func New(messages core.MessageStore, messagez core.MessageService) *Messager {
+func New(messages core.MessageStore, messagez core.MessageService, marketStr core.IMarketStore, proposalSrv core.ProposalService) *Messager {
 	messager := Messager{
-		messageStore:   messages,
-		messageService: messagez,
+		messageStore:    messages,
+		messageService:  messagez,
+		marketStore:     marketStr,
+		proposalService: proposalSrv,
+		staleAlerted:    make(map[string]time.Time),
 	}
 
 	return &messager
@@ -38,6 +46,10 @@ func (w *Messager) onWork(ctx context.Context) error {
 	const Limit = 300
 	const Batch = 70
 
+	if err := w.alertStalePrices(ctx); err != nil {
+		log.WithError(err).Error("alertStalePrices")
+	}
+
 	messages, err := w.messageStore.List(ctx, Limit)
 	if err != nil {
 		log.WithError(err).Error("messengers.ListPair")
@@ -78,3 +90,31 @@ func (w *Messager) onWork(ctx context.Context) error {
 
 	return nil
 }
+
+// alertStalePrices alert the node managers once for each stale market price
+func (w *Messager) alertStalePrices(ctx context.Context) error {
+	markets, err := w.marketStore.All(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, market := range markets {
+		if !market.IsPriceStale(now) {
+			delete(w.staleAlerted, market.AssetID)
+			continue
+		}
+
+		if alerted, ok := w.staleAlerted[market.AssetID]; ok && alerted.Equal(market.PriceUpdatedAt) {
+			continue
+		}
+
+		if err := w.proposalService.PriceStale(ctx, market); err != nil {
+			return err
+		}
+
+		w.staleAlerted[market.AssetID] = market.PriceUpdatedAt
+	}
+
+	return nil
+}
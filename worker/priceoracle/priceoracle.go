@@ -80,7 +80,7 @@ func (w *Worker) onWork(ctx context.Context) error {
 			defer wg.Done()
 			if !w.isPriceProvided(ctx, market) {
 				// pull price ticker from outside
-				ticker, e := w.PriceOracleService.PullPriceTicker(ctx, market.AssetID, time.Now())
+				ticker, e := w.PriceOracleService.PullPriceTicker(ctx, market, time.Now())
 				if e != nil {
 					log.Errorln("pull price ticker error:", e)
 					return
@@ -0,0 +1,367 @@
+package snapshot
+
+import (
+	"compound/core"
+	"compound/core/proposal"
+	"compound/internal/compound"
+	"compound/pkg/mtg"
+	"context"
+	"strings"
+	"time"
+
+	"github.com/fox-one/pkg/logger"
+	"github.com/fox-one/pkg/store/db"
+	uuidutil "github.com/fox-one/pkg/uuid"
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// handleStartAuctionEvent start the auction of the market reserves,
+// the lot is taken out of the reserves and the cash until the auction is settled or expired
+func (w *Payee) handleStartAuctionEvent(ctx context.Context, p *core.Proposal, req proposal.StartAuctionReq, t time.Time) error {
+	return w.transaction(ctx, func(tx *db.DB) error {
+		log := logger.FromContext(ctx).WithField("worker", "start-auction")
+
+		// the auction is started only once
+		if _, _, e := w.auctionStore.Find(ctx, p.TraceID); e == nil {
+			return nil
+		}
+
+		auctionType := core.AuctionType(req.Type)
+		if auctionType != core.AuctionTypeEnglish && auctionType != core.AuctionTypeDutch {
+			log.Warningln("invalid auction type:", req.Type)
+			return nil
+		}
+
+		amount := req.Amount.Truncate(8)
+		if amount.LessThanOrEqual(decimal.Zero) || req.Duration <= 0 || req.EndPrice.LessThanOrEqual(decimal.Zero) {
+			log.Warningln("invalid auction:", amount, req.Duration, req.EndPrice)
+			return nil
+		}
+
+		if auctionType == core.AuctionTypeDutch && req.StartPrice.LessThan(req.EndPrice) {
+			log.Warningln("invalid dutch auction prices:", req.StartPrice, req.EndPrice)
+			return nil
+		}
+
+		market, isRecordNotFound, e := w.marketStore.FindBySymbol(ctx, strings.ToUpper(req.Symbol))
+		if e != nil {
+			if isRecordNotFound {
+				return nil
+			}
+
+			return e
+		}
+
+		if e = w.marketService.AccrueInterest(ctx, tx, market, t); e != nil {
+			return e
+		}
+
+		if amount.GreaterThan(market.Reserves) || amount.GreaterThan(market.TotalCash) {
+			log.Warningln("insufficient reserves:", amount, market.Reserves, market.TotalCash)
+			return nil
+		}
+
+		market.Reserves = market.Reserves.Sub(amount).Truncate(16)
+		market.TotalCash = market.TotalCash.Sub(amount).Truncate(16)
+		if e = w.marketStore.Update(ctx, tx, market); e != nil {
+			log.Errorln(e)
+			return e
+		}
+
+		auction := core.Auction{
+			TraceID:    p.TraceID,
+			Type:       auctionType,
+			AssetID:    market.AssetID,
+			Amount:     amount,
+			BidAssetID: req.BidAsset,
+			Opponent:   req.Opponent,
+			StartPrice: req.StartPrice.Truncate(8),
+			EndPrice:   req.EndPrice.Truncate(8),
+			Status:     core.AuctionStatusOpen,
+			StartAt:    t,
+			EndAt:      t.Add(time.Duration(req.Duration) * time.Second),
+		}
+		if e = w.auctionStore.Save(ctx, tx, &auction); e != nil {
+			log.WithError(e).Errorln("save auction error")
+			return e
+		}
+
+		return nil
+	})
+}
+
+// handle auction bid event
+//
+// the bid of the english auction should be higher than the current highest bid, which is refunded when outbid,
+// the highest bid is held until the auction ends,
+// the first bid reaching the asking price of the dutch auction wins the lot immediately and the excess is refunded
+func (w *Payee) handleAuctionBidEvent(ctx context.Context, tx *db.DB, output *core.Output, userID, followID string, body []byte) error {
+	log := logger.FromContext(ctx).WithField("worker", "auction_bid")
+
+	var auctionID uuid.UUID
+	if _, err := mtg.Scan(body, &auctionID); err != nil {
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeAuctionBid, core.ErrInvalidArgument, "")
+	}
+
+	auction, isRecordNotFound, e := w.auctionStore.Find(ctx, auctionID.String())
+	if isRecordNotFound {
+		log.Warningln("auction not found")
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeAuctionBid, core.ErrAuctionNotFound, "")
+	}
+	if e != nil {
+		log.WithError(e).Errorln("find auction error")
+		return e
+	}
+
+	if auction.Status != core.AuctionStatusOpen || !output.CreatedAt.Before(auction.EndAt) {
+		log.Warningln("auction closed")
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeAuctionBid, core.ErrAuctionClosed, "")
+	}
+
+	if output.AssetID != auction.BidAssetID {
+		log.Warningln("invalid bid asset:", output.AssetID)
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeAuctionBid, core.ErrInvalidArgument, "")
+	}
+
+	price := auction.EndPrice
+	if auction.Type == core.AuctionTypeDutch {
+		price = compound.DutchAuctionPrice(auction.StartPrice, auction.EndPrice, auction.StartAt, auction.EndAt, output.CreatedAt)
+	}
+
+	if output.Amount.LessThan(price) {
+		log.Warningln("bid too low:", output.Amount, ":price:", price)
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeAuctionBid, core.ErrBidTooLow, "")
+	}
+
+	if auction.Type == core.AuctionTypeEnglish && auction.Winner != "" && !output.Amount.GreaterThan(auction.WinningBid) {
+		log.Warningln("bid not higher than the highest bid:", output.Amount, ":highest:", auction.WinningBid)
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeAuctionBid, core.ErrBidTooLow, "")
+	}
+
+	// add transaction
+	extra := core.NewTransactionExtra()
+	extra.Put(core.TransactionKeyReferTrace, auction.TraceID)
+	extra.Put(core.TransactionKeyPrice, price)
+	transaction := core.BuildTransactionFromOutput(ctx, userID, followID, core.ActionTypeAuctionBid, output, &extra)
+	if e = w.transactionStore.Create(ctx, tx, transaction); e != nil {
+		log.WithError(e).Errorln("create transaction error")
+		return e
+	}
+
+	if auction.Type == core.AuctionTypeEnglish {
+		return w.placeEnglishBid(ctx, tx, auction, output, userID, followID)
+	}
+
+	if e = w.settleAuction(ctx, tx, auction, userID, followID, output.TraceID, price); e != nil {
+		return e
+	}
+
+	if excess := output.Amount.Sub(price).Truncate(8); excess.GreaterThan(decimal.Zero) {
+		refundAction := core.TransferAction{
+			Source:   core.ActionTypeAuctionRefundTransfer,
+			FollowID: followID,
+		}
+		return w.transferOut(ctx, tx, userID, followID, output.TraceID, auction.BidAssetID, excess, &refundAction)
+	}
+
+	return nil
+}
+
+// placeEnglishBid refund the outbid highest bid and hold the new one until the auction ends,
+// the highest bid is published on the auction
+func (w *Payee) placeEnglishBid(ctx context.Context, tx *db.DB, auction *core.Auction, output *core.Output, userID, followID string) error {
+	log := logger.FromContext(ctx).WithField("worker", "auction_bid")
+
+	bids, e := w.auctionStore.ListBids(ctx, auction.TraceID)
+	if e != nil {
+		log.WithError(e).Errorln("list bids error")
+		return e
+	}
+
+	for _, bid := range bids {
+		if bid.Status != core.AuctionBidStatusPending {
+			continue
+		}
+
+		refundAction := core.TransferAction{
+			Source:   core.ActionTypeAuctionRefundTransfer,
+			FollowID: bid.FollowID,
+		}
+		if e = w.transferOut(ctx, tx, bid.UserID, bid.FollowID, bid.TraceID, auction.BidAssetID, bid.Amount, &refundAction); e != nil {
+			return e
+		}
+
+		bid.Status = core.AuctionBidStatusRefunded
+		if e = w.auctionStore.UpdateBid(ctx, tx, bid); e != nil {
+			log.WithError(e).Errorln("update bid error")
+			return e
+		}
+	}
+
+	bid := core.AuctionBid{
+		TraceID:   output.TraceID,
+		AuctionID: auction.TraceID,
+		UserID:    userID,
+		FollowID:  followID,
+		Amount:    output.Amount,
+		Status:    core.AuctionBidStatusPending,
+	}
+	if e = w.auctionStore.SaveBid(ctx, tx, &bid); e != nil {
+		log.WithError(e).Errorln("save bid error")
+		return e
+	}
+
+	auction.Winner = userID
+	auction.WinningBid = output.Amount
+	if e = w.auctionStore.Update(ctx, tx, auction); e != nil {
+		log.WithError(e).Errorln("update auction error")
+		return e
+	}
+
+	return nil
+}
+
+// handleExpiredAuctions settle the auctions ended before t,
+// the highest bid of the english auction wins,
+// the lot of the auction without any winning bid is returned to the market reserves
+func (w *Payee) handleExpiredAuctions(ctx context.Context, tx *db.DB, t time.Time) error {
+	log := logger.FromContext(ctx).WithField("worker", "auction_settle")
+
+	auctions, e := w.auctionStore.ListExpired(ctx, t)
+	if e != nil {
+		log.WithError(e).Errorln("list expired auctions error")
+		return e
+	}
+
+	for _, auction := range auctions {
+		var bids []*core.AuctionBid
+		if auction.Type == core.AuctionTypeEnglish {
+			if bids, e = w.auctionStore.ListBids(ctx, auction.TraceID); e != nil {
+				log.WithError(e).Errorln("list bids error")
+				return e
+			}
+		}
+
+		// the earliest of the highest bids wins
+		var winner *core.AuctionBid
+		for _, bid := range bids {
+			if bid.Status == core.AuctionBidStatusPending && (winner == nil || bid.Amount.GreaterThan(winner.Amount)) {
+				winner = bid
+			}
+		}
+
+		if winner == nil {
+			if e = w.expireAuction(ctx, tx, auction, t); e != nil {
+				return e
+			}
+			continue
+		}
+
+		for _, bid := range bids {
+			if bid.Status != core.AuctionBidStatusPending {
+				continue
+			}
+
+			if bid == winner {
+				bid.Status = core.AuctionBidStatusWon
+			} else {
+				refundAction := core.TransferAction{
+					Source:   core.ActionTypeAuctionRefundTransfer,
+					FollowID: bid.FollowID,
+				}
+				if e = w.transferOut(ctx, tx, bid.UserID, bid.FollowID, bid.TraceID, auction.BidAssetID, bid.Amount, &refundAction); e != nil {
+					return e
+				}
+
+				bid.Status = core.AuctionBidStatusRefunded
+			}
+
+			if e = w.auctionStore.UpdateBid(ctx, tx, bid); e != nil {
+				log.WithError(e).Errorln("update bid error")
+				return e
+			}
+		}
+
+		if e = w.settleAuction(ctx, tx, auction, winner.UserID, winner.FollowID, winner.TraceID, winner.Amount); e != nil {
+			return e
+		}
+	}
+
+	return nil
+}
+
+// settleAuction transfer the lot to the winner and the proceeds to the opponent of the auction
+func (w *Payee) settleAuction(ctx context.Context, tx *db.DB, auction *core.Auction, userID, followID, traceID string, price decimal.Decimal) error {
+	log := logger.FromContext(ctx).WithField("worker", "auction_settle")
+
+	auction.Status = core.AuctionStatusSettled
+	auction.Winner = userID
+	auction.WinningBid = price
+	if e := w.auctionStore.Update(ctx, tx, auction); e != nil {
+		log.WithError(e).Errorln("update auction error")
+		return e
+	}
+
+	extra := core.NewTransactionExtra()
+	extra.Put(core.TransactionKeyReferTrace, auction.TraceID)
+	extra.Put(core.TransactionKeyPrice, price)
+	transaction := core.Transaction{
+		UserID:   userID,
+		Action:   core.ActionTypeAuctionSettle,
+		TraceID:  uuidutil.Modify(auction.TraceID, "auction_settle"),
+		FollowID: followID,
+		AssetID:  auction.AssetID,
+		Amount:   auction.Amount,
+		Data:     extra.Format(),
+	}
+	if e := w.transactionStore.Create(ctx, tx, &transaction); e != nil {
+		log.WithError(e).Errorln("create transaction error")
+		return e
+	}
+
+	lotAction := core.TransferAction{
+		Source:   core.ActionTypeAuctionTransfer,
+		FollowID: followID,
+	}
+	if e := w.transferOut(ctx, tx, userID, followID, traceID, auction.AssetID, auction.Amount, &lotAction); e != nil {
+		return e
+	}
+
+	proceedsAction := core.TransferAction{
+		Source:   core.ActionTypeAuctionTransfer,
+		FollowID: auction.TraceID,
+	}
+	return w.transferOut(ctx, tx, auction.Opponent, auction.TraceID, auction.TraceID, auction.BidAssetID, price, &proceedsAction)
+}
+
+// expireAuction return the lot of the auction ended without winning bid to the market reserves
+func (w *Payee) expireAuction(ctx context.Context, tx *db.DB, auction *core.Auction, t time.Time) error {
+	log := logger.FromContext(ctx).WithField("worker", "auction_settle")
+
+	market, _, e := w.marketStore.Find(ctx, auction.AssetID)
+	if e != nil {
+		log.WithError(e).Errorln("find market error")
+		return e
+	}
+
+	if e = w.marketService.AccrueInterest(ctx, tx, market, t); e != nil {
+		log.Errorln(e)
+		return e
+	}
+
+	market.Reserves = market.Reserves.Add(auction.Amount).Truncate(16)
+	market.TotalCash = market.TotalCash.Add(auction.Amount).Truncate(16)
+	if e = w.marketStore.Update(ctx, tx, market); e != nil {
+		log.Errorln(e)
+		return e
+	}
+
+	auction.Status = core.AuctionStatusExpired
+	if e = w.auctionStore.Update(ctx, tx, auction); e != nil {
+		log.WithError(e).Errorln("update auction error")
+		return e
+	}
+
+	return nil
+}
@@ -0,0 +1,140 @@
+package snapshot
+
+import (
+	"compound/core"
+	"compound/internal/compound"
+	"context"
+	"time"
+
+	"github.com/fox-one/pkg/logger"
+	"github.com/fox-one/pkg/store/db"
+	uuidutil "github.com/fox-one/pkg/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// handleBadDebts write off the debts of the user without any collaterals left,
+// called by the actions removing the collaterals: liquidation, unpledge, self liquidation, deleverage and flash loan default
+//
+// the debt is written off against the market reserves first,
+// the remainder is socialized to the suppliers by lowering the exchange rate
+func (w *Payee) handleBadDebts(ctx context.Context, tx *db.DB, output *core.Output, userID string) error {
+	log := logger.FromContext(ctx).WithField("worker", "bad_debt")
+
+	supplies, e := w.supplyStore.FindByUser(ctx, userID)
+	if e != nil {
+		log.WithError(e).Errorln("find supplies error")
+		return e
+	}
+
+	for _, supply := range supplies {
+		if supply.Collaterals.GreaterThan(decimal.Zero) {
+			return nil
+		}
+	}
+
+	borrows, e := w.borrowStore.FindByUser(ctx, userID)
+	if e != nil {
+		log.WithError(e).Errorln("find borrows error")
+		return e
+	}
+
+	var transactions []*core.Transaction
+	for _, borrow := range borrows {
+		if borrow.Principal.LessThanOrEqual(decimal.Zero) {
+			continue
+		}
+
+		transaction, e := w.writeOffBadDebt(ctx, tx, output, borrow, output.CreatedAt)
+		if e != nil {
+			return e
+		}
+
+		if transaction != nil {
+			transactions = append(transactions, transaction)
+		}
+	}
+
+	for _, transaction := range transactions {
+		if e := w.proposalService.BadDebtWrittenOff(ctx, transaction); e != nil {
+			log.WithError(e).Errorln("report bad debt error")
+			return e
+		}
+	}
+
+	return nil
+}
+
+func (w *Payee) writeOffBadDebt(ctx context.Context, tx *db.DB, output *core.Output, borrow *core.Borrow, t time.Time) (*core.Transaction, error) {
+	log := logger.FromContext(ctx).WithField("worker", "bad_debt")
+
+	market, _, e := w.marketStore.Find(ctx, borrow.AssetID)
+	if e != nil {
+		log.WithError(e).Errorln("find market error")
+		return nil, e
+	}
+
+	if e = w.marketService.AccrueInterest(ctx, tx, market, t); e != nil {
+		log.Errorln(e)
+		return nil, e
+	}
+
+	// distribute rewards before the borrow written off
+	if _, e = w.rewardService.Distribute(ctx, tx, market, borrow.UserID); e != nil {
+		log.Errorln(e)
+		return nil, e
+	}
+
+	debt, e := w.borrowService.BorrowBalance(ctx, borrow, market)
+	if e != nil {
+		log.Errorln(e)
+		return nil, e
+	}
+	debt = debt.Truncate(16)
+
+	fromReserves, socialized := compound.WriteOffBadDebt(debt, market.Reserves)
+	log.Infoln("write off bad debt, user:", borrow.UserID, ":asset:", borrow.AssetID, ":debt:", debt, ":reserves:", fromReserves, ":socialized:", socialized)
+
+	if borrow.IsStable() {
+		market.TotalStableBorrows, market.AvgStableRate = compound.UpdateStableBorrows(market.TotalStableBorrows, market.AvgStableRate, debt, borrow.Principal, borrow.StableRate, decimal.Zero, decimal.Zero)
+	}
+
+	market.Reserves = market.Reserves.Sub(fromReserves).Truncate(16)
+	market.TotalBorrows = decimal.Max(market.TotalBorrows.Sub(debt), decimal.Zero).Truncate(16)
+	if e = w.marketStore.Update(ctx, tx, market); e != nil {
+		log.Errorln(e)
+		return nil, e
+	}
+
+	borrow.Principal = decimal.Zero
+	borrow.InterestIndex = market.BorrowIndex.Truncate(16)
+	borrow.StableRateBlock = market.BlockNumber
+	if e = w.borrowStore.Update(ctx, tx, borrow); e != nil {
+		log.Errorln(e)
+		return nil, e
+	}
+
+	if e = w.marketService.AccrueInterest(ctx, tx, market, t); e != nil {
+		log.Errorln(e)
+		return nil, e
+	}
+
+	extra := core.NewTransactionExtra()
+	extra.Put(core.TransactionKeyReferTrace, output.TraceID)
+	extra.Put(core.TransactionKeyReserves, fromReserves)
+	extra.Put(core.TransactionKeySocialized, socialized)
+	transaction := core.Transaction{
+		UserID:   borrow.UserID,
+		Action:   core.ActionTypeBadDebtWriteOff,
+		TraceID:  uuidutil.Modify(output.TraceID, "bad_debt:"+borrow.UserID+borrow.AssetID),
+		FollowID: output.TraceID,
+		AssetID:  borrow.AssetID,
+		Amount:   debt,
+		Data:     extra.Format(),
+	}
+	if e = w.transactionStore.Create(ctx, tx, &transaction); e != nil {
+		log.WithError(e).Errorln("create transaction error")
+		return nil, e
+	}
+
+	return &transaction, nil
+}
@@ -0,0 +1,127 @@
+package snapshot
+
+import (
+	"compound/core"
+	"compound/core/proposal"
+	"compound/pkg/dbtx"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fox-one/pkg/logger"
+	"github.com/fox-one/pkg/store/db"
+)
+
+// the proposals configuring the markets could be batched, all of them write through the transaction of the batch
+var batchActions = map[core.ActionType]bool{
+	core.ActionTypeProposalAddMarket:               true,
+	core.ActionTypeProposalUpdateMarket:            true,
+	core.ActionTypeProposalUpdateMarketAdvance:     true,
+	core.ActionTypeProposalUpdateInterestRateModel: true,
+	core.ActionTypeProposalUpdateIsolation:         true,
+	core.ActionTypeProposalUpdateCategory:          true,
+	core.ActionTypeProposalSetMarketCategory:       true,
+	core.ActionTypeProposalUpdateRewardSpeed:       true,
+	core.ActionTypeProposalUpdateLiquidationMode:   true,
+	core.ActionTypeProposalUpdatePriceGuard:        true,
+	core.ActionTypeProposalUpdatePriceConsensus:    true,
+	core.ActionTypeProposalCloseMarket:             true,
+	core.ActionTypeProposalOpenMarket:              true,
+}
+
+// errBatchItemSkipped the batched proposal request is skipped by its handler, the batch is rolled back
+var errBatchItemSkipped = errors.New("batch item skipped")
+
+type batchKey struct{}
+
+// skipProposal the error returned by the handler skipping the invalid proposal request,
+// the request in a batch fails the whole batch while the single one is just skipped
+func skipProposal(ctx context.Context) error {
+	if batch, _ := ctx.Value(batchKey{}).(bool); batch {
+		return errBatchItemSkipped
+	}
+
+	return nil
+}
+
+// decodeBatchContent decode the proposal requests in the batch to json content
+func (w *Payee) decodeBatchContent(req proposal.BatchReq) ([]byte, error) {
+	if len(req.Items) == 0 {
+		return nil, errors.New("empty batch")
+	}
+
+	for idx, item := range req.Items {
+		if !batchActions[item.Action] {
+			return nil, fmt.Errorf("action %s not batchable", item.Action)
+		}
+
+		content, err := w.decodeProposalContent(item.Action, item.Data)
+		if err != nil {
+			return nil, fmt.Errorf("decode batch item %d: %w", idx, err)
+		}
+
+		req.Items[idx].Content = content
+	}
+
+	return json.Marshal(req)
+}
+
+// handleBatchEvent execute the proposal requests in order in one transaction,
+// the later requests see the writes of the earlier ones, and none of them is applied if any fails
+func (w *Payee) handleBatchEvent(ctx context.Context, p *core.Proposal, req proposal.BatchReq, t time.Time) error {
+	log := logger.FromContext(ctx).WithField("worker", "batch")
+
+	for _, item := range req.Items {
+		if !batchActions[item.Action] {
+			log.Warningln("action not batchable:", item.Action)
+			return nil
+		}
+	}
+
+	return w.transaction(ctx, func(tx *db.DB) error {
+		ctx := context.WithValue(dbtx.WithContext(ctx, tx), batchKey{}, true)
+
+		err := dbtx.Savepoint(tx, "batch", func() error {
+			for idx, item := range req.Items {
+				itemProposal := *p
+				itemProposal.Action = item.Action
+				itemProposal.Content = []byte(item.Content)
+
+				if err := w.handlePassedProposal(ctx, &itemProposal, t); err != nil {
+					log.WithError(err).Errorln("handle batch item", idx, item.Action)
+					return err
+				}
+			}
+
+			return nil
+		})
+
+		// the writes of the earlier items are rolled back with the skipped one
+		if errors.Is(err, errBatchItemSkipped) {
+			log.Warningln("batch rolled back:", p.TraceID)
+			return nil
+		}
+
+		return err
+	})
+}
+
+// timelock the delay of the passed proposal, the longest one of the batched actions for the batch
+func (w *Payee) timelock(p *core.Proposal) time.Duration {
+	delay := w.system.Timelock(p.Action)
+	if p.Action != core.ActionTypeProposalBatch {
+		return delay
+	}
+
+	var req proposal.BatchReq
+	_ = json.Unmarshal(p.Content, &req)
+	for _, item := range req.Items {
+		if d := w.system.Timelock(item.Action); d > delay {
+			delay = d
+		}
+	}
+
+	return delay
+}
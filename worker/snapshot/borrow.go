@@ -2,28 +2,66 @@ package snapshot
 
 import (
 	"compound/core"
+	"compound/internal/compound"
 	"compound/pkg/mtg"
 	"context"
 
 	"github.com/fox-one/pkg/logger"
 	"github.com/fox-one/pkg/store/db"
 	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
 	"github.com/shopspring/decimal"
 )
 
 // handle borrow event
+//
+// the memo may carry the address of the delegator after the rate mode,
+// the borrow is recorded to the delegator within the allowance granted to the sender
 func (w *Payee) handleBorrowEvent(ctx context.Context, tx *db.DB, output *core.Output, userID, followID string, body []byte) error {
 
 	log := logger.FromContext(ctx).WithField("worker", "borrow")
 
 	var asset uuid.UUID
 	var borrowAmount decimal.Decimal
-	if _, err := mtg.Scan(body, &asset, &borrowAmount); err != nil {
+	rest, err := mtg.Scan(body, &asset, &borrowAmount)
+	if err != nil {
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeBorrow, core.ErrInvalidArgument, "")
+	}
+
+	// the rate mode is optional, variable rate by default
+	rateMode := int(core.BorrowRateModeVariable)
+	if len(rest) > 0 {
+		if rest, err = mtg.Scan(rest, &rateMode); err != nil {
+			return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeBorrow, core.ErrInvalidArgument, "")
+		}
+	}
+
+	// the delegator is optional, borrow for the sender by default
+	borrowerID := userID
+	if len(rest) > 0 {
+		var delegatorAddress uuid.UUID
+		if _, err := mtg.Scan(rest, &delegatorAddress); err != nil {
+			return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeBorrow, core.ErrInvalidArgument, "")
+		}
+
+		delegator, e := w.userStore.FindByAddress(ctx, delegatorAddress.String())
+		if e != nil {
+			if gorm.IsRecordNotFoundError(e) {
+				return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeBorrow, core.ErrInvalidArgument, "")
+			}
+			return e
+		}
+
+		borrowerID = delegator.UserID
+	}
+
+	mode := core.BorrowRateMode(rateMode)
+	if !mode.IsValid() {
 		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeBorrow, core.ErrInvalidArgument, "")
 	}
 
 	assetID := asset.String()
-	log.Infoln("borrow, asset:", assetID, ":amount:", borrowAmount)
+	log.Infoln("borrow, asset:", assetID, ":amount:", borrowAmount, ":rate_mode:", mode, ":borrower:", borrowerID)
 	market, isRecordNotFound, e := w.marketStore.Find(ctx, assetID)
 	if isRecordNotFound {
 		log.Warningln("market not found, refund")
@@ -39,57 +77,112 @@ func (w *Payee) handleBorrowEvent(ctx context.Context, tx *db.DB, output *core.O
 		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeBorrow, core.ErrMarketClosed, "")
 	}
 
+	// the prices of all the markets in the liquidity of the borrower should be fresh
+	stale, e := w.isPriceStale(ctx, borrowerID, output.CreatedAt, market)
+	if e != nil {
+		log.Errorln(e)
+		return e
+	}
+
+	if stale {
+		log.Warningln("price stale, refund")
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeBorrow, core.ErrPriceStale, "")
+	}
+
 	// accrue interest
 	if e = w.marketService.AccrueInterest(ctx, tx, market, output.CreatedAt); e != nil {
 		return e
 	}
 
-	if !w.borrowService.BorrowAllowed(ctx, borrowAmount, userID, market, output.CreatedAt) {
+	// distribute rewards before the borrow changed
+	if _, e = w.rewardService.Distribute(ctx, tx, market, borrowerID); e != nil {
+		log.Errorln(e)
+		return e
+	}
+
+	borrow, isRecordNotFound, e := w.borrowStore.Find(ctx, borrowerID, market.AssetID)
+	if e != nil && !isRecordNotFound {
+		log.Errorln(e)
+		return e
+	}
+
+	// the rate mode can only be changed after the borrow is fully repaid
+	if borrow != nil && borrow.IsStable() != (mode == core.BorrowRateModeStable) && borrow.Principal.GreaterThan(decimal.Zero) {
+		log.Warningln("borrow rate mode mismatch")
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeBorrow, core.ErrBorrowRateModeMismatch, "")
+	}
+
+	var delegation *core.Delegation
+	if borrowerID != userID {
+		delegation, isRecordNotFound, e = w.delegationStore.Find(ctx, borrowerID, userID, market.AssetID)
+		if e != nil && !isRecordNotFound {
+			log.WithError(e).Errorln("find delegation error")
+			return e
+		}
+
+		if isRecordNotFound || borrowAmount.GreaterThan(delegation.Allowance) {
+			log.Errorln("insufficient allowance")
+			return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeBorrow, core.ErrInsufficientAllowance, "")
+		}
+
+		if !w.borrowService.DelegatedBorrowAllowed(ctx, borrowAmount, borrowerID, userID, market, output.CreatedAt) {
+			log.Errorln("delegated borrow not allowed")
+			return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeBorrow, core.ErrBorrowNotAllowed, "")
+		}
+	} else if !w.borrowService.BorrowAllowed(ctx, borrowAmount, userID, market, output.CreatedAt) {
 		log.Errorln("borrow not allowed")
 		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeBorrow, core.ErrBorrowNotAllowed, "")
 	}
 
+	// lock the stable rate at borrow time
+	stableRate := compound.GetStableRatePerBlock(market.BorrowRatePerBlock)
+
+	if borrow == nil {
+		borrow = &core.Borrow{
+			UserID:        borrowerID,
+			AssetID:       market.AssetID,
+			InterestIndex: market.BorrowIndex,
+		}
+	}
+
+	borrowBalance, e := w.borrowService.BorrowBalance(ctx, borrow, market)
+	if e != nil {
+		log.Errorln(e)
+		return e
+	}
+
+	principal, rate := borrow.Principal, borrow.StableRate
+	if mode == core.BorrowRateModeStable {
+		// the weighted average of the existing stable rate and the new one
+		_, borrow.StableRate = compound.AddStableBorrows(borrowBalance, borrow.StableRate, borrowAmount, stableRate)
+	}
+
+	borrow.Principal = borrowBalance.Add(borrowAmount).Truncate(16)
+	borrow.InterestIndex = market.BorrowIndex.Truncate(16)
+	borrow.RateMode = mode
+	borrow.StableRateBlock = market.BlockNumber
+
 	market.TotalCash = market.TotalCash.Sub(borrowAmount).Truncate(16)
 	market.TotalBorrows = market.TotalBorrows.Add(borrowAmount).Truncate(16)
+	if mode == core.BorrowRateModeStable {
+		market.TotalStableBorrows, market.AvgStableRate = compound.UpdateStableBorrows(market.TotalStableBorrows, market.AvgStableRate, borrowBalance, principal, rate, borrow.Principal, borrow.StableRate)
+	}
 	// update market
 	if e = w.marketStore.Update(ctx, tx, market); e != nil {
 		log.Errorln(e)
 		return e
 	}
 
-	borrow, isRecordNotFound, e := w.borrowStore.Find(ctx, userID, market.AssetID)
-	if e != nil {
-		if isRecordNotFound {
-			//new borrow record
-			borrow = &core.Borrow{
-				UserID:        userID,
-				AssetID:       market.AssetID,
-				Principal:     borrowAmount,
-				InterestIndex: market.BorrowIndex}
-
-			if e = w.borrowStore.Save(ctx, tx, borrow); e != nil {
-				log.Errorln(e)
-				return e
-			}
-		} else {
-			return e
-		}
+	if borrow.ID == 0 {
+		//new borrow record
+		e = w.borrowStore.Save(ctx, tx, borrow)
 	} else {
 		//update borrow account
-		borrowBalance, e := w.borrowService.BorrowBalance(ctx, borrow, market)
-		if e != nil {
-			log.Errorln(e)
-			return e
-		}
-
-		newBorrowBalance := borrowBalance.Add(borrowAmount)
-		borrow.Principal = newBorrowBalance.Truncate(16)
-		borrow.InterestIndex = market.BorrowIndex.Truncate(16)
 		e = w.borrowStore.Update(ctx, tx, borrow)
-		if e != nil {
-			log.Errorln(e)
-			return e
-		}
+	}
+	if e != nil {
+		log.Errorln(e)
+		return e
 	}
 
 	//update interest
@@ -98,16 +191,42 @@ func (w *Payee) handleBorrowEvent(ctx context.Context, tx *db.DB, output *core.O
 		return e
 	}
 
+	if e = w.rebalanceStableBorrows(ctx, tx, market); e != nil {
+		log.Errorln(e)
+		return e
+	}
+
+	if delegation != nil {
+		delegation.Allowance = delegation.Allowance.Sub(borrowAmount).Truncate(16)
+		if e = w.delegationStore.Update(ctx, tx, delegation); e != nil {
+			log.Errorln(e)
+			return e
+		}
+	}
+
 	//transaction
 	extra := core.NewTransactionExtra()
 	extra.Put(core.TransactionKeyAssetID, assetID)
 	extra.Put(core.TransactionKeyAmount, borrowAmount)
+	if mode == core.BorrowRateModeStable {
+		extra.Put(core.TransactionKeyStableRate, borrow.StableRate)
+	}
+	if borrowerID != userID {
+		extra.Put(core.TransactionKeyUser, borrowerID)
+	}
 	transaction := core.BuildTransactionFromOutput(ctx, userID, followID, core.ActionTypeBorrow, output, &extra)
 	if e = w.transactionStore.Create(ctx, tx, transaction); e != nil {
 		log.WithError(e).Errorln("create transaction error")
 		return e
 	}
 
+	// the position of the delegator borrowed on behalf of is put up for liquidation auction too
+	if borrowerID != userID {
+		if e = w.updateLiquidationAuction(ctx, tx, output.TraceID, borrowerID, output.CreatedAt); e != nil {
+			return e
+		}
+	}
+
 	//transfer borrowed asset
 	transferAction := core.TransferAction{
 		Source:   core.ActionTypeBorrowTransfer,
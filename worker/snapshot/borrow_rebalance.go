@@ -0,0 +1,67 @@
+package snapshot
+
+import (
+	"compound/core"
+	"compound/internal/compound"
+	"context"
+
+	"github.com/fox-one/pkg/logger"
+	"github.com/fox-one/pkg/store/db"
+)
+
+// stableRebalanceLimit the max stable borrows rebalanced by one action, the lowest rates first
+const stableRebalanceLimit = 10
+
+// rebalanceStableBorrows rebalance the stable borrows to the current stable rate
+//
+// when the utilization rate exceeds compound.StableRateRebalanceUtilization,
+// the stable borrows with a rate lower than the variable borrow rate are rebalanced,
+// at most stableRebalanceLimit of them on each borrow or redeem, the rest on the later ones
+func (w *Payee) rebalanceStableBorrows(ctx context.Context, tx *db.DB, market *core.Market) error {
+	log := logger.FromContext(ctx).WithField("worker", "stable_rate_rebalance")
+
+	if market.TotalStableBorrows.IsZero() ||
+		market.UtilizationRate.LessThanOrEqual(compound.StableRateRebalanceUtilization) {
+		return nil
+	}
+
+	borrows, e := w.borrowStore.ListStableBelow(ctx, market.AssetID, market.BorrowRatePerBlock, stableRebalanceLimit)
+	if e != nil {
+		log.WithError(e).Errorln("list stable borrows error")
+		return e
+	}
+
+	stableRate := compound.GetStableRatePerBlock(market.BorrowRatePerBlock)
+	rebalanced := false
+	for _, borrow := range borrows {
+		if !borrow.IsStable() || borrow.Principal.IsZero() ||
+			!compound.StableRateRebalanceAllowed(market.UtilizationRate, borrow.StableRate, market.BorrowRatePerBlock) {
+			continue
+		}
+
+		borrowBalance, e := w.borrowService.BorrowBalance(ctx, borrow, market)
+		if e != nil {
+			log.Errorln(e)
+			return e
+		}
+
+		market.TotalStableBorrows, market.AvgStableRate = compound.UpdateStableBorrows(market.TotalStableBorrows, market.AvgStableRate, borrowBalance, borrow.Principal, borrow.StableRate, borrowBalance, stableRate)
+
+		log.Infoln("rebalance stable rate, user:", borrow.UserID, ":from:", borrow.StableRate, ":to:", stableRate)
+		borrow.Principal = borrowBalance.Truncate(16)
+		borrow.StableRate = stableRate
+		borrow.StableRateBlock = market.BlockNumber
+		if e = w.borrowStore.Update(ctx, tx, borrow); e != nil {
+			log.Errorln(e)
+			return e
+		}
+
+		rebalanced = true
+	}
+
+	if !rebalanced {
+		return nil
+	}
+
+	return w.marketStore.Update(ctx, tx, market)
+}
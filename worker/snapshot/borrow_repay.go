@@ -2,14 +2,21 @@ package snapshot
 
 import (
 	"compound/core"
+	"compound/internal/compound"
+	"compound/pkg/mtg"
 	"context"
 
 	"github.com/fox-one/pkg/logger"
 	"github.com/fox-one/pkg/store/db"
+	"github.com/gofrs/uuid"
+	"github.com/jinzhu/gorm"
 	"github.com/shopspring/decimal"
 )
 
 // handle borrow repay event
+//
+// the memo may carry the address of the borrower to repay on behalf of, the sender's borrow is repaid by default,
+// the redundant amount is always refunded to the sender
 func (w *Payee) handleRepayEvent(ctx context.Context, tx *db.DB, output *core.Output, userID, followID string, body []byte) error {
 
 	log := logger.FromContext(ctx).WithField("worker", "borrow_repay")
@@ -17,7 +24,25 @@ func (w *Payee) handleRepayEvent(ctx context.Context, tx *db.DB, output *core.Ou
 	repayAmount := output.Amount
 	assetID := output.AssetID
 
-	log.Infoln(":asset:", output.AssetID, "amount:", repayAmount)
+	borrowerID := userID
+	if len(body) > 0 {
+		var borrowerAddress uuid.UUID
+		if _, err := mtg.Scan(body, &borrowerAddress); err != nil {
+			return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeRepay, core.ErrInvalidArgument, "")
+		}
+
+		borrower, e := w.userStore.FindByAddress(ctx, borrowerAddress.String())
+		if e != nil {
+			if gorm.IsRecordNotFoundError(e) {
+				return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeRepay, core.ErrInvalidArgument, "")
+			}
+			return e
+		}
+
+		borrowerID = borrower.UserID
+	}
+
+	log.Infoln(":asset:", output.AssetID, "amount:", repayAmount, ":borrower:", borrowerID)
 	market, isRecordNotFound, e := w.marketStore.Find(ctx, assetID)
 	if isRecordNotFound {
 		log.Warningln("market not found")
@@ -39,7 +64,13 @@ func (w *Payee) handleRepayEvent(ctx context.Context, tx *db.DB, output *core.Ou
 		return e
 	}
 
-	borrow, isRecordNotFound, e := w.borrowStore.Find(ctx, userID, market.AssetID)
+	// distribute rewards before the borrow changed
+	if _, e = w.rewardService.Distribute(ctx, tx, market, borrowerID); e != nil {
+		log.Errorln(e)
+		return e
+	}
+
+	borrow, isRecordNotFound, e := w.borrowStore.Find(ctx, borrowerID, market.AssetID)
 	if isRecordNotFound {
 		log.Warningln("borrow not found")
 		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeRepay, core.ErrBorrowNotFound, "")
@@ -65,8 +96,10 @@ func (w *Payee) handleRepayEvent(ctx context.Context, tx *db.DB, output *core.Ou
 		realRepaidBalance = borrowBalance
 	}
 
+	principal := borrow.Principal
 	borrow.Principal = newBalance.Truncate(16)
 	borrow.InterestIndex = newIndex.Truncate(16)
+	borrow.StableRateBlock = market.BlockNumber
 	if e = w.borrowStore.Update(ctx, tx, borrow); e != nil {
 		log.Errorln(e)
 		return e
@@ -74,6 +107,9 @@ func (w *Payee) handleRepayEvent(ctx context.Context, tx *db.DB, output *core.Ou
 
 	market.TotalBorrows = market.TotalBorrows.Sub(realRepaidBalance).Truncate(16)
 	market.TotalCash = market.TotalCash.Add(realRepaidBalance).Truncate(16)
+	if borrow.IsStable() {
+		market.TotalStableBorrows, market.AvgStableRate = compound.UpdateStableBorrows(market.TotalStableBorrows, market.AvgStableRate, borrowBalance, principal, borrow.StableRate, borrow.Principal, borrow.StableRate)
+	}
 
 	if e = w.marketStore.Update(ctx, tx, market); e != nil {
 		log.Errorln(e)
@@ -87,12 +123,23 @@ func (w *Payee) handleRepayEvent(ctx context.Context, tx *db.DB, output *core.Ou
 	}
 
 	// add transaction
-	transaction := core.BuildTransactionFromOutput(ctx, userID, followID, core.ActionTypeRepay, output, nil)
+	extra := core.NewTransactionExtra()
+	if borrowerID != userID {
+		extra.Put(core.TransactionKeyUser, borrowerID)
+	}
+	transaction := core.BuildTransactionFromOutput(ctx, userID, followID, core.ActionTypeRepay, output, &extra)
 	if e = w.transactionStore.Create(ctx, tx, transaction); e != nil {
 		log.WithError(e).Errorln("create transaction error")
 		return e
 	}
 
+	// the position of the borrower repaid on behalf of is taken off the liquidation auction too
+	if borrowerID != userID {
+		if e = w.updateLiquidationAuction(ctx, tx, output.TraceID, borrowerID, output.CreatedAt); e != nil {
+			return e
+		}
+	}
+
 	if redundantAmount.GreaterThan(decimal.Zero) {
 		refundAmount := redundantAmount
 		transferAction := core.TransferAction{
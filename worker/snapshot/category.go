@@ -0,0 +1,106 @@
+package snapshot
+
+import (
+	"compound/core"
+	"compound/core/proposal"
+	"compound/internal/compound"
+	"context"
+	"strings"
+	"time"
+
+	"github.com/fox-one/pkg/logger"
+	"github.com/fox-one/pkg/store/db"
+	"github.com/shopspring/decimal"
+)
+
+// handleUpdateCategoryEvent create the e-mode category or update the parameters of the existing one
+func (w *Payee) handleUpdateCategoryEvent(ctx context.Context, p *core.Proposal, req proposal.UpdateCategoryReq, t time.Time) error {
+	return w.transaction(ctx, func(tx *db.DB) error {
+		log := logger.FromContext(ctx).WithField("worker", "update-category")
+
+		name := strings.ToUpper(req.Name)
+		if name == "" {
+			log.Warningln("empty category name")
+			return skipProposal(ctx)
+		}
+
+		// the category collateral factor may exceed the market max, but must be less than 1
+		if req.CollateralFactor.LessThanOrEqual(decimal.Zero) || req.CollateralFactor.GreaterThanOrEqual(decimal.NewFromInt(1)) {
+			log.Warningln("invalid category collateral factor:", req.CollateralFactor)
+			return skipProposal(ctx)
+		}
+
+		if req.LiquidationIncentive.LessThan(compound.LiquidationIncentiveMin) || req.LiquidationIncentive.GreaterThan(compound.LiquidationIncentiveMax) {
+			log.Warningln("invalid category liquidation incentive:", req.LiquidationIncentive)
+			return skipProposal(ctx)
+		}
+
+		category, isRecordNotFound, e := w.categoryStore.Find(ctx, name)
+		if e != nil {
+			if !isRecordNotFound {
+				return e
+			}
+
+			category = &core.Category{
+				Name:                 name,
+				CollateralFactor:     req.CollateralFactor,
+				LiquidationIncentive: req.LiquidationIncentive,
+			}
+			if e = w.categoryStore.Save(ctx, tx, category); e != nil {
+				log.Errorln(e)
+				return e
+			}
+
+			return nil
+		}
+
+		category.CollateralFactor = req.CollateralFactor
+		category.LiquidationIncentive = req.LiquidationIncentive
+		if e = w.categoryStore.Update(ctx, tx, category); e != nil {
+			log.Errorln(e)
+			return e
+		}
+
+		return nil
+	})
+}
+
+// handleSetMarketCategoryEvent put the market into the e-mode category, or remove it from the category with empty name
+func (w *Payee) handleSetMarketCategoryEvent(ctx context.Context, p *core.Proposal, req proposal.MarketCategoryReq, t time.Time) error {
+	return w.transaction(ctx, func(tx *db.DB) error {
+		log := logger.FromContext(ctx).WithField("worker", "set-market-category")
+
+		market, isRecordNotFound, e := w.marketStore.FindBySymbol(ctx, strings.ToUpper(req.Symbol))
+		if e != nil {
+			if isRecordNotFound {
+				return skipProposal(ctx)
+			}
+
+			return e
+		}
+
+		name := strings.ToUpper(req.Category)
+		if name != "" {
+			if _, isRecordNotFound, e := w.categoryStore.Find(ctx, name); e != nil {
+				if isRecordNotFound {
+					log.Warningln("category not found:", name)
+					return skipProposal(ctx)
+				}
+
+				return e
+			}
+		}
+
+		if e = w.marketService.AccrueInterest(ctx, tx, market, t); e != nil {
+			return e
+		}
+
+		market.Category = name
+		if e = w.marketStore.Update(ctx, tx, market); e != nil {
+			log.Errorln(e)
+			return e
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,276 @@
+package snapshot
+
+import (
+	"compound/core"
+	"compound/internal/compound"
+	"compound/pkg/dbtx"
+	"compound/pkg/mtg"
+	"context"
+	"errors"
+
+	"github.com/fox-one/pkg/logger"
+	"github.com/fox-one/pkg/store/db"
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// handle swap collateral event
+//
+// the pledged ctokens of market A are unpledged, converted to the ctokens of market B at the oracle prices after the swap fee and pledged again,
+// the conversion runs against the reserves: the underlying of A goes to the reserves of A, and the reserves of B are supplied for the user,
+// A is priced at the lower of its current and collateral prices, and B at the higher one
+func (w *Payee) handleSwapCollateralEvent(ctx context.Context, tx *db.DB, output *core.Output, userID, followID string, body []byte) error {
+	log := logger.FromContext(ctx).WithField("worker", "swap_collateral")
+
+	var fromCTokenAsset, toCTokenAsset uuid.UUID
+	var fromCTokens decimal.Decimal
+	if _, err := mtg.Scan(body, &fromCTokenAsset, &fromCTokens, &toCTokenAsset); err != nil {
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeSwapCollateral, core.ErrInvalidArgument, "")
+	}
+
+	fromCTokens = fromCTokens.Truncate(8)
+	log.Infof("from:%s, amount:%s, to:%s", fromCTokenAsset.String(), fromCTokens, toCTokenAsset.String())
+
+	if fromCTokens.LessThanOrEqual(decimal.Zero) || fromCTokenAsset == toCTokenAsset {
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeSwapCollateral, core.ErrInvalidArgument, "")
+	}
+
+	fromMarket, isRecordNotFound, e := w.marketStore.FindByCToken(ctx, fromCTokenAsset.String())
+	if isRecordNotFound {
+		log.Warningln("from market not found")
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeSwapCollateral, core.ErrMarketNotFound, "")
+	}
+	if e != nil {
+		log.WithError(e).Errorln("find from market error")
+		return e
+	}
+
+	toMarket, isRecordNotFound, e := w.marketStore.FindByCToken(ctx, toCTokenAsset.String())
+	if isRecordNotFound {
+		log.Warningln("to market not found")
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeSwapCollateral, core.ErrMarketNotFound, "")
+	}
+	if e != nil {
+		log.WithError(e).Errorln("find to market error")
+		return e
+	}
+
+	if w.marketService.IsMarketClosed(ctx, fromMarket) || w.marketService.IsMarketClosed(ctx, toMarket) {
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeSwapCollateral, core.ErrMarketClosed, "")
+	}
+
+	// the prices of all the markets in the liquidity of the user should be fresh
+	stale, e := w.isPriceStale(ctx, userID, output.CreatedAt, fromMarket, toMarket)
+	if e != nil {
+		log.Errorln(e)
+		return e
+	}
+
+	if stale {
+		log.Warningln("price stale")
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeSwapCollateral, core.ErrPriceStale, "")
+	}
+
+	if toMarket.CollateralFactor.LessThanOrEqual(decimal.Zero) {
+		log.Errorln(errors.New("pledge disallowed"))
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeSwapCollateral, core.ErrPledgeNotAllowed, "")
+	}
+
+	// the isolated collaterals can not be swapped
+	if fromMarket.IsIsolated() || toMarket.IsIsolated() {
+		log.Errorln(errors.New("isolation mode violated"))
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeSwapCollateral, core.ErrIsolationModeViolated, "")
+	}
+
+	// accrue interest
+	for _, market := range []*core.Market{fromMarket, toMarket} {
+		if e = w.marketService.AccrueInterest(ctx, tx, market, output.CreatedAt); e != nil {
+			log.Errorln(e)
+			return e
+		}
+
+		// distribute rewards before the collaterals changed
+		if _, e = w.rewardService.Distribute(ctx, tx, market, userID); e != nil {
+			log.Errorln(e)
+			return e
+		}
+	}
+
+	fromSupply, isRecordNotFound, e := w.supplyStore.Find(ctx, userID, fromMarket.CTokenAssetID)
+	if isRecordNotFound {
+		log.Warningln("supply not found")
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeSwapCollateral, core.ErrSupplyNotFound, "")
+	}
+	if e != nil {
+		log.WithError(e).Errorln("find supply error")
+		return e
+	}
+
+	if fromCTokens.GreaterThan(fromSupply.Collaterals) {
+		log.Errorln(errors.New("insufficient collaterals"))
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeSwapCollateral, core.ErrInsufficientCollaterals, "")
+	}
+
+	// both sides are priced against the collateral prices of the liquidity check,
+	// so a manipulated current price can not be swapped at while the collateral price lags
+	fromCurrentPrice, e := w.priceService.GetCurrentUnderlyingPrice(ctx, fromMarket)
+	if e != nil {
+		log.Errorln(e)
+		return e
+	}
+
+	fromCollateralPrice, e := w.priceService.GetCollateralPrice(ctx, fromMarket)
+	if e != nil {
+		log.Errorln(e)
+		return e
+	}
+
+	toCurrentPrice, e := w.priceService.GetCurrentUnderlyingPrice(ctx, toMarket)
+	if e != nil {
+		log.Errorln(e)
+		return e
+	}
+
+	toCollateralPrice, e := w.priceService.GetCollateralPrice(ctx, toMarket)
+	if e != nil {
+		log.Errorln(e)
+		return e
+	}
+
+	fromPrice, toPrice := compound.SwapPrices(fromCurrentPrice, fromCollateralPrice, toCurrentPrice, toCollateralPrice)
+
+	fromExchangeRate, e := w.marketService.CurExchangeRate(ctx, fromMarket)
+	if e != nil {
+		log.Errorln(e)
+		return e
+	}
+
+	toExchangeRate, e := w.marketService.CurExchangeRate(ctx, toMarket)
+	if e != nil {
+		log.Errorln(e)
+		return e
+	}
+
+	fromUnderlying, toUnderlying, toCTokens := compound.SwapCollateral(fromCTokens, fromExchangeRate, fromPrice, toExchangeRate, toPrice, compound.CollateralSwapFee)
+	if toCTokens.LessThanOrEqual(decimal.Zero) {
+		log.Errorln(errors.New("invalid swap amount"))
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeSwapCollateral, core.ErrInvalidAmount, "")
+	}
+
+	if toUnderlying.GreaterThan(toMarket.Reserves) {
+		log.Warningln("insufficient reserves")
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeSwapCollateral, core.ErrInsufficientReserves, "")
+	}
+
+	// check supply cap
+	if toMarket.SupplyCap.GreaterThan(decimal.Zero) {
+		totalSupplies, e := w.marketService.CurTotalSupplies(ctx, toMarket)
+		if e != nil {
+			log.Errorln(e)
+			return e
+		}
+
+		if totalSupplies.Add(toUnderlying).GreaterThan(toMarket.SupplyCap) {
+			log.Warningln("supplies over cap")
+			return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeSwapCollateral, core.ErrSupplyOverCap, "")
+		}
+	}
+
+	blockNum, e := w.blockService.GetBlock(ctx, output.CreatedAt)
+	if e != nil {
+		log.Errorln(e)
+		return e
+	}
+
+	// the swap is rolled back if the account is short of liquidity after it,
+	// checked by the account liquidity with the e-mode collateral factors and the collateral prices
+	e = dbtx.Savepoint(tx, "swap_collateral", func() error {
+		// the redeemed underlying of A stays in the pool as the reserves
+		fromMarket.CTokens = fromMarket.CTokens.Sub(fromCTokens).Truncate(16)
+		fromMarket.Reserves = fromMarket.Reserves.Add(fromUnderlying).Truncate(16)
+		if e = w.marketStore.Update(ctx, tx, fromMarket); e != nil {
+			log.Errorln(e)
+			return e
+		}
+
+		// the reserves of B are supplied for the user
+		toMarket.CTokens = toMarket.CTokens.Add(toCTokens).Truncate(16)
+		toMarket.Reserves = toMarket.Reserves.Sub(toUnderlying).Truncate(16)
+		if e = w.marketStore.Update(ctx, tx, toMarket); e != nil {
+			log.Errorln(e)
+			return e
+		}
+
+		fromSupply.Collaterals = fromSupply.Collaterals.Sub(fromCTokens).Truncate(16)
+		if e = w.supplyStore.Update(ctx, tx, fromSupply); e != nil {
+			log.Errorln(e)
+			return e
+		}
+
+		toSupply, isRecordNotFound, e := w.supplyStore.Find(ctx, userID, toMarket.CTokenAssetID)
+		if e != nil {
+			if !isRecordNotFound {
+				log.Errorln(e)
+				return e
+			}
+
+			toSupply = &core.Supply{
+				UserID:        userID,
+				CTokenAssetID: toMarket.CTokenAssetID,
+				Collaterals:   toCTokens,
+			}
+			if e = w.supplyStore.Save(ctx, tx, toSupply); e != nil {
+				log.Errorln(e)
+				return e
+			}
+		} else {
+			toSupply.Collaterals = toSupply.Collaterals.Add(toCTokens).Truncate(16)
+			if e = w.supplyStore.Update(ctx, tx, toSupply); e != nil {
+				log.Errorln(e)
+				return e
+			}
+		}
+
+		// accrue interest
+		for _, market := range []*core.Market{fromMarket, toMarket} {
+			if e = w.marketService.AccrueInterest(ctx, tx, market, output.CreatedAt); e != nil {
+				log.Errorln(e)
+				return e
+			}
+		}
+
+		liquidity, e := w.accountService.CalculateAccountLiquidity(ctx, userID, blockNum)
+		if e != nil {
+			log.Errorln(e)
+			return e
+		}
+
+		if liquidity.LessThan(decimal.Zero) {
+			log.Errorln(errors.New("insufficient liquidity"))
+			return core.ErrInsufficientLiquidity
+		}
+
+		return nil
+	})
+	if e == core.ErrInsufficientLiquidity {
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeSwapCollateral, core.ErrInsufficientLiquidity, "")
+	}
+	if e != nil {
+		return e
+	}
+
+	// add transaction
+	extra := core.NewTransactionExtra()
+	extra.Put(core.TransactionKeyCTokenAssetID, fromMarket.CTokenAssetID)
+	extra.Put(core.TransactionKeyAmount, fromCTokens)
+	extra.Put(core.TransactionKeyTargetCTokenAssetID, toMarket.CTokenAssetID)
+	extra.Put(core.TransactionKeyTargetAmount, toCTokens)
+	extra.Put(core.TransactionKeyFee, fromUnderlying.Mul(compound.CollateralSwapFee).Truncate(8))
+	transaction := core.BuildTransactionFromOutput(ctx, userID, followID, core.ActionTypeSwapCollateral, output, &extra)
+	if e = w.transactionStore.Create(ctx, tx, transaction); e != nil {
+		log.WithError(e).Errorln("create transaction error")
+		return e
+	}
+
+	return nil
+}
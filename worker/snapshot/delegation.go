@@ -0,0 +1,93 @@
+package snapshot
+
+import (
+	"compound/core"
+	"compound/pkg/mtg"
+	"context"
+
+	"github.com/fox-one/pkg/logger"
+	"github.com/fox-one/pkg/store/db"
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// handle delegate event
+//
+// the sender grants the delegatee the allowance to borrow the asset against the collaterals of the sender,
+// the existing allowance is replaced, and zero allowance revokes the delegation
+func (w *Payee) handleDelegateEvent(ctx context.Context, tx *db.DB, output *core.Output, userID, followID string, body []byte) error {
+	log := logger.FromContext(ctx).WithField("worker", "delegate")
+
+	var delegatee uuid.UUID
+	var asset uuid.UUID
+	var allowance decimal.Decimal
+	if _, err := mtg.Scan(body, &delegatee, &asset, &allowance); err != nil {
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeDelegate, core.ErrInvalidArgument, "")
+	}
+
+	delegateeID := delegatee.String()
+	assetID := asset.String()
+	allowance = allowance.Truncate(8)
+	log.Infoln("delegate, delegatee:", delegateeID, ":asset:", assetID, ":allowance:", allowance)
+
+	if delegateeID == userID || allowance.LessThan(decimal.Zero) {
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeDelegate, core.ErrInvalidArgument, "")
+	}
+
+	if _, isRecordNotFound, e := w.marketStore.Find(ctx, assetID); e != nil {
+		if isRecordNotFound {
+			log.Warningln("market not found")
+			return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeDelegate, core.ErrMarketNotFound, "")
+		}
+
+		log.WithError(e).Errorln("find market error")
+		return e
+	}
+
+	// upsert delegatee, so that the delegation could be queried by the delegatee address
+	user := core.User{
+		UserID:  delegateeID,
+		Address: core.BuildUserAddress(delegateeID),
+	}
+	if e := w.userStore.Save(ctx, &user); e != nil {
+		return e
+	}
+
+	delegation, isRecordNotFound, e := w.delegationStore.Find(ctx, userID, delegateeID, assetID)
+	if e != nil {
+		if !isRecordNotFound {
+			log.WithError(e).Errorln("find delegation error")
+			return e
+		}
+
+		delegation = &core.Delegation{
+			Delegator: userID,
+			Delegatee: delegateeID,
+			AssetID:   assetID,
+			Allowance: allowance,
+		}
+		if e = w.delegationStore.Save(ctx, tx, delegation); e != nil {
+			log.Errorln(e)
+			return e
+		}
+	} else {
+		delegation.Allowance = allowance
+		if e = w.delegationStore.Update(ctx, tx, delegation); e != nil {
+			log.Errorln(e)
+			return e
+		}
+	}
+
+	// add transaction
+	extra := core.NewTransactionExtra()
+	extra.Put(core.TransactionKeyUser, delegateeID)
+	extra.Put(core.TransactionKeyAssetID, assetID)
+	extra.Put(core.TransactionKeyAmount, allowance)
+	transaction := core.BuildTransactionFromOutput(ctx, userID, followID, core.ActionTypeDelegate, output, &extra)
+	if e = w.transactionStore.Create(ctx, tx, transaction); e != nil {
+		log.WithError(e).Errorln("create transaction error")
+		return e
+	}
+
+	return nil
+}
@@ -0,0 +1,425 @@
+package snapshot
+
+import (
+	"compound/core"
+	"compound/internal/compound"
+	"compound/pkg/mtg"
+	"context"
+	"time"
+
+	"github.com/fox-one/pkg/logger"
+	"github.com/fox-one/pkg/store/db"
+	uuidutil "github.com/fox-one/pkg/uuid"
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// handle flash loan event
+//
+// the user borrows the market cash and should return the principal plus fee within core.FlashLoanWindow,
+// otherwise the pledged collaterals are seized by handleExpiredFlashLoans
+func (w *Payee) handleFlashLoanEvent(ctx context.Context, tx *db.DB, output *core.Output, userID, followID string, body []byte) error {
+	log := logger.FromContext(ctx).WithField("worker", "flash_loan")
+
+	var asset uuid.UUID
+	var loanAmount decimal.Decimal
+	if _, err := mtg.Scan(body, &asset, &loanAmount); err != nil {
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeFlashLoan, core.ErrInvalidArgument, "")
+	}
+
+	assetID := asset.String()
+	loanAmount = loanAmount.Truncate(8)
+	log.Infoln("flash loan, asset:", assetID, ":amount:", loanAmount)
+
+	market, isRecordNotFound, e := w.marketStore.Find(ctx, assetID)
+	if isRecordNotFound {
+		log.Warningln("market not found, refund")
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeFlashLoan, core.ErrMarketNotFound, "")
+	}
+	if e != nil {
+		log.Errorln("query market error:", e)
+		return e
+	}
+
+	if w.marketService.IsMarketClosed(ctx, market) {
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeFlashLoan, core.ErrMarketClosed, "")
+	}
+
+	// the prices of all the markets in the liquidity of the user should be fresh
+	stale, e := w.isPriceStale(ctx, userID, output.CreatedAt, market)
+	if e != nil {
+		log.Errorln(e)
+		return e
+	}
+
+	if stale {
+		log.Warningln("price stale")
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeFlashLoan, core.ErrPriceStale, "")
+	}
+
+	if loanAmount.LessThanOrEqual(decimal.Zero) {
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeFlashLoan, core.ErrInvalidAmount, "")
+	}
+
+	// only one pending flash loan for each asset
+	_, isRecordNotFound, e = w.flashLoanStore.FindPending(ctx, userID, assetID)
+	if e == nil {
+		log.Warningln("pending flash loan exists")
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeFlashLoan, core.ErrBorrowNotAllowed, "")
+	}
+	if !isRecordNotFound {
+		log.WithError(e).Errorln("find pending flash loan error")
+		return e
+	}
+
+	// accrue interest
+	if e = w.marketService.AccrueInterest(ctx, tx, market, output.CreatedAt); e != nil {
+		log.Errorln(e)
+		return e
+	}
+
+	fee := loanAmount.Mul(market.FlashLoanFee).Truncate(8)
+
+	// the principal plus fee must be covered by the pledged collaterals
+	if !w.borrowService.BorrowAllowed(ctx, loanAmount.Add(fee), userID, market, output.CreatedAt) {
+		log.Errorln("flash loan not allowed")
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeFlashLoan, core.ErrBorrowNotAllowed, "")
+	}
+
+	// the pending loan is kept out of the borrows to accrue no interest
+	market.TotalCash = market.TotalCash.Sub(loanAmount).Truncate(16)
+	market.FlashLoans = market.FlashLoans.Add(loanAmount).Truncate(16)
+	if e = w.marketStore.Update(ctx, tx, market); e != nil {
+		log.Errorln(e)
+		return e
+	}
+
+	loan := core.FlashLoan{
+		TraceID:   output.TraceID,
+		UserID:    userID,
+		FollowID:  followID,
+		AssetID:   assetID,
+		Amount:    loanAmount,
+		Fee:       fee,
+		Status:    core.FlashLoanStatusPending,
+		ExpiredAt: output.CreatedAt.Add(core.FlashLoanWindow),
+	}
+	if e = w.flashLoanStore.Save(ctx, tx, &loan); e != nil {
+		log.WithError(e).Errorln("save flash loan error")
+		return e
+	}
+
+	// accrue interest
+	if e = w.marketService.AccrueInterest(ctx, tx, market, output.CreatedAt); e != nil {
+		log.Errorln(e)
+		return e
+	}
+
+	// add transaction
+	extra := core.NewTransactionExtra()
+	extra.Put(core.TransactionKeyAssetID, assetID)
+	extra.Put(core.TransactionKeyAmount, loanAmount)
+	extra.Put(core.TransactionKeyFee, fee)
+	transaction := core.BuildTransactionFromOutput(ctx, userID, followID, core.ActionTypeFlashLoan, output, &extra)
+	if e = w.transactionStore.Create(ctx, tx, transaction); e != nil {
+		log.WithError(e).Errorln("create transaction error")
+		return e
+	}
+
+	// transfer loaned asset
+	transferAction := core.TransferAction{
+		Source:   core.ActionTypeFlashLoanTransfer,
+		FollowID: followID,
+	}
+	return w.transferOut(ctx, tx, userID, followID, output.TraceID, assetID, loanAmount, &transferAction)
+}
+
+// handle flash loan repay event
+func (w *Payee) handleFlashLoanRepayEvent(ctx context.Context, tx *db.DB, output *core.Output, userID, followID string, body []byte) error {
+	log := logger.FromContext(ctx).WithField("worker", "flash_loan_repay")
+
+	repayAmount := output.Amount
+	assetID := output.AssetID
+
+	loan, isRecordNotFound, e := w.flashLoanStore.FindPending(ctx, userID, assetID)
+	if isRecordNotFound {
+		log.Warningln("flash loan not found")
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeFlashLoanRepay, core.ErrFlashLoanNotFound, "")
+	}
+	if e != nil {
+		log.WithError(e).Errorln("find flash loan error")
+		return e
+	}
+
+	// the principal and fee should be returned in one output
+	due := loan.Due()
+	if repayAmount.LessThan(due) {
+		log.Warningln("insufficient repay amount:", repayAmount, ":due:", due)
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeFlashLoanRepay, core.ErrInvalidAmount, "")
+	}
+
+	market, _, e := w.marketStore.Find(ctx, assetID)
+	if e != nil {
+		log.WithError(e).Errorln("find market error")
+		return e
+	}
+
+	// accrue interest
+	if e = w.marketService.AccrueInterest(ctx, tx, market, output.CreatedAt); e != nil {
+		log.Errorln(e)
+		return e
+	}
+
+	market.FlashLoans = decimal.Max(market.FlashLoans.Sub(loan.Amount), decimal.Zero).Truncate(16)
+	market.TotalCash = market.TotalCash.Add(loan.Amount).Truncate(16)
+	if e = w.marketStore.Update(ctx, tx, market); e != nil {
+		log.Errorln(e)
+		return e
+	}
+
+	if e = w.marketService.AccrueFee(ctx, tx, market, loan.Fee); e != nil {
+		log.Errorln(e)
+		return e
+	}
+
+	loan.Status = core.FlashLoanStatusRepaid
+	if e = w.flashLoanStore.Update(ctx, tx, loan); e != nil {
+		log.WithError(e).Errorln("update flash loan error")
+		return e
+	}
+
+	// accrue interest
+	if e = w.marketService.AccrueInterest(ctx, tx, market, output.CreatedAt); e != nil {
+		log.Errorln(e)
+		return e
+	}
+
+	// add transaction
+	redundantAmount := repayAmount.Sub(due).Truncate(8)
+	extra := core.NewTransactionExtra()
+	extra.Put(core.TransactionKeyReferTrace, loan.TraceID)
+	extra.Put(core.TransactionKeyFee, loan.Fee)
+	extra.Put(core.TransactionKeyRefund, redundantAmount)
+	transaction := core.BuildTransactionFromOutput(ctx, userID, followID, core.ActionTypeFlashLoanRepay, output, &extra)
+	if e = w.transactionStore.Create(ctx, tx, transaction); e != nil {
+		log.WithError(e).Errorln("create transaction error")
+		return e
+	}
+
+	if redundantAmount.GreaterThan(decimal.Zero) {
+		transferAction := core.TransferAction{
+			Source:   core.ActionTypeRepayRefundTransfer,
+			FollowID: followID,
+		}
+
+		return w.transferOut(ctx, tx, userID, followID, output.TraceID, assetID, redundantAmount, &transferAction)
+	}
+
+	return nil
+}
+
+// handleExpiredFlashLoans seize the pledged collaterals of the flash loans not returned in time
+//
+// the collaterals worth the principal plus fee at the oracle price are seized into the reserves of their markets,
+// the loan market is credited with the value seized as the returned loan, backed by the seized reserves,
+// and only the due not covered is written off against its reserves first, the remainder is socialized
+func (w *Payee) handleExpiredFlashLoans(ctx context.Context, tx *db.DB, output *core.Output) error {
+	log := logger.FromContext(ctx).WithField("worker", "flash_loan_default")
+	t := output.CreatedAt
+
+	loans, e := w.flashLoanStore.ListExpired(ctx, t)
+	if e != nil {
+		log.WithError(e).Errorln("list expired flash loans error")
+		return e
+	}
+
+	for _, loan := range loans {
+		market, _, e := w.marketStore.Find(ctx, loan.AssetID)
+		if e != nil {
+			log.WithError(e).Errorln("find market error")
+			return e
+		}
+
+		if e = w.marketService.AccrueInterest(ctx, tx, market, t); e != nil {
+			log.Errorln(e)
+			return e
+		}
+
+		price, e := w.priceService.GetCurrentUnderlyingPrice(ctx, market)
+		if e != nil {
+			log.Errorln(e)
+			return e
+		}
+
+		covered, e := w.seizeFlashLoanCollaterals(ctx, tx, loan, price, t)
+		if e != nil {
+			return e
+		}
+
+		// the seized collaterals were counted in the other markets, reload the loan market
+		if market, _, e = w.marketStore.Find(ctx, loan.AssetID); e != nil {
+			log.WithError(e).Errorln("find market error")
+			return e
+		}
+
+		// the seized collaterals repay the loan market as the returned loan, only the due not covered is written off
+		principal, fee, fromReserves, socialized := compound.SettleFlashLoanDefault(loan.Amount, loan.Fee, covered, market.Reserves)
+		log.Infoln("flash loan defaulted, user:", loan.UserID, ":due:", loan.Due(), ":covered:", covered, ":reserves:", fromReserves, ":socialized:", socialized)
+
+		market.FlashLoans = decimal.Max(market.FlashLoans.Sub(loan.Amount), decimal.Zero).Truncate(16)
+		market.TotalCash = market.TotalCash.Add(principal).Truncate(16)
+		market.Reserves = market.Reserves.Sub(fromReserves).Truncate(16)
+		if e = w.marketStore.Update(ctx, tx, market); e != nil {
+			log.Errorln(e)
+			return e
+		}
+
+		if e = w.marketService.AccrueFee(ctx, tx, market, fee); e != nil {
+			log.Errorln(e)
+			return e
+		}
+
+		if e = w.marketService.AccrueInterest(ctx, tx, market, t); e != nil {
+			log.Errorln(e)
+			return e
+		}
+
+		loan.Status = core.FlashLoanStatusDefaulted
+		if e = w.flashLoanStore.Update(ctx, tx, loan); e != nil {
+			log.WithError(e).Errorln("update flash loan error")
+			return e
+		}
+
+		extra := core.NewTransactionExtra()
+		extra.Put(core.TransactionKeyReferTrace, loan.TraceID)
+		extra.Put(core.TransactionKeyFee, loan.Fee)
+		extra.Put(core.TransactionKeyRepayAmount, covered)
+		extra.Put(core.TransactionKeyReserves, fromReserves)
+		extra.Put(core.TransactionKeySocialized, socialized)
+		transaction := core.Transaction{
+			UserID:   loan.UserID,
+			Action:   core.ActionTypeFlashLoanDefault,
+			TraceID:  uuidutil.Modify(loan.TraceID, "flash_loan_default"),
+			FollowID: loan.FollowID,
+			AssetID:  loan.AssetID,
+			Amount:   loan.Due(),
+			Data:     extra.Format(),
+		}
+		if e = w.transactionStore.Create(ctx, tx, &transaction); e != nil {
+			log.WithError(e).Errorln("create transaction error")
+			return e
+		}
+
+		// the debts left without any collaterals are written off
+		if e = w.handleBadDebts(ctx, tx, output, loan.UserID); e != nil {
+			return e
+		}
+
+		if e = w.updateLiquidationAuction(ctx, tx, output.TraceID, loan.UserID, output.CreatedAt); e != nil {
+			return e
+		}
+	}
+
+	return nil
+}
+
+// seizeFlashLoanCollaterals seize the pledged collaterals in order until the due of the loan is covered,
+// returns the due amount covered by the seized collaterals
+func (w *Payee) seizeFlashLoanCollaterals(ctx context.Context, tx *db.DB, loan *core.FlashLoan, loanPrice decimal.Decimal, t time.Time) (decimal.Decimal, error) {
+	log := logger.FromContext(ctx).WithField("worker", "flash_loan_default")
+
+	supplies, e := w.supplyStore.FindByUser(ctx, loan.UserID)
+	if e != nil {
+		log.WithError(e).Errorln("find supplies error")
+		return decimal.Zero, e
+	}
+
+	covered := decimal.Zero
+	for _, supply := range supplies {
+		remaining := loan.Due().Sub(covered)
+		if remaining.LessThanOrEqual(decimal.Zero) {
+			break
+		}
+
+		if supply.Collaterals.LessThanOrEqual(decimal.Zero) {
+			continue
+		}
+
+		market, _, e := w.marketStore.FindByCToken(ctx, supply.CTokenAssetID)
+		if e != nil {
+			log.WithError(e).Errorln("find market error")
+			return decimal.Zero, e
+		}
+
+		if e = w.marketService.AccrueInterest(ctx, tx, market, t); e != nil {
+			log.Errorln(e)
+			return decimal.Zero, e
+		}
+
+		// distribute rewards before the collaterals changed
+		if _, e = w.rewardService.Distribute(ctx, tx, market, loan.UserID); e != nil {
+			log.Errorln(e)
+			return decimal.Zero, e
+		}
+
+		price, e := w.priceService.GetCurrentUnderlyingPrice(ctx, market)
+		if e != nil {
+			log.Errorln(e)
+			return decimal.Zero, e
+		}
+
+		exchangeRate, e := w.marketService.CurExchangeRate(ctx, market)
+		if e != nil {
+			log.Errorln(e)
+			return decimal.Zero, e
+		}
+
+		repay, seizedAmount := compound.SelfLiquidate(remaining, loanPrice, supply.Collaterals.Mul(exchangeRate), price)
+		seizedCTokens := decimal.Min(seizedAmount.Div(exchangeRate), supply.Collaterals).Truncate(16)
+		if seizedCTokens.LessThanOrEqual(decimal.Zero) {
+			continue
+		}
+
+		supply.Collaterals = supply.Collaterals.Sub(seizedCTokens).Truncate(16)
+		if e = w.supplyStore.Update(ctx, tx, supply); e != nil {
+			log.Errorln(e)
+			return decimal.Zero, e
+		}
+
+		// the seized underlying stays in the cash and is owned by the reserves, the exchange rate is unchanged
+		market.CTokens = market.CTokens.Sub(seizedCTokens).Truncate(16)
+		market.Reserves = market.Reserves.Add(seizedAmount).Truncate(16)
+		if e = w.marketStore.Update(ctx, tx, market); e != nil {
+			log.Errorln(e)
+			return decimal.Zero, e
+		}
+
+		if e = w.marketService.AccrueInterest(ctx, tx, market, t); e != nil {
+			log.Errorln(e)
+			return decimal.Zero, e
+		}
+
+		covered = covered.Add(repay)
+
+		extra := core.NewTransactionExtra()
+		extra.Put(core.TransactionKeyReferTrace, loan.TraceID)
+		extra.Put(core.TransactionKeyCTokenAssetID, supply.CTokenAssetID)
+		extra.Put(core.TransactionKeyCTokens, seizedCTokens)
+		extra.Put(core.TransactionKeyPrice, price)
+		transaction := core.Transaction{
+			UserID:   loan.UserID,
+			Action:   core.ActionTypeFlashLoanDefault,
+			TraceID:  uuidutil.Modify(loan.TraceID, "flash_loan_seize:"+supply.CTokenAssetID),
+			FollowID: loan.FollowID,
+			AssetID:  market.AssetID,
+			Amount:   seizedAmount,
+			Data:     extra.Format(),
+		}
+		if e = w.transactionStore.Create(ctx, tx, &transaction); e != nil {
+			log.WithError(e).Errorln("create transaction error")
+			return decimal.Zero, e
+		}
+	}
+
+	return covered, nil
+}
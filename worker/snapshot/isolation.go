@@ -0,0 +1,57 @@
+package snapshot
+
+import (
+	"compound/core"
+	"context"
+
+	"github.com/shopspring/decimal"
+)
+
+// pledgeAllowedInIsolation check the isolation mode before pledging
+//
+// the isolated collateral can not be pledged with any other collaterals,
+// and can only be pledged by the users without debts when entering the isolation mode
+func (w *Payee) pledgeAllowedInIsolation(ctx context.Context, userID string, market *core.Market) (bool, error) {
+	supplies, e := w.supplyStore.FindByUser(ctx, userID)
+	if e != nil {
+		return false, e
+	}
+
+	pledged := false
+	for _, supply := range supplies {
+		if supply.Collaterals.LessThanOrEqual(decimal.Zero) {
+			continue
+		}
+
+		if supply.CTokenAssetID == market.CTokenAssetID {
+			pledged = true
+			continue
+		}
+
+		other, _, e := w.marketStore.FindByCToken(ctx, supply.CTokenAssetID)
+		if e != nil {
+			return false, e
+		}
+
+		if market.IsIsolated() || other.IsIsolated() {
+			return false, nil
+		}
+	}
+
+	if !market.IsIsolated() || pledged {
+		return true, nil
+	}
+
+	borrows, e := w.borrowStore.FindByUser(ctx, userID)
+	if e != nil {
+		return false, e
+	}
+
+	for _, borrow := range borrows {
+		if borrow.Principal.GreaterThan(decimal.Zero) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
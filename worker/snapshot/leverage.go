@@ -0,0 +1,382 @@
+package snapshot
+
+import (
+	"compound/core"
+	"compound/internal/compound"
+	"compound/pkg/dbtx"
+	"compound/pkg/mtg"
+	"context"
+	"errors"
+
+	"github.com/fox-one/pkg/logger"
+	"github.com/fox-one/pkg/store/db"
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// handle leverage event
+//
+// the supplied asset is pledged, then borrowed against and re-supplied in loops up to the requested leverage,
+// the borrowed amount never leaves the market, so only the ctokens and borrows of the user grow
+func (w *Payee) handleLeverageEvent(ctx context.Context, tx *db.DB, output *core.Output, userID, followID string, body []byte) error {
+	log := logger.FromContext(ctx).WithField("worker", "leverage")
+
+	var leverage decimal.Decimal
+	if _, err := mtg.Scan(body, &leverage); err != nil {
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeLeverage, core.ErrInvalidArgument, "")
+	}
+
+	supplyAmount := output.Amount.Abs()
+	log.Infoln("leverage, asset:", output.AssetID, ":amount:", supplyAmount, ":leverage:", leverage)
+
+	if leverage.LessThanOrEqual(decimal.NewFromInt(1)) {
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeLeverage, core.ErrInvalidArgument, "")
+	}
+
+	market, isRecordNotFound, e := w.marketStore.Find(ctx, output.AssetID)
+	if isRecordNotFound {
+		log.Warningln("market not found")
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeLeverage, core.ErrMarketNotFound, "")
+	}
+	if e != nil {
+		log.WithError(e).Errorln("find market error")
+		return e
+	}
+
+	if w.marketService.IsMarketClosed(ctx, market) {
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeLeverage, core.ErrMarketClosed, "")
+	}
+
+	// the prices of all the markets in the liquidity of the user should be fresh
+	stale, e := w.isPriceStale(ctx, userID, output.CreatedAt, market)
+	if e != nil {
+		log.Errorln(e)
+		return e
+	}
+
+	if stale {
+		log.Warningln("price stale")
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeLeverage, core.ErrPriceStale, "")
+	}
+
+	if market.CollateralFactor.LessThanOrEqual(decimal.Zero) {
+		log.Errorln(errors.New("pledge disallowed"))
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeLeverage, core.ErrPledgeNotAllowed, "")
+	}
+
+	// the isolated collateral can not be borrowed against itself
+	isolatedMarket, e := w.accountService.IsolatedMarket(ctx, userID)
+	if e != nil {
+		log.Errorln(e)
+		return e
+	}
+	if market.IsIsolated() || isolatedMarket != nil {
+		log.Errorln(errors.New("isolation mode violated"))
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeLeverage, core.ErrIsolationModeViolated, "")
+	}
+
+	// accrue interest
+	if e = w.marketService.AccrueInterest(ctx, tx, market, output.CreatedAt); e != nil {
+		log.Errorln(e)
+		return e
+	}
+
+	// distribute rewards before the collaterals and borrow changed
+	if _, e = w.rewardService.Distribute(ctx, tx, market, userID); e != nil {
+		log.Errorln(e)
+		return e
+	}
+
+	borrow, isRecordNotFound, e := w.borrowStore.Find(ctx, userID, market.AssetID)
+	if e != nil && !isRecordNotFound {
+		log.Errorln(e)
+		return e
+	}
+
+	// the leveraged position is borrowed at the variable rate
+	if borrow != nil && borrow.IsStable() && borrow.Principal.GreaterThan(decimal.Zero) {
+		log.Warningln("borrow rate mode mismatch")
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeLeverage, core.ErrBorrowRateModeMismatch, "")
+	}
+
+	borrowAmount, loops := compound.Leverage(supplyAmount, market.CollateralFactor, leverage, compound.LeverageMaxLoops)
+	if borrowAmount.LessThanOrEqual(decimal.Zero) {
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeLeverage, core.ErrInvalidAmount, "")
+	}
+
+	log.Infoln("leverage, borrow:", borrowAmount, ":loops:", loops)
+
+	totalAmount := supplyAmount.Add(borrowAmount)
+
+	// check supply cap
+	if market.SupplyCap.GreaterThan(decimal.Zero) {
+		totalSupplies, e := w.marketService.CurTotalSupplies(ctx, market)
+		if e != nil {
+			log.Errorln(e)
+			return e
+		}
+
+		if totalSupplies.Add(totalAmount).GreaterThan(market.SupplyCap) {
+			log.Warningln("supplies over cap")
+			return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeLeverage, core.ErrSupplyOverCap, "")
+		}
+	}
+
+	blockNum, e := w.blockService.GetBlock(ctx, output.CreatedAt)
+	if e != nil {
+		log.Errorln(e)
+		return e
+	}
+
+	exchangeRate, e := w.marketService.CurExchangeRate(ctx, market)
+	if e != nil {
+		log.Errorln(e)
+		return e
+	}
+
+	ctokens := totalAmount.Div(exchangeRate).Truncate(8)
+	if ctokens.LessThan(decimal.NewFromFloat(0.00000001)) {
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeLeverage, core.ErrInvalidAmount, "")
+	}
+
+	// the leverage is rolled back if the account is short of liquidity after it,
+	// checked by the account liquidity with the e-mode collateral factors and the collateral prices
+	e = dbtx.Savepoint(tx, "leverage", func() error {
+		// the borrowed amount is re-supplied, only the supplied amount is added to the cash
+		market.CTokens = market.CTokens.Add(ctokens).Truncate(16)
+		market.TotalCash = market.TotalCash.Add(supplyAmount).Truncate(16)
+		market.TotalBorrows = market.TotalBorrows.Add(borrowAmount).Truncate(16)
+		if e = w.marketStore.Update(ctx, tx, market); e != nil {
+			log.Errorln(e)
+			return e
+		}
+
+		supply, isRecordNotFound, e := w.supplyStore.Find(ctx, userID, market.CTokenAssetID)
+		if e != nil {
+			if !isRecordNotFound {
+				log.Errorln(e)
+				return e
+			}
+
+			supply = &core.Supply{
+				UserID:        userID,
+				CTokenAssetID: market.CTokenAssetID,
+				Collaterals:   ctokens,
+			}
+			if e = w.supplyStore.Save(ctx, tx, supply); e != nil {
+				log.Errorln(e)
+				return e
+			}
+		} else {
+			supply.Collaterals = supply.Collaterals.Add(ctokens).Truncate(16)
+			if e = w.supplyStore.Update(ctx, tx, supply); e != nil {
+				log.Errorln(e)
+				return e
+			}
+		}
+
+		if borrow == nil {
+			borrow = &core.Borrow{
+				UserID:          userID,
+				AssetID:         market.AssetID,
+				Principal:       borrowAmount,
+				InterestIndex:   market.BorrowIndex,
+				RateMode:        core.BorrowRateModeVariable,
+				StableRateBlock: market.BlockNumber,
+			}
+			if e = w.borrowStore.Save(ctx, tx, borrow); e != nil {
+				log.Errorln(e)
+				return e
+			}
+		} else {
+			borrowBalance, e := w.borrowService.BorrowBalance(ctx, borrow, market)
+			if e != nil {
+				log.Errorln(e)
+				return e
+			}
+
+			borrow.Principal = borrowBalance.Add(borrowAmount).Truncate(16)
+			borrow.InterestIndex = market.BorrowIndex.Truncate(16)
+			borrow.RateMode = core.BorrowRateModeVariable
+			borrow.StableRateBlock = market.BlockNumber
+			if e = w.borrowStore.Update(ctx, tx, borrow); e != nil {
+				log.Errorln(e)
+				return e
+			}
+		}
+
+		// accrue interest
+		if e = w.marketService.AccrueInterest(ctx, tx, market, output.CreatedAt); e != nil {
+			log.Errorln(e)
+			return e
+		}
+
+		liquidity, e := w.accountService.CalculateBorrowLiquidity(ctx, userID, blockNum, market)
+		if e != nil {
+			log.Errorln(e)
+			return e
+		}
+
+		if liquidity.LessThan(decimal.Zero) {
+			log.Errorln(errors.New("insufficient liquidity"))
+			return core.ErrInsufficientLiquidity
+		}
+
+		return nil
+	})
+	if e == core.ErrInsufficientLiquidity {
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeLeverage, core.ErrInsufficientLiquidity, "")
+	}
+	if e != nil {
+		return e
+	}
+
+	// add transaction
+	extra := core.NewTransactionExtra()
+	extra.Put(core.TransactionKeyCTokenAssetID, market.CTokenAssetID)
+	extra.Put(core.TransactionKeyCTokens, ctokens)
+	extra.Put(core.TransactionKeyBorrowAmount, borrowAmount)
+	transaction := core.BuildTransactionFromOutput(ctx, userID, followID, core.ActionTypeLeverage, output, &extra)
+	if e = w.transactionStore.Create(ctx, tx, transaction); e != nil {
+		log.WithError(e).Errorln("create transaction error")
+		return e
+	}
+
+	return nil
+}
+
+// handle deleverage event
+//
+// the pledged collateral is redeemed to repay the borrow of the same market in one step,
+// which never lowers the liquidity as the collateral factor is less than 1
+func (w *Payee) handleDeleverageEvent(ctx context.Context, tx *db.DB, output *core.Output, userID, followID string, body []byte) error {
+	log := logger.FromContext(ctx).WithField("worker", "deleverage")
+
+	var asset uuid.UUID
+	var repayAmount decimal.Decimal
+	if _, err := mtg.Scan(body, &asset, &repayAmount); err != nil {
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeDeleverage, core.ErrInvalidArgument, "")
+	}
+
+	log.Infoln("deleverage, asset:", asset.String(), ":amount:", repayAmount)
+
+	if repayAmount.LessThanOrEqual(decimal.Zero) {
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeDeleverage, core.ErrInvalidAmount, "")
+	}
+
+	market, isRecordNotFound, e := w.marketStore.Find(ctx, asset.String())
+	if isRecordNotFound {
+		log.Warningln("market not found")
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeDeleverage, core.ErrMarketNotFound, "")
+	}
+	if e != nil {
+		log.WithError(e).Errorln("find market error")
+		return e
+	}
+
+	if w.marketService.IsMarketClosed(ctx, market) {
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeDeleverage, core.ErrMarketClosed, "")
+	}
+
+	// accrue interest
+	if e = w.marketService.AccrueInterest(ctx, tx, market, output.CreatedAt); e != nil {
+		log.Errorln(e)
+		return e
+	}
+
+	// distribute rewards before the collaterals and borrow changed
+	if _, e = w.rewardService.Distribute(ctx, tx, market, userID); e != nil {
+		log.Errorln(e)
+		return e
+	}
+
+	supply, isRecordNotFound, e := w.supplyStore.Find(ctx, userID, market.CTokenAssetID)
+	if isRecordNotFound {
+		log.Warningln("supply not found")
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeDeleverage, core.ErrSupplyNotFound, "")
+	}
+	if e != nil {
+		log.WithError(e).Errorln("find supply error")
+		return e
+	}
+
+	borrow, isRecordNotFound, e := w.borrowStore.Find(ctx, userID, market.AssetID)
+	if isRecordNotFound {
+		log.Warningln("borrow not found")
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeDeleverage, core.ErrBorrowNotFound, "")
+	}
+	if e != nil {
+		log.WithError(e).Errorln("find borrow error")
+		return e
+	}
+
+	exchangeRate, e := w.marketService.CurExchangeRate(ctx, market)
+	if e != nil {
+		log.Errorln(e)
+		return e
+	}
+
+	borrowBalance, e := w.borrowService.BorrowBalance(ctx, borrow, market)
+	if e != nil {
+		log.Errorln(e)
+		return e
+	}
+
+	repayAmount = decimal.Min(repayAmount, borrowBalance, supply.Collaterals.Mul(exchangeRate)).Truncate(8)
+	ctokens := decimal.Min(repayAmount.Div(exchangeRate).Truncate(16), supply.Collaterals)
+	if repayAmount.LessThanOrEqual(decimal.Zero) || ctokens.LessThanOrEqual(decimal.Zero) {
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeDeleverage, core.ErrInvalidAmount, "")
+	}
+
+	supply.Collaterals = supply.Collaterals.Sub(ctokens).Truncate(16)
+	if e = w.supplyStore.Update(ctx, tx, supply); e != nil {
+		log.Errorln(e)
+		return e
+	}
+
+	newBorrowBalance := borrowBalance.Sub(repayAmount).Truncate(16)
+	newIndex := market.BorrowIndex
+	if newBorrowBalance.LessThanOrEqual(decimal.Zero) {
+		newBorrowBalance = decimal.Zero
+		newIndex = decimal.Zero
+	}
+	principal := borrow.Principal
+	borrow.Principal = newBorrowBalance
+	borrow.InterestIndex = newIndex.Truncate(16)
+	borrow.StableRateBlock = market.BlockNumber
+	if e = w.borrowStore.Update(ctx, tx, borrow); e != nil {
+		log.Errorln(e)
+		return e
+	}
+
+	// the redeemed amount repays the borrow directly, the cash is unchanged
+	market.CTokens = market.CTokens.Sub(ctokens).Truncate(16)
+	market.TotalBorrows = market.TotalBorrows.Sub(repayAmount).Truncate(16)
+	if borrow.IsStable() {
+		market.TotalStableBorrows, market.AvgStableRate = compound.UpdateStableBorrows(market.TotalStableBorrows, market.AvgStableRate, borrowBalance, principal, borrow.StableRate, borrow.Principal, borrow.StableRate)
+	}
+	if e = w.marketStore.Update(ctx, tx, market); e != nil {
+		log.Errorln(e)
+		return e
+	}
+
+	// accrue interest
+	if e = w.marketService.AccrueInterest(ctx, tx, market, output.CreatedAt); e != nil {
+		log.Errorln(e)
+		return e
+	}
+
+	// add transaction
+	extra := core.NewTransactionExtra()
+	extra.Put(core.TransactionKeyCTokenAssetID, market.CTokenAssetID)
+	extra.Put(core.TransactionKeyCTokens, ctokens)
+	extra.Put(core.TransactionKeyRepayAmount, repayAmount)
+	transaction := core.BuildTransactionFromOutput(ctx, userID, followID, core.ActionTypeDeleverage, output, &extra)
+	if e = w.transactionStore.Create(ctx, tx, transaction); e != nil {
+		log.WithError(e).Errorln("create transaction error")
+		return e
+	}
+
+	// the debts left without any collaterals are written off
+	return w.handleBadDebts(ctx, tx, output, userID)
+}
@@ -2,6 +2,7 @@ package snapshot
 
 import (
 	"compound/core"
+	"compound/internal/compound"
 	"compound/pkg/mtg"
 	"context"
 
@@ -77,17 +78,32 @@ func (w *Payee) handleLiquidationEvent(ctx context.Context, tx *db.DB, output *c
 		return e
 	}
 
-	// to repay
-	borrowMarket, isRecordNotFound, e := w.marketStore.Find(ctx, userPayAssetID)
-	if isRecordNotFound {
-		log.Warningln("borrow market not found")
-		return w.handleRefundEvent(ctx, tx, output, liquidator, followID, core.ActionTypeLiquidate, core.ErrMarketNotFound, "")
+	// to repay, the same market is shared to write through one copy
+	borrowMarket := supplyMarket
+	if userPayAssetID != seizedAssetID {
+		borrowMarket, isRecordNotFound, e = w.marketStore.Find(ctx, userPayAssetID)
+		if isRecordNotFound {
+			log.Warningln("borrow market not found")
+			return w.handleRefundEvent(ctx, tx, output, liquidator, followID, core.ActionTypeLiquidate, core.ErrMarketNotFound, "")
+		}
+		if e != nil {
+			log.WithError(e).Errorln("find borrow market error")
+			return e
+		}
 	}
+
+	// the prices of all the markets in the liquidity of the seized user should be fresh
+	stale, e := w.isPriceStale(ctx, seizedUserID, output.CreatedAt, supplyMarket, borrowMarket)
 	if e != nil {
-		log.WithError(e).Errorln("find borrow market error")
+		log.Errorln(e)
 		return e
 	}
 
+	if stale {
+		log.Warningln("price stale")
+		return w.handleRefundEvent(ctx, tx, output, liquidator, followID, core.ActionTypeLiquidate, core.ErrPriceStale, "")
+	}
+
 	//supply market accrue interest
 	if e = w.marketService.AccrueInterest(ctx, tx, supplyMarket, output.CreatedAt); e != nil {
 		log.Errorln(e)
@@ -100,6 +116,18 @@ func (w *Payee) handleLiquidationEvent(ctx context.Context, tx *db.DB, output *c
 		return e
 	}
 
+	// distribute rewards of the seized user before the collaterals and borrow changed
+	if _, e = w.rewardService.Distribute(ctx, tx, supplyMarket, seizedUserID); e != nil {
+		log.Errorln(e)
+		return e
+	}
+	if borrowMarket.AssetID != supplyMarket.AssetID {
+		if _, e = w.rewardService.Distribute(ctx, tx, borrowMarket, seizedUserID); e != nil {
+			log.Errorln(e)
+			return e
+		}
+	}
+
 	supply, isRecordNotFound, e := w.supplyStore.Find(ctx, seizedUserID, supplyMarket.CTokenAssetID)
 	if isRecordNotFound {
 		log.Warningln("supply not found")
@@ -154,7 +182,33 @@ func (w *Payee) handleLiquidationEvent(ctx context.Context, tx *db.DB, output *c
 	}
 
 	maxSeize := supply.Collaterals.Mul(supplyExchangeRate).Mul(supplyMarket.CloseFactor)
-	seizedPrice := supplyPrice.Sub(supplyPrice.Mul(supplyMarket.LiquidationIncentive))
+	liquidationIncentive, e := w.accountService.LiquidationIncentive(ctx, seizedUserID, supplyMarket)
+	if e != nil {
+		log.Errorln(e)
+		return e
+	}
+
+	// the discount of the auction grows up to the liquidation incentive
+	if supplyMarket.IsLiquidationAuction() {
+		discount, opened, e := w.liquidationAuctionDiscount(ctx, seizedUserID, supplyMarket, liquidationIncentive, output.CreatedAt)
+		if e != nil {
+			log.Errorln(e)
+			return e
+		}
+
+		// the position drifted into liquidation without any event is put up for auction by the first attempt
+		if !opened {
+			log.Warningln("liquidation auction not opened")
+			if e = w.updateLiquidationAuction(ctx, tx, output.TraceID, seizedUserID, output.CreatedAt); e != nil {
+				return e
+			}
+
+			return w.handleRefundEvent(ctx, tx, output, liquidator, followID, core.ActionTypeLiquidate, core.ErrSeizeNotAllowed, "")
+		}
+
+		liquidationIncentive = discount
+	}
+	seizedPrice := supplyPrice.Sub(supplyPrice.Mul(liquidationIncentive))
 	maxSeizeValue := maxSeize.Mul(seizedPrice)
 	repayValue := userPayAmount.Mul(borrowPrice)
 	borrowBalanceValue := borrowBalance.Mul(borrowPrice)
@@ -195,8 +249,10 @@ func (w *Payee) handleLiquidationEvent(ctx context.Context, tx *db.DB, output *c
 		newBorrowBalance = decimal.Zero
 		newIndex = decimal.Zero
 	}
+	principal := borrow.Principal
 	borrow.Principal = newBorrowBalance.Truncate(16)
 	borrow.InterestIndex = newIndex.Truncate(16)
+	borrow.StableRateBlock = borrowMarket.BlockNumber
 	if e = w.borrowStore.Update(ctx, tx, borrow); e != nil {
 		log.Errorln(e)
 		return e
@@ -204,6 +260,9 @@ func (w *Payee) handleLiquidationEvent(ctx context.Context, tx *db.DB, output *c
 
 	borrowMarket.TotalBorrows = borrowMarket.TotalBorrows.Sub(reallyRepayAmount).Truncate(16)
 	borrowMarket.TotalCash = borrowMarket.TotalCash.Add(reallyRepayAmount).Truncate(16)
+	if borrow.IsStable() {
+		borrowMarket.TotalStableBorrows, borrowMarket.AvgStableRate = compound.UpdateStableBorrows(borrowMarket.TotalStableBorrows, borrowMarket.AvgStableRate, borrowBalance, principal, borrow.StableRate, borrow.Principal, borrow.StableRate)
+	}
 	if e = w.marketStore.Update(ctx, tx, borrowMarket); e != nil {
 		log.Errorln(e)
 		return e
@@ -238,6 +297,15 @@ func (w *Payee) handleLiquidationEvent(ctx context.Context, tx *db.DB, output *c
 		return e
 	}
 
+	// the debts left without any collaterals are written off
+	if e = w.handleBadDebts(ctx, tx, output, seizedUserID); e != nil {
+		return e
+	}
+
+	if e = w.updateLiquidationAuction(ctx, tx, output.TraceID, seizedUserID, output.CreatedAt); e != nil {
+		return e
+	}
+
 	// transfer
 	transferAction := core.TransferAction{
 		Source:   core.ActionTypeLiquidateTransfer,
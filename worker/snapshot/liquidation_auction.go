@@ -0,0 +1,225 @@
+package snapshot
+
+import (
+	"compound/core"
+	"compound/core/proposal"
+	"compound/internal/compound"
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fox-one/pkg/logger"
+	"github.com/fox-one/pkg/store/db"
+	uuidutil "github.com/fox-one/pkg/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// updateLiquidationAuction open the liquidation auction of the user if the position became liquidatable,
+// or close it if the position is healthy again
+//
+// the auctions are updated on the events changing the positions: the actions of the user, the repayments and the delegated borrows on behalf of the user,
+// the liquidations and the defaulted flash loans of the user, and the price and collateral factor updates of the markets,
+// the positions drifting into liquidation by the interest are put up for auction at the first liquidation attempt
+func (w *Payee) updateLiquidationAuction(ctx context.Context, tx *db.DB, traceID, userID string, t time.Time) error {
+	log := logger.FromContext(ctx).WithField("worker", "liquidation_auction")
+
+	auction, isRecordNotFound, e := w.liquidationAuctionStore.FindOpen(ctx, userID)
+	if e != nil && !isRecordNotFound {
+		log.WithError(e).Errorln("find liquidation auction error")
+		return e
+	}
+
+	enabled, e := w.isLiquidationAuctionEnabled(ctx)
+	if e != nil {
+		return e
+	}
+
+	liquidatable := false
+	if enabled {
+		blockNum, e := w.blockService.GetBlock(ctx, t)
+		if e != nil {
+			log.Errorln(e)
+			return e
+		}
+
+		liquidity, e := w.accountService.CalculateAccountLiquidity(ctx, userID, blockNum)
+		if e != nil {
+			log.Errorln(e)
+			return e
+		}
+
+		liquidatable = liquidity.LessThan(decimal.Zero)
+	}
+
+	if liquidatable && isRecordNotFound {
+		auction = &core.LiquidationAuction{
+			TraceID: uuidutil.Modify(traceID, "liquidation_auction:"+userID),
+			UserID:  userID,
+			Status:  core.LiquidationAuctionStatusOpen,
+			StartAt: t,
+		}
+		if e = w.liquidationAuctionStore.Save(ctx, tx, auction); e != nil {
+			log.WithError(e).Errorln("save liquidation auction error")
+			return e
+		}
+	} else if !liquidatable && !isRecordNotFound {
+		auction.Status = core.LiquidationAuctionStatusClosed
+		if e = w.liquidationAuctionStore.Update(ctx, tx, auction); e != nil {
+			log.WithError(e).Errorln("update liquidation auction error")
+			return e
+		}
+	}
+
+	return nil
+}
+
+// updateMarketLiquidationAuctions update the liquidation auctions of the users pledging or borrowing from the market,
+// after the price or the collateral factor of the market changed
+func (w *Payee) updateMarketLiquidationAuctions(ctx context.Context, tx *db.DB, traceID string, market *core.Market, t time.Time) error {
+	log := logger.FromContext(ctx).WithField("worker", "liquidation_auction")
+
+	enabled, e := w.isLiquidationAuctionEnabled(ctx)
+	if e != nil {
+		return e
+	}
+
+	// the auctions left open are closed by the actions of the users
+	if !enabled {
+		return nil
+	}
+
+	users := make(map[string]bool)
+	supplies, e := w.supplyStore.FindByCTokenAssetID(ctx, market.CTokenAssetID)
+	if e != nil {
+		log.WithError(e).Errorln("list supplies error")
+		return e
+	}
+
+	for _, supply := range supplies {
+		if supply.Collaterals.GreaterThan(decimal.Zero) {
+			users[supply.UserID] = true
+		}
+	}
+
+	borrows, e := w.borrowStore.FindByAssetID(ctx, market.AssetID)
+	if e != nil {
+		log.WithError(e).Errorln("list borrows error")
+		return e
+	}
+
+	for _, borrow := range borrows {
+		if borrow.Principal.GreaterThan(decimal.Zero) {
+			users[borrow.UserID] = true
+		}
+	}
+
+	userIDs := make([]string, 0, len(users))
+	for userID := range users {
+		userIDs = append(userIDs, userID)
+	}
+	sort.Strings(userIDs)
+
+	for _, userID := range userIDs {
+		if e = w.updateLiquidationAuction(ctx, tx, traceID, userID, t); e != nil {
+			return e
+		}
+	}
+
+	return nil
+}
+
+// isLiquidationAuctionEnabled whether any market is liquidated by auction
+func (w *Payee) isLiquidationAuctionEnabled(ctx context.Context) (bool, error) {
+	markets, e := w.marketStore.All(ctx)
+	if e != nil {
+		return false, e
+	}
+
+	for _, market := range markets {
+		if market.IsLiquidationAuction() {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// liquidationAuctionDiscount the discount of the collateral liquidated by auction,
+// false if the position is not put up for auction yet
+func (w *Payee) liquidationAuctionDiscount(ctx context.Context, userID string, market *core.Market, maxDiscount decimal.Decimal, t time.Time) (decimal.Decimal, bool, error) {
+	auction, isRecordNotFound, e := w.liquidationAuctionStore.FindOpen(ctx, userID)
+	if isRecordNotFound {
+		return decimal.Zero, false, nil
+	}
+	if e != nil {
+		return decimal.Zero, false, e
+	}
+
+	window := time.Duration(market.LiquidationAuctionWindow) * time.Second
+	return compound.LiquidationAuctionDiscount(maxDiscount, auction.StartAt, t, window), true, nil
+}
+
+// handleUpdateLiquidationModeEvent switch the liquidation mode of the collateral market
+func (w *Payee) handleUpdateLiquidationModeEvent(ctx context.Context, p *core.Proposal, req proposal.UpdateLiquidationModeReq, t time.Time) error {
+	return w.transaction(ctx, func(tx *db.DB) error {
+		log := logger.FromContext(ctx).WithField("worker", "update-liquidation-mode")
+
+		mode := core.LiquidationMode(req.Mode)
+		if !mode.IsValid() {
+			log.Warningln("invalid liquidation mode:", req.Mode)
+			return skipProposal(ctx)
+		}
+
+		if mode == core.LiquidationModeAuction && req.Window <= 0 {
+			log.Warningln("invalid liquidation auction window:", req.Window)
+			return skipProposal(ctx)
+		}
+
+		market, isRecordNotFound, e := w.marketStore.FindBySymbol(ctx, strings.ToUpper(req.Symbol))
+		if e != nil {
+			if isRecordNotFound {
+				return skipProposal(ctx)
+			}
+
+			return e
+		}
+
+		market.LiquidationMode = mode
+		if req.Window > 0 {
+			market.LiquidationAuctionWindow = req.Window
+		}
+
+		if e = w.marketStore.Update(ctx, tx, market); e != nil {
+			log.Errorln(e)
+			return e
+		}
+
+		// the liquidatable positions are put up for auction once the market is liquidated by auction
+		if market.IsLiquidationAuction() {
+			return w.updateMarketLiquidationAuctions(ctx, tx, p.TraceID, market, t)
+		}
+
+		enabled, e := w.isLiquidationAuctionEnabled(ctx)
+		if e != nil || enabled {
+			return e
+		}
+
+		// the open auctions are closed once no market is liquidated by auction
+		auctions, e := w.liquidationAuctionStore.ListOpen(ctx)
+		if e != nil {
+			log.WithError(e).Errorln("list open liquidation auctions error")
+			return e
+		}
+
+		for _, auction := range auctions {
+			auction.Status = core.LiquidationAuctionStatusClosed
+			if e = w.liquidationAuctionStore.Update(ctx, tx, auction); e != nil {
+				log.WithError(e).Errorln("update liquidation auction error")
+				return e
+			}
+		}
+
+		return nil
+	})
+}
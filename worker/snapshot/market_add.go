@@ -7,16 +7,24 @@ import (
 	"strings"
 
 	"github.com/fox-one/pkg/logger"
+	"github.com/fox-one/pkg/store/db"
 )
 
 func (w *Payee) handleAddMarketEvent(ctx context.Context, p *core.Proposal, req proposal.AddMarketReq) error {
 	log := logger.FromContext(ctx).WithField("worker", "add-market")
 
 	log.Infof("asset:%s", req.AssetID)
+
+	// the reward pool would be paid out of the suppliers' cash
+	if rewardAsset := w.system.RewardAsset; rewardAsset != "" && (req.AssetID == rewardAsset || req.CTokenAssetID == rewardAsset) {
+		log.Warningln("reward asset can not be listed:", rewardAsset)
+		return skipProposal(ctx)
+	}
+
 	_, isRecordNotFound, e := w.marketStore.Find(ctx, req.AssetID)
 	if e == nil {
 		//market exists
-		return nil
+		return skipProposal(ctx)
 	}
 
 	if isRecordNotFound {
@@ -27,7 +35,9 @@ func (w *Payee) handleAddMarketEvent(ctx context.Context, p *core.Proposal, req
 			Status:        core.MarketStatusOpen,
 		}
 
-		if e = w.marketStore.Save(ctx, w.db, &market); e != nil {
+		if e = w.transaction(ctx, func(tx *db.DB) error {
+			return w.marketStore.Save(ctx, tx, &market)
+		}); e != nil {
 			return e
 		}
 	}
@@ -11,13 +11,13 @@ import (
 )
 
 func (w *Payee) handleOpenMarketEvent(ctx context.Context, p *core.Proposal, req proposal.MarketStatusReq, t time.Time) error {
-	return w.db.Tx(func(tx *db.DB) error {
+	return w.transaction(ctx, func(tx *db.DB) error {
 		log := logger.FromContext(ctx).WithField("worker", "open-market")
 
 		market, isRecordNotFound, e := w.marketStore.Find(ctx, req.AssetID)
 		if e != nil {
 			if isRecordNotFound {
-				return nil
+				return skipProposal(ctx)
 			}
 
 			return e
@@ -38,13 +38,13 @@ func (w *Payee) handleOpenMarketEvent(ctx context.Context, p *core.Proposal, req
 }
 
 func (w *Payee) handleCloseMarketEvent(ctx context.Context, p *core.Proposal, req proposal.MarketStatusReq, t time.Time) error {
-	return w.db.Tx(func(tx *db.DB) error {
+	return w.transaction(ctx, func(tx *db.DB) error {
 		log := logger.FromContext(ctx).WithField("worker", "close-market")
 
 		market, isRecordNotFound, e := w.marketStore.Find(ctx, req.AssetID)
 		if e != nil {
 			if isRecordNotFound {
-				return nil
+				return skipProposal(ctx)
 			}
 
 			return e
@@ -14,13 +14,13 @@ import (
 )
 
 func (w *Payee) handleUpdateMarketEvent(ctx context.Context, p *core.Proposal, req proposal.UpdateMarketReq, t time.Time) error {
-	return w.db.Tx(func(tx *db.DB) error {
+	return w.transaction(ctx, func(tx *db.DB) error {
 		log := logger.FromContext(ctx).WithField("worker", "update-market")
 
 		market, isRecordNotFound, e := w.marketStore.FindBySymbol(ctx, strings.ToUpper(req.Symbol))
 		if e != nil {
 			if isRecordNotFound {
-				return nil
+				return skipProposal(ctx)
 			}
 
 			return e
@@ -57,18 +57,19 @@ func (w *Payee) handleUpdateMarketEvent(ctx context.Context, p *core.Proposal, r
 			return e
 		}
 
-		return nil
+		// the positions pledging the market are revalued by the collateral factor
+		return w.updateMarketLiquidationAuctions(ctx, tx, p.TraceID, market, t)
 	})
 }
 
 func (w *Payee) handleUpdateMarketAdvanceEvent(ctx context.Context, p *core.Proposal, req proposal.UpdateMarketAdvanceReq, t time.Time) error {
-	return w.db.Tx(func(tx *db.DB) error {
+	return w.transaction(ctx, func(tx *db.DB) error {
 		log := logger.FromContext(ctx).WithField("worker", "update-market-advance")
 
 		market, isRecordNotFound, e := w.marketStore.FindBySymbol(ctx, strings.ToUpper(req.Symbol))
 		if e != nil {
 			if isRecordNotFound {
-				return nil
+				return skipProposal(ctx)
 			}
 
 			return e
@@ -82,7 +83,7 @@ func (w *Payee) handleUpdateMarketAdvanceEvent(ctx context.Context, p *core.Prop
 			market.BorrowCap = req.BorrowCap
 		}
 
-		if req.CloseFactor.GreaterThanOrEqual(compound.CloseFactorMin) && req.CloseFactor.LessThanOrEqual(compound.CloseFactorMax) {
+		if compound.IsValidCloseFactor(req.CloseFactor) {
 			market.CloseFactor = req.CloseFactor
 		}
 
@@ -94,10 +95,119 @@ func (w *Payee) handleUpdateMarketAdvanceEvent(ctx context.Context, p *core.Prop
 			market.JumpMultiplier = req.JumpMultiplier
 		}
 
-		if req.Kink.GreaterThanOrEqual(decimal.Zero) && req.Kink.LessThan(decimal.NewFromInt(1)) {
+		if compound.IsValidKink(req.Kink) {
+			market.Kink = req.Kink
+		}
+
+		if req.FlashLoanFee.GreaterThanOrEqual(decimal.Zero) && req.FlashLoanFee.LessThan(decimal.NewFromInt(1)) {
+			market.FlashLoanFee = req.FlashLoanFee
+		}
+
+		if req.SupplyCap.GreaterThanOrEqual(decimal.Zero) {
+			market.SupplyCap = req.SupplyCap
+		}
+
+		if req.TwapWindow >= 0 && req.TwapWindow <= core.MaxTwapWindow {
+			market.TwapWindow = req.TwapWindow
+		}
+
+		if req.MaxPriceAge >= 0 {
+			market.MaxPriceAge = req.MaxPriceAge
+		}
+
+		if e = w.marketStore.Update(ctx, tx, market); e != nil {
+			log.Errorln(e)
+			return e
+		}
+
+		return nil
+	})
+}
+
+func (w *Payee) handleUpdateInterestRateModelEvent(ctx context.Context, p *core.Proposal, req proposal.UpdateInterestRateModelReq, t time.Time) error {
+	return w.transaction(ctx, func(tx *db.DB) error {
+		log := logger.FromContext(ctx).WithField("worker", "update-interest-rate-model")
+
+		model := core.InterestRateModelType(req.Model)
+		if !model.IsValid() {
+			log.Warningln("invalid interest rate model:", req.Model)
+			return skipProposal(ctx)
+		}
+
+		market, isRecordNotFound, e := w.marketStore.FindBySymbol(ctx, strings.ToUpper(req.Symbol))
+		if e != nil {
+			if isRecordNotFound {
+				return skipProposal(ctx)
+			}
+
+			return e
+		}
+
+		// accrue interest with the old model
+		if e = w.marketService.AccrueInterest(ctx, tx, market, t); e != nil {
+			return e
+		}
+
+		market.InterestRateModel = model
+
+		if req.BaseRate.GreaterThan(decimal.Zero) && req.BaseRate.LessThan(decimal.NewFromInt(1)) {
+			market.BaseRate = req.BaseRate
+		}
+
+		if req.Multiplier.GreaterThan(decimal.Zero) && req.Multiplier.LessThan(decimal.NewFromInt(1)) {
+			market.Multiplier = req.Multiplier
+		}
+
+		if req.JumpMultiplier.GreaterThan(decimal.Zero) && req.JumpMultiplier.LessThan(decimal.NewFromInt(1)) {
+			market.JumpMultiplier = req.JumpMultiplier
+		}
+
+		if req.Kink.GreaterThan(decimal.Zero) && compound.IsValidKink(req.Kink) {
 			market.Kink = req.Kink
 		}
 
+		if req.SecondJumpMultiplier.GreaterThan(decimal.Zero) {
+			market.SecondJumpMultiplier = req.SecondJumpMultiplier
+		}
+
+		if req.SecondKink.GreaterThan(market.Kink) && compound.IsValidKink(req.SecondKink) {
+			market.SecondKink = req.SecondKink
+		}
+
+		// refresh the rates with the new model
+		if e = w.marketService.AccrueInterest(ctx, tx, market, t); e != nil {
+			log.Errorln(e)
+			return e
+		}
+
+		return nil
+	})
+}
+
+func (w *Payee) handleUpdateIsolationEvent(ctx context.Context, p *core.Proposal, req proposal.UpdateIsolationReq, t time.Time) error {
+	return w.transaction(ctx, func(tx *db.DB) error {
+		log := logger.FromContext(ctx).WithField("worker", "update-isolation")
+
+		mode := core.IsolationMode(req.IsolationMode)
+		if !mode.IsValid() {
+			log.Warningln("invalid isolation mode:", req.IsolationMode)
+			return skipProposal(ctx)
+		}
+
+		market, isRecordNotFound, e := w.marketStore.FindBySymbol(ctx, strings.ToUpper(req.Symbol))
+		if e != nil {
+			if isRecordNotFound {
+				return skipProposal(ctx)
+			}
+
+			return e
+		}
+
+		market.IsolationMode = mode
+		if req.DebtCeiling.GreaterThanOrEqual(decimal.Zero) {
+			market.DebtCeiling = req.DebtCeiling
+		}
+
 		if e = w.marketStore.Update(ctx, tx, market); e != nil {
 			log.Errorln(e)
 			return e
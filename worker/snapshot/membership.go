@@ -0,0 +1,126 @@
+package snapshot
+
+import (
+	"compound/core"
+	"compound/core/proposal"
+	"compound/pkg/mtg"
+	"context"
+	"time"
+
+	"github.com/fox-one/pkg/logger"
+	"github.com/fox-one/pkg/store/db"
+	"github.com/shopspring/decimal"
+)
+
+// loadMembership load the member set and threshold effective for the output created at t,
+// the configured ones are used before any membership proposal passed
+func (w *Payee) loadMembership(ctx context.Context, t time.Time) error {
+	membership, isRecordNotFound, e := w.membershipStore.FindEffective(ctx, t)
+	if e != nil {
+		if isRecordNotFound {
+			w.members = w.system.Members
+			w.threshold = w.system.Threshold
+			return nil
+		}
+
+		logger.FromContext(ctx).WithError(e).Errorln("memberships.FindEffective")
+		return e
+	}
+
+	members, e := membership.MemberList()
+	if e != nil {
+		return e
+	}
+
+	w.members = members
+	w.threshold = membership.Threshold
+
+	return nil
+}
+
+// handleAddMemberEvent add the member, effective for the outputs created after t
+func (w *Payee) handleAddMemberEvent(ctx context.Context, p *core.Proposal, req proposal.AddMemberReq, t time.Time) error {
+	log := logger.FromContext(ctx).WithField("worker", "add-member")
+
+	verifyKey, e := mtg.DecodePublicKey(req.VerifyKey)
+	if e != nil {
+		log.WithError(e).Warningln("invalid verify key")
+		return nil
+	}
+
+	members := make([]*core.Member, 0, len(w.members)+1)
+	for _, m := range w.members {
+		// the proposal is handled again on the later votes
+		if m.ClientID == req.ClientID {
+			return nil
+		}
+
+		members = append(members, m)
+	}
+
+	weight := req.Weight
+	if weight.LessThanOrEqual(decimal.Zero) {
+		weight = decimal.NewFromInt(1)
+	}
+
+	members = append(members, &core.Member{
+		ClientID:  req.ClientID,
+		VerifyKey: verifyKey,
+		Weight:    weight,
+	})
+
+	return w.saveMembership(ctx, p, members, w.threshold, t)
+}
+
+// handleRemoveMemberEvent remove the member, effective for the outputs created after t
+func (w *Payee) handleRemoveMemberEvent(ctx context.Context, p *core.Proposal, req proposal.RemoveMemberReq, t time.Time) error {
+	log := logger.FromContext(ctx).WithField("worker", "remove-member")
+
+	members := make([]*core.Member, 0, len(w.members))
+	for _, m := range w.members {
+		if m.ClientID != req.ClientID {
+			members = append(members, m)
+		}
+	}
+
+	// the proposal is handled again on the later votes
+	if len(members) == len(w.members) {
+		return nil
+	}
+
+	if !core.IsValidThreshold(w.threshold, len(members)) {
+		log.Warningln("too few members for the threshold:", len(members), w.threshold)
+		return nil
+	}
+
+	return w.saveMembership(ctx, p, members, w.threshold, t)
+}
+
+// handleUpdateThresholdEvent update the threshold, effective for the outputs created after t
+func (w *Payee) handleUpdateThresholdEvent(ctx context.Context, p *core.Proposal, req proposal.UpdateThresholdReq, t time.Time) error {
+	log := logger.FromContext(ctx).WithField("worker", "update-threshold")
+
+	if req.Threshold > 255 || !core.IsValidThreshold(uint8(req.Threshold), len(w.members)) {
+		log.Warningln("invalid threshold:", req.Threshold)
+		return nil
+	}
+
+	threshold := uint8(req.Threshold)
+	if threshold == w.threshold {
+		return nil
+	}
+
+	return w.saveMembership(ctx, p, w.members, threshold, t)
+}
+
+func (w *Payee) saveMembership(ctx context.Context, p *core.Proposal, members []*core.Member, threshold uint8, t time.Time) error {
+	return w.transaction(ctx, func(tx *db.DB) error {
+		membership := core.NewMembership(p.TraceID, members, threshold, t)
+		if e := w.membershipStore.Save(ctx, tx, membership); e != nil {
+			logger.FromContext(ctx).WithError(e).Errorln("memberships.Save")
+			return e
+		}
+
+		return nil
+	})
+}
@@ -2,12 +2,14 @@ package snapshot
 
 import (
 	"compound/core"
+	"compound/pkg/dbtx"
 	"compound/pkg/mtg"
 	"compound/worker"
 	"context"
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/fox-one/pkg/logger"
 	"github.com/fox-one/pkg/property"
@@ -26,27 +28,40 @@ const (
 // Payee payee worker
 type Payee struct {
 	worker.TickWorker
-	db                 *db.DB
-	system             *core.System
-	dapp               *core.Wallet
-	propertyStore      property.Store
-	userStore          core.UserStore
-	outputArchiveStore core.OutputArchiveStore
-	walletStore        core.WalletStore
-	priceStore         core.IPriceStore
-	marketStore        core.IMarketStore
-	supplyStore        core.ISupplyStore
-	borrowStore        core.IBorrowStore
-	proposalStore      core.ProposalStore
-	transactionStore   core.TransactionStore
-	proposalService    core.ProposalService
-	blockService       core.IBlockService
-	priceService       core.IPriceOracleService
-	marketService      core.IMarketService
-	supplyService      core.ISupplyService
-	borrowService      core.IBorrowService
-	accountService     core.IAccountService
-	allowListService   core.IAllowListService
+	db                      *db.DB
+	system                  *core.System
+	dapp                    *core.Wallet
+	propertyStore           property.Store
+	userStore               core.UserStore
+	outputArchiveStore      core.OutputArchiveStore
+	walletStore             core.WalletStore
+	priceStore              core.IPriceStore
+	marketStore             core.IMarketStore
+	supplyStore             core.ISupplyStore
+	borrowStore             core.IBorrowStore
+	proposalStore           core.ProposalStore
+	transactionStore        core.TransactionStore
+	flashLoanStore          core.IFlashLoanStore
+	categoryStore           core.ICategoryStore
+	rewardStore             core.IRewardStore
+	delegationStore         core.IDelegationStore
+	auctionStore            core.IAuctionStore
+	liquidationAuctionStore core.ILiquidationAuctionStore
+	membershipStore         core.IMembershipStore
+	proposalService         core.ProposalService
+	blockService            core.IBlockService
+	priceService            core.IPriceOracleService
+	marketService           core.IMarketService
+	supplyService           core.ISupplyService
+	borrowService           core.IBorrowService
+	accountService          core.IAccountService
+	rewardService           core.IRewardService
+	allowListService        core.IAllowListService
+	// the member set and threshold effective for the output in handling
+	members   []*core.Member
+	threshold uint8
+	// the creation time of the output in handling, the transfers are paid with the member set effective at it
+	outputAt time.Time
 }
 
 // NewPayee new payee
@@ -63,6 +78,13 @@ func NewPayee(db *db.DB,
 	borrowStore core.IBorrowStore,
 	proposalStore core.ProposalStore,
 	transactionStore core.TransactionStore,
+	flashLoanStore core.IFlashLoanStore,
+	categoryStore core.ICategoryStore,
+	rewardStore core.IRewardStore,
+	delegationStore core.IDelegationStore,
+	auctionStore core.IAuctionStore,
+	liquidationAuctionStore core.ILiquidationAuctionStore,
+	membershipStore core.IMembershipStore,
 	proposalService core.ProposalService,
 	priceSrv core.IPriceOracleService,
 	blockService core.IBlockService,
@@ -70,29 +92,40 @@ func NewPayee(db *db.DB,
 	supplyService core.ISupplyService,
 	borrowService core.IBorrowService,
 	accountService core.IAccountService,
+	rewardService core.IRewardService,
 	allowListService core.IAllowListService) *Payee {
 	payee := Payee{
-		db:                 db,
-		system:             system,
-		dapp:               dapp,
-		propertyStore:      propertyStore,
-		userStore:          userStore,
-		outputArchiveStore: outputArchiveStore,
-		walletStore:        walletStore,
-		priceStore:         priceStore,
-		marketStore:        marketStore,
-		supplyStore:        supplyStore,
-		borrowStore:        borrowStore,
-		proposalStore:      proposalStore,
-		transactionStore:   transactionStore,
-		proposalService:    proposalService,
-		priceService:       priceSrv,
-		blockService:       blockService,
-		marketService:      marketSrv,
-		supplyService:      supplyService,
-		borrowService:      borrowService,
-		accountService:     accountService,
-		allowListService:   allowListService,
+		db:                      db,
+		system:                  system,
+		dapp:                    dapp,
+		propertyStore:           propertyStore,
+		userStore:               userStore,
+		outputArchiveStore:      outputArchiveStore,
+		walletStore:             walletStore,
+		priceStore:              priceStore,
+		marketStore:             marketStore,
+		supplyStore:             supplyStore,
+		borrowStore:             borrowStore,
+		proposalStore:           proposalStore,
+		transactionStore:        transactionStore,
+		flashLoanStore:          flashLoanStore,
+		categoryStore:           categoryStore,
+		rewardStore:             rewardStore,
+		delegationStore:         delegationStore,
+		auctionStore:            auctionStore,
+		liquidationAuctionStore: liquidationAuctionStore,
+		membershipStore:         membershipStore,
+		proposalService:         proposalService,
+		priceService:            priceSrv,
+		blockService:            blockService,
+		marketService:           marketSrv,
+		supplyService:           supplyService,
+		borrowService:           borrowService,
+		accountService:          accountService,
+		rewardService:           rewardService,
+		allowListService:        allowListService,
+		members:                 system.Members,
+		threshold:               system.Threshold,
 	}
 
 	return &payee
@@ -167,10 +200,39 @@ func (w *Payee) handleOutput(ctx context.Context, tx *db.DB, output *core.Output
 	log := logger.FromContext(ctx).WithField("output", output.TraceID)
 	ctx = logger.WithContext(ctx, log)
 
+	// all the steps of the output read through the output transaction to see the writes of the earlier steps
+	ctx = dbtx.WithContext(ctx, tx)
+
+	// the member set changed by the proposals is switched at the same output on all nodes
+	if err := w.loadMembership(ctx, output.CreatedAt); err != nil {
+		return err
+	}
+	w.outputAt = output.CreatedAt
+
+	// the collaterals of the flash loans not returned in time are seized before any other action
+	if err := w.handleExpiredFlashLoans(ctx, tx, output); err != nil {
+		return err
+	}
+
+	// the ended reserve auctions are settled before the bids of this output
+	if err := w.handleExpiredAuctions(ctx, tx, output.CreatedAt); err != nil {
+		return err
+	}
+
+	// the proposals not passed in the voting window are expired before the votes of this output
+	if err := w.handleExpiredProposals(ctx, output.CreatedAt); err != nil {
+		return err
+	}
+
+	// the queued proposals whose timelock passed are executed before the votes of this output
+	if err := w.handleQueuedProposals(ctx, output.CreatedAt); err != nil {
+		return err
+	}
+
 	message := w.decodeMemo(output.Memo)
 
 	// handle member vote action
-	if member, body, err := core.DecodeMemberProposalTransactionAction(message, w.system.Members); err == nil {
+	if member, body, err := core.DecodeMemberProposalTransactionAction(message, w.members); err == nil {
 		return w.handleProposalAction(ctx, output, member, body)
 	}
 
@@ -204,7 +266,12 @@ func (w *Payee) handleOutput(ctx context.Context, tx *db.DB, output *core.Output
 		return err
 	}
 
-	return w.handleUserAction(ctx, tx, output, actionType, userID, followID.String(), body)
+	if err = w.handleUserAction(ctx, tx, output, actionType, userID, followID.String(), body); err != nil {
+		return err
+	}
+
+	// the position of the user changed by the action is put up for or taken off the liquidation auction
+	return w.updateLiquidationAuction(ctx, tx, output.TraceID, userID, output.CreatedAt)
 }
 
 func (w *Payee) handleProposalAction(ctx context.Context, output *core.Output, member *core.Member, body []byte) error {
@@ -221,8 +288,12 @@ func (w *Payee) handleProposalAction(ctx context.Context, output *core.Output, m
 
 	if core.ActionType(actionType) == core.ActionTypeProposalVote {
 		return w.handleVoteProposalEvent(ctx, output, member, traceID.String())
+	} else if core.ActionType(actionType) == core.ActionTypeProposalCancel {
+		return w.handleCancelProposalEvent(ctx, output, member, traceID.String())
 	} else if core.ActionType(actionType) == core.ActionTypeProposalProvidePrice {
 		return w.handleProposalProvidePriceEvent(ctx, output, member, traceID.String(), body)
+	} else if core.ActionType(actionType) == core.ActionTypeProposalFundRewards {
+		return w.handleFundRewardsEvent(ctx, output, member, traceID.String())
 	}
 
 	return w.handleCreateProposalEvent(ctx, output, member, core.ActionType(actionType), traceID.String(), body)
@@ -244,6 +315,24 @@ func (w *Payee) handleUserAction(ctx context.Context, tx *db.DB, output *core.Ou
 		return w.handleUnpledgeEvent(ctx, tx, output, userID, followID, body)
 	case core.ActionTypeLiquidate:
 		return w.handleLiquidationEvent(ctx, tx, output, userID, followID, body)
+	case core.ActionTypeFlashLoan:
+		return w.handleFlashLoanEvent(ctx, tx, output, userID, followID, body)
+	case core.ActionTypeFlashLoanRepay:
+		return w.handleFlashLoanRepayEvent(ctx, tx, output, userID, followID, body)
+	case core.ActionTypeClaim:
+		return w.handleClaimEvent(ctx, tx, output, userID, followID, body)
+	case core.ActionTypeDelegate:
+		return w.handleDelegateEvent(ctx, tx, output, userID, followID, body)
+	case core.ActionTypeSwapCollateral:
+		return w.handleSwapCollateralEvent(ctx, tx, output, userID, followID, body)
+	case core.ActionTypeSelfLiquidate:
+		return w.handleSelfLiquidationEvent(ctx, tx, output, userID, followID, body)
+	case core.ActionTypeLeverage:
+		return w.handleLeverageEvent(ctx, tx, output, userID, followID, body)
+	case core.ActionTypeDeleverage:
+		return w.handleDeleverageEvent(ctx, tx, output, userID, followID, body)
+	case core.ActionTypeAuctionBid:
+		return w.handleAuctionBidEvent(ctx, tx, output, userID, followID, body)
 	default:
 		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeRefundTransfer, core.ErrUnknown, "")
 	}
@@ -258,6 +347,7 @@ func (w *Payee) transferOut(ctx context.Context, tx *db.DB, userID, followID, ou
 
 	modifier := fmt.Sprintf("%s.%d", followID, transferAction.Source)
 	transfer := core.Transfer{
+		CreatedAt: w.outputAt,
 		TraceID:   uuidutil.Modify(outputTraceID, modifier),
 		Opponents: []string{userID},
 		Threshold: 1,
@@ -274,6 +364,15 @@ func (w *Payee) transferOut(ctx context.Context, tx *db.DB, userID, followID, ou
 	return nil
 }
 
+// transaction run fn in the transaction of the batch proposal bound to the context, or in a new one
+func (w *Payee) transaction(ctx context.Context, fn func(tx *db.DB) error) error {
+	if tx, ok := dbtx.FromContext(ctx); ok {
+		return fn(tx)
+	}
+
+	return w.db.Tx(fn)
+}
+
 func (w *Payee) decodeMemo(memo string) []byte {
 	if b, err := base64.StdEncoding.DecodeString(memo); err == nil {
 		return b
@@ -3,11 +3,14 @@ package snapshot
 import (
 	"compound/core"
 	"compound/core/proposal"
+	"compound/internal/compound"
 	"compound/pkg/mtg"
 	"context"
 	"database/sql"
 	"encoding/json"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/fox-one/pkg/logger"
 	"github.com/fox-one/pkg/store/db"
@@ -16,7 +19,7 @@ import (
 
 // handle price proposal
 func (w *Payee) handleProposalProvidePriceEvent(ctx context.Context, output *core.Output, member *core.Member, traceID string, body []byte) error {
-	return w.db.Tx(func(tx *db.DB) error {
+	return w.transaction(ctx, func(tx *db.DB) error {
 		log := logger.FromContext(ctx).WithField("worker", "handleProposalProvidePriceEvent")
 		var data proposal.ProvidePriceReq
 		if _, e := mtg.Scan(body, &data); e != nil {
@@ -84,7 +87,7 @@ func (w *Payee) handleProposalProvidePriceEvent(ctx context.Context, output *cor
 		})
 
 		priceLen := len(priceTickers)
-		if priceLen < int(w.system.Threshold) {
+		if priceLen < int(w.threshold) {
 			// less than threshold
 			return nil
 		}
@@ -93,33 +96,14 @@ func (w *Payee) handleProposalProvidePriceEvent(ctx context.Context, output *cor
 			return priceTickers[i].Price.LessThan(priceTickers[j].Price)
 		})
 
-		validPrices := make(map[string]decimal.Decimal)
-
-		// find the valid price
-		for i := 1; i < priceLen; i++ {
-			first := priceTickers[i-1]
-			second := priceTickers[i]
-			delta := second.Price.Sub(first.Price).Abs()
-			changeRatio := delta.Div(first.Price)
-			if changeRatio.LessThan(decimal.NewFromFloat(0.05)) {
-				validPrices[first.Provider] = first.Price
-				validPrices[second.Provider] = first.Price
-			}
-		}
-
-		lenOfValidPrices := len(validPrices)
-		if len(validPrices) < int(w.system.Threshold) {
+		consensusPrice, count := w.priceConsensus(market, priceTickers)
+		if count < int(w.threshold) {
 			// less than threshold
 			return nil
 		}
 
-		// calculate the avg price
-		sumOfPrice := decimal.Zero
-		for _, p := range validPrices {
-			sumOfPrice = sumOfPrice.Add(p)
-		}
-		price.Price = sumOfPrice.Div(decimal.NewFromInt(int64(lenOfValidPrices)))
-
+		price.Price = consensusPrice
+		price.ConsensusVersion = market.PriceConsensusVersion
 		price.PassedAt = sql.NullTime{
 			Time:  output.CreatedAt,
 			Valid: true,
@@ -130,11 +114,22 @@ func (w *Payee) handleProposalProvidePriceEvent(ctx context.Context, output *cor
 		}
 
 		price.Content = bs
+
+		held, e := w.holdPrice(ctx, market, price)
+		if e != nil {
+			return e
+		}
+
 		if e = w.priceStore.Update(ctx, tx, price); e != nil {
 			log.WithError(e).Errorln("update price err")
 			return e
 		}
 
+		if held {
+			log.Warningf("price held, asset:%s, price:%s, new price:%s", market.Symbol, market.Price, price.Price)
+			return nil
+		}
+
 		// accrue interest
 		if e = w.marketService.AccrueInterest(ctx, tx, market, output.CreatedAt); e != nil {
 			return e
@@ -147,6 +142,186 @@ func (w *Payee) handleProposalProvidePriceEvent(ctx context.Context, output *cor
 			return e
 		}
 
+		return w.updateMarketLiquidationAuctions(ctx, tx, output.TraceID, market, output.CreatedAt)
+	})
+}
+
+// priceConsensus agree the price provided by the members by the price consensus of the market,
+// returns the price and the count of the members agreed
+func (w *Payee) priceConsensus(market *core.Market, tickers []*core.PriceTicker) (decimal.Decimal, int) {
+	weights := make(map[string]decimal.Decimal, len(w.members))
+	for _, m := range w.members {
+		weights[m.ClientID] = m.Weight
+	}
+
+	prices := make([]compound.ProvidedPrice, len(tickers))
+	for i, ticker := range tickers {
+		prices[i] = compound.ProvidedPrice{
+			Provider: ticker.Provider,
+			Price:    ticker.Price,
+			Weight:   weights[ticker.Provider],
+		}
+	}
+
+	switch market.PriceConsensus {
+	case core.PriceConsensusTrimmedMedian:
+		return compound.TrimmedMedianConsensus(prices, market.PriceTolerance)
+	case core.PriceConsensusWeightedMedian:
+		return compound.WeightedMedianConsensus(prices, market.PriceTolerance)
+	default:
+		return compound.AdjacencyConsensus(prices, market.PriceTolerance)
+	}
+}
+
+// holdPrice hold the passed price moving more than the max price change from the market price as pending,
+// the pending price is confirmed if the next passed price stays within the max price change of it
+func (w *Payee) holdPrice(ctx context.Context, market *core.Market, price *core.Price) (bool, error) {
+	if !compound.PriceChangeExceeded(market.Price, price.Price, market.MaxPriceChange) {
+		return false, nil
+	}
+
+	last, isRecordNotFound, e := w.priceStore.FindLatestPassed(ctx, market.AssetID, price.BlockNumber)
+	if e != nil && !isRecordNotFound {
+		return false, e
+	}
+
+	if e == nil && last.Status == core.PriceStatusPending && !compound.PriceChangeExceeded(last.Price, price.Price, market.MaxPriceChange) {
+		price.Status = core.PriceStatusConfirmed
+		return false, nil
+	}
+
+	price.Status = core.PriceStatusPending
+	return true, nil
+}
+
+// handleUpdatePriceGuardEvent update the max price change of the market per price block
+func (w *Payee) handleUpdatePriceGuardEvent(ctx context.Context, p *core.Proposal, req proposal.UpdatePriceGuardReq, t time.Time) error {
+	return w.transaction(ctx, func(tx *db.DB) error {
+		log := logger.FromContext(ctx).WithField("worker", "update-price-guard")
+
+		if req.MaxChange.LessThan(decimal.Zero) {
+			log.Warningln("invalid max price change:", req.MaxChange)
+			return skipProposal(ctx)
+		}
+
+		market, isRecordNotFound, e := w.marketStore.FindBySymbol(ctx, strings.ToUpper(req.Symbol))
+		if e != nil {
+			if isRecordNotFound {
+				return skipProposal(ctx)
+			}
+
+			return e
+		}
+
+		market.MaxPriceChange = req.MaxChange
+		if e = w.marketStore.Update(ctx, tx, market); e != nil {
+			log.Errorln(e)
+			return e
+		}
+
+		return nil
+	})
+}
+
+// handleConfirmPriceEvent apply the latest pending price of the market held by the price guard
+func (w *Payee) handleConfirmPriceEvent(ctx context.Context, p *core.Proposal, req proposal.ConfirmPriceReq, t time.Time) error {
+	return w.transaction(ctx, func(tx *db.DB) error {
+		log := logger.FromContext(ctx).WithField("worker", "confirm-price")
+
+		market, isRecordNotFound, e := w.marketStore.FindBySymbol(ctx, strings.ToUpper(req.Symbol))
+		if e != nil {
+			if isRecordNotFound {
+				return nil
+			}
+
+			return e
+		}
+
+		price, isRecordNotFound, e := w.priceStore.FindLatestPassed(ctx, market.AssetID, core.CalculatePriceBlock(t)+1)
+		if e != nil {
+			if isRecordNotFound {
+				return nil
+			}
+
+			return e
+		}
+
+		// confirmed already or superseded by the later price
+		if price.Status != core.PriceStatusPending {
+			log.Warningln("no pending price:", market.Symbol)
+			return nil
+		}
+
+		price.Status = core.PriceStatusConfirmed
+		if e = w.priceStore.Update(ctx, tx, price); e != nil {
+			log.WithError(e).Errorln("update price err")
+			return e
+		}
+
+		if e = w.marketService.AccrueInterest(ctx, tx, market, t); e != nil {
+			return e
+		}
+
+		market.Price = price.Price
+		market.PriceUpdatedAt = price.PassedAt.Time
+		if e = w.marketStore.Update(ctx, tx, market); e != nil {
+			log.WithError(e).Errorln("update market price err")
+			return e
+		}
+
+		return w.updateMarketLiquidationAuctions(ctx, tx, p.TraceID, market, t)
+	})
+}
+
+// handleUpdatePriceConsensusEvent switch the price consensus of the market, the consensus version is increased
+func (w *Payee) handleUpdatePriceConsensusEvent(ctx context.Context, p *core.Proposal, req proposal.UpdatePriceConsensusReq, t time.Time) error {
+	return w.transaction(ctx, func(tx *db.DB) error {
+		log := logger.FromContext(ctx).WithField("worker", "update-price-consensus")
+
+		consensus := core.PriceConsensus(req.Consensus)
+		if !consensus.IsValid() {
+			log.Warningln("invalid price consensus:", req.Consensus)
+			return skipProposal(ctx)
+		}
+
+		if req.Tolerance.LessThan(decimal.Zero) || req.Tolerance.GreaterThanOrEqual(decimal.NewFromInt(1)) {
+			log.Warningln("invalid price tolerance:", req.Tolerance)
+			return skipProposal(ctx)
+		}
+
+		market, isRecordNotFound, e := w.marketStore.FindBySymbol(ctx, strings.ToUpper(req.Symbol))
+		if e != nil {
+			if isRecordNotFound {
+				return skipProposal(ctx)
+			}
+
+			return e
+		}
+
+		// the proposal is handled again on the later votes
+		if market.PriceConsensus == consensus && market.PriceTolerance.Equal(req.Tolerance) {
+			return nil
+		}
+
+		market.PriceConsensus = consensus
+		market.PriceTolerance = req.Tolerance
+		market.PriceConsensusVersion++
+		if e = w.marketStore.Update(ctx, tx, market); e != nil {
+			log.Errorln(e)
+			return e
+		}
+
 		return nil
 	})
 }
+
+// isPriceStale whether the price of any of the markets, or of the markets in the liquidity of the user is stale at t
+func (w *Payee) isPriceStale(ctx context.Context, userID string, t time.Time, markets ...*core.Market) (bool, error) {
+	for _, market := range markets {
+		if market.IsPriceStale(t) {
+			return true, nil
+		}
+	}
+
+	return w.accountService.PriceStale(ctx, userID, t)
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"time"
 
 	"github.com/asaskevich/govalidator"
@@ -28,8 +29,18 @@ func (w *Payee) handleVoteProposalEvent(ctx context.Context, output *core.Output
 		return err
 	}
 
+	if p.IsClosed() {
+		log.Infoln("proposal closed:", p.Status)
+		return nil
+	}
+
 	passed := p.PassedAt.Valid
 
+	// the votes after the voting window are rejected
+	if !passed && p.IsExpired(output.CreatedAt) {
+		return w.expireProposal(ctx, p)
+	}
+
 	if !passed && !govalidator.IsIn(member.ClientID, p.Votes...) {
 		p.Votes = append(p.Votes, member.ClientID)
 		log.Infof("Proposal Voted by %s", member.ClientID)
@@ -39,17 +50,34 @@ func (w *Payee) handleVoteProposalEvent(ctx context.Context, output *core.Output
 			return err
 		}
 
-		if passed = len(p.Votes) >= int(w.system.Threshold); passed {
+		if passed = len(p.Votes) >= int(w.threshold); passed {
 			p.PassedAt = sql.NullTime{
 				Time:  output.CreatedAt,
 				Valid: true,
 			}
+			p.Status = core.ProposalStatusPassed
+
+			// the timelocked proposal is executed by handleQueuedProposals after the delay
+			if delay := w.timelock(p); delay > 0 {
+				p.Status = core.ProposalStatusQueued
+				p.ExecutableAt = sql.NullTime{
+					Time:  output.CreatedAt.Add(delay),
+					Valid: true,
+				}
+			}
 
 			log.Infof("Proposal Approved")
 			if err := w.proposalService.ProposalPassed(ctx, p); err != nil {
 				log.WithError(err).Errorln("notifier.ProposalApproved")
 				return err
 			}
+
+			if p.Status == core.ProposalStatusQueued {
+				if err := w.proposalService.ProposalQueued(ctx, p); err != nil {
+					log.WithError(err).Errorln("notifier.ProposalQueued")
+					return err
+				}
+			}
 		}
 
 		if err := w.proposalStore.Update(ctx, p); err != nil {
@@ -58,8 +86,152 @@ func (w *Payee) handleVoteProposalEvent(ctx context.Context, output *core.Output
 		}
 	}
 
-	if passed {
-		return w.handlePassedProposal(ctx, p, output.CreatedAt)
+	if passed && p.Status == core.ProposalStatusPassed {
+		return w.executeProposal(ctx, p, output.CreatedAt)
+	}
+
+	return nil
+}
+
+// executeProposal execute the passed proposal and mark it executed in the same output transaction,
+// so that it is executed only once
+func (w *Payee) executeProposal(ctx context.Context, p *core.Proposal, t time.Time) error {
+	log := logger.FromContext(ctx)
+
+	if err := w.handlePassedProposal(ctx, p, t); err != nil {
+		return err
+	}
+
+	p.Status = core.ProposalStatusExecuted
+	if err := w.proposalStore.Update(ctx, p); err != nil {
+		log.WithError(err).Errorln("proposals.Update")
+		return err
+	}
+
+	return nil
+}
+
+// handleQueuedProposals execute the queued proposals whose timelock passed before t
+func (w *Payee) handleQueuedProposals(ctx context.Context, t time.Time) error {
+	log := logger.FromContext(ctx).WithField("worker", "timelock")
+
+	proposals, err := w.proposalStore.ListExecutable(ctx, t)
+	if err != nil {
+		log.WithError(err).Errorln("proposals.ListExecutable")
+		return err
+	}
+
+	for _, p := range proposals {
+		if err := w.executeProposal(ctx, p, t); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// handleVetoEvent veto the proposal queued by the timelock
+func (w *Payee) handleVetoEvent(ctx context.Context, p *core.Proposal, req proposal.VetoReq, t time.Time) error {
+	log := logger.FromContext(ctx).WithField("worker", "veto")
+
+	target, isRecordNotFound, err := w.proposalStore.Find(ctx, req.Proposal)
+	if err != nil {
+		if isRecordNotFound {
+			return nil
+		}
+
+		return err
+	}
+
+	// executed already or vetoed on the earlier vote
+	if target.Status != core.ProposalStatusQueued {
+		log.Infoln("proposal not queued:", target.Status)
+		return nil
+	}
+
+	target.Status = core.ProposalStatusVetoed
+	if err := w.proposalStore.Update(ctx, target); err != nil {
+		log.WithError(err).Errorln("proposals.Update")
+		return err
+	}
+
+	if err := w.proposalService.ProposalVetoed(ctx, target); err != nil {
+		log.WithError(err).Errorln("notifier.ProposalVetoed")
+		return err
+	}
+
+	return nil
+}
+
+// handleCancelProposalEvent cancel the proposal not passed yet, only by the creator
+func (w *Payee) handleCancelProposalEvent(ctx context.Context, output *core.Output, member *core.Member, traceID string) error {
+	log := logger.FromContext(ctx).WithField("proposal", traceID)
+
+	p, isRecordNotFound, err := w.proposalStore.Find(ctx, traceID)
+	if err != nil {
+		if isRecordNotFound {
+			log.WithError(err).Debugln("proposal not found")
+			return nil
+		}
+
+		log.WithError(err).Errorln("proposals.Find")
+		return err
+	}
+
+	if p.Creator != member.ClientID {
+		log.Warningln("proposal cancelled by non-creator:", member.ClientID)
+		return nil
+	}
+
+	if p.Status != core.ProposalStatusCreated || p.PassedAt.Valid {
+		log.Infoln("proposal not cancellable:", p.Status)
+		return nil
+	}
+
+	p.Status = core.ProposalStatusCancelled
+	if err := w.proposalStore.Update(ctx, p); err != nil {
+		log.WithError(err).Errorln("proposals.Update")
+		return err
+	}
+
+	if err := w.proposalService.ProposalCancelled(ctx, p); err != nil {
+		log.WithError(err).Errorln("notifier.ProposalCancelled")
+		return err
+	}
+
+	return nil
+}
+
+// handleExpiredProposals expire the proposals not passed in the voting window before t
+func (w *Payee) handleExpiredProposals(ctx context.Context, t time.Time) error {
+	proposals, err := w.proposalStore.ListExpired(ctx, t)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Errorln("proposals.ListExpired")
+		return err
+	}
+
+	for _, p := range proposals {
+		if err := w.expireProposal(ctx, p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (w *Payee) expireProposal(ctx context.Context, p *core.Proposal) error {
+	log := logger.FromContext(ctx).WithField("proposal", p.TraceID)
+
+	p.Status = core.ProposalStatusExpired
+	if err := w.proposalStore.Update(ctx, p); err != nil {
+		log.WithError(err).Errorln("proposals.Update")
+		return err
+	}
+
+	log.Infof("Proposal Expired")
+	if err := w.proposalService.ProposalExpired(ctx, p); err != nil {
+		log.WithError(err).Errorln("notifier.ProposalExpired")
+		return err
 	}
 
 	return nil
@@ -73,83 +245,195 @@ func (w *Payee) handleCreateProposalEvent(ctx context.Context, output *core.Outp
 		AssetID:   output.AssetID,
 		Amount:    output.Amount,
 		Action:    action,
+		Status:    core.ProposalStatusCreated,
 		CreatedAt: output.CreatedAt,
 		UpdatedAt: output.CreatedAt,
 	}
 
-	switch p.Action {
+	if w.system.VoteWindow > 0 {
+		p.ExpiresAt = sql.NullTime{
+			Time:  output.CreatedAt.Add(w.system.VoteWindow),
+			Valid: true,
+		}
+	}
+
+	content, err := w.decodeProposalContent(action, body)
+	if err != nil {
+		log.WithError(err).Warningln("invalid proposal:", action)
+		return nil
+	}
+	p.Content = content
+
+	// the invalid proposal is recorded as rejected with the reason, and never voted
+	reason, err := w.validateProposal(ctx, action, content)
+	if err != nil {
+		log.WithError(err).Errorln("validate proposal error")
+		return err
+	}
+	if reason != "" {
+		log.Infof("proposal %s rejected: %s", p.TraceID, reason)
+		p.Status = core.ProposalStatusRejected
+		p.Reason = reason
+	}
+
+	if err := w.proposalStore.Create(ctx, &p); err != nil {
+		log.WithError(err).Errorln("proposal.create error")
+		return err
+	}
+
+	if err := w.proposalService.ProposalCreated(ctx, &p, member); err != nil {
+		log.WithError(err).Errorln("proposalCreated error")
+		return err
+	}
+
+	return nil
+}
+
+// decodeProposalContent decode the proposal request of the action to json content
+func (w *Payee) decodeProposalContent(action core.ActionType, body []byte) ([]byte, error) {
+	switch action {
 	case core.ActionTypeProposalAddMarket:
 		var content proposal.AddMarketReq
 		if _, err := mtg.Scan(body, &content); err != nil {
-			log.WithError(err).Errorln("decode proposal AddMarket content error")
-			return nil
+			return nil, err
 		}
-		p.Content, _ = json.Marshal(content)
+		return json.Marshal(content)
 	case core.ActionTypeProposalUpdateMarket:
 		var content proposal.UpdateMarketReq
 		if _, err := mtg.Scan(body, &content); err != nil {
-			log.WithError(err).Errorln("decode proposal UpdateMarket content error")
-			return nil
+			return nil, err
 		}
-		p.Content, _ = json.Marshal(content)
+		return json.Marshal(content)
 	case core.ActionTypeProposalUpdateMarketAdvance:
 		var content proposal.UpdateMarketAdvanceReq
 		if _, err := mtg.Scan(body, &content); err != nil {
-			log.WithError(err).Errorln("decode proposal UpdateMarketAdvance content error")
-			return nil
+			return nil, err
+		}
+		return json.Marshal(content)
+	case core.ActionTypeProposalUpdateInterestRateModel:
+		var content proposal.UpdateInterestRateModelReq
+		if _, err := mtg.Scan(body, &content); err != nil {
+			return nil, err
+		}
+		return json.Marshal(content)
+	case core.ActionTypeProposalUpdateIsolation:
+		var content proposal.UpdateIsolationReq
+		if _, err := mtg.Scan(body, &content); err != nil {
+			return nil, err
+		}
+		return json.Marshal(content)
+	case core.ActionTypeProposalUpdateCategory:
+		var content proposal.UpdateCategoryReq
+		if _, err := mtg.Scan(body, &content); err != nil {
+			return nil, err
+		}
+		return json.Marshal(content)
+	case core.ActionTypeProposalSetMarketCategory:
+		var content proposal.MarketCategoryReq
+		if _, err := mtg.Scan(body, &content); err != nil {
+			return nil, err
+		}
+		return json.Marshal(content)
+	case core.ActionTypeProposalUpdateRewardSpeed:
+		var content proposal.UpdateRewardSpeedReq
+		if _, err := mtg.Scan(body, &content); err != nil {
+			return nil, err
+		}
+		return json.Marshal(content)
+	case core.ActionTypeProposalUpdateLiquidationMode:
+		var content proposal.UpdateLiquidationModeReq
+		if _, err := mtg.Scan(body, &content); err != nil {
+			return nil, err
+		}
+		return json.Marshal(content)
+	case core.ActionTypeProposalUpdatePriceGuard:
+		var content proposal.UpdatePriceGuardReq
+		if _, err := mtg.Scan(body, &content); err != nil {
+			return nil, err
+		}
+		return json.Marshal(content)
+	case core.ActionTypeProposalConfirmPrice:
+		var content proposal.ConfirmPriceReq
+		if _, err := mtg.Scan(body, &content); err != nil {
+			return nil, err
+		}
+		return json.Marshal(content)
+	case core.ActionTypeProposalUpdatePriceConsensus:
+		var content proposal.UpdatePriceConsensusReq
+		if _, err := mtg.Scan(body, &content); err != nil {
+			return nil, err
+		}
+		return json.Marshal(content)
+	case core.ActionTypeProposalVeto:
+		var content proposal.VetoReq
+		if _, err := mtg.Scan(body, &content); err != nil {
+			return nil, err
+		}
+		return json.Marshal(content)
+	case core.ActionTypeProposalAddMember:
+		var content proposal.AddMemberReq
+		if _, err := mtg.Scan(body, &content); err != nil {
+			return nil, err
+		}
+		return json.Marshal(content)
+	case core.ActionTypeProposalRemoveMember:
+		var content proposal.RemoveMemberReq
+		if _, err := mtg.Scan(body, &content); err != nil {
+			return nil, err
+		}
+		return json.Marshal(content)
+	case core.ActionTypeProposalUpdateThreshold:
+		var content proposal.UpdateThresholdReq
+		if _, err := mtg.Scan(body, &content); err != nil {
+			return nil, err
 		}
-		p.Content, _ = json.Marshal(content)
+		return json.Marshal(content)
+	case core.ActionTypeProposalStartAuction:
+		var content proposal.StartAuctionReq
+		if _, err := mtg.Scan(body, &content); err != nil {
+			return nil, err
+		}
+		return json.Marshal(content)
 	case core.ActionTypeProposalWithdrawReserves:
 		var content proposal.WithdrawReq
 		if _, err := mtg.Scan(body, &content); err != nil {
-			log.WithError(err).Errorln("decode proposal WithdrawReserves content error")
-			return nil
+			return nil, err
 		}
-		p.Content, _ = json.Marshal(content)
+		return json.Marshal(content)
 	case core.ActionTypeProposalCloseMarket:
 		var content proposal.MarketStatusReq
 		if _, err := mtg.Scan(body, &content); err != nil {
-			log.WithError(err).Errorln("decode proposal closeMarket content error")
-			return nil
+			return nil, err
 		}
-		p.Content, _ = json.Marshal(content)
+		return json.Marshal(content)
 	case core.ActionTypeProposalOpenMarket:
 		var content proposal.MarketStatusReq
 		if _, err := mtg.Scan(body, &content); err != nil {
-			log.WithError(err).Errorln("decode proposal openMarket content error")
-			return nil
+			return nil, err
 		}
-		p.Content, _ = json.Marshal(content)
+		return json.Marshal(content)
 	case core.ActionTypeProposalAddScope, core.ActionTypeProposalRemoveScope:
 		var content proposal.ScopeReq
 		if _, err := mtg.Scan(body, &content); err != nil {
-			log.WithError(err).Errorln("decode proposal scopereq content error")
-			return nil
+			return nil, err
 		}
-		p.Content, _ = json.Marshal(content)
+		return json.Marshal(content)
 	case core.ActionTypeProposalAddAllowList, core.ActionTypeProposalRemoveAllowList:
 		var content proposal.AllowListReq
 		if _, err := mtg.Scan(body, &content); err != nil {
-			log.WithError(err).Errorln("decode proposal allowlist content error")
-			return nil
+			return nil, err
 		}
-		p.Content, _ = json.Marshal(content)
+		return json.Marshal(content)
+	case core.ActionTypeProposalBatch:
+		var content proposal.BatchReq
+		if _, err := mtg.Scan(body, &content); err != nil {
+			return nil, err
+		}
+		return w.decodeBatchContent(content)
 	default:
-		log.Warningln("invalid proposal:", p.Action)
-		return nil
-	}
-
-	if err := w.proposalStore.Create(ctx, &p); err != nil {
-		log.WithError(err).Errorln("proposal.create error")
-		return err
-	}
-
-	if err := w.proposalService.ProposalCreated(ctx, &p, member); err != nil {
-		log.WithError(err).Errorln("proposalCreated error")
-		return err
+		return nil, errors.New("unknown proposal action")
 	}
 
-	return nil
 }
 
 func (w *Payee) handlePassedProposal(ctx context.Context, p *core.Proposal, t time.Time) error {
@@ -169,6 +453,81 @@ func (w *Payee) handlePassedProposal(ctx context.Context, p *core.Proposal, t ti
 		_ = json.Unmarshal(p.Content, &proposalReq)
 		return w.handleUpdateMarketAdvanceEvent(ctx, p, proposalReq, t)
 
+	case core.ActionTypeProposalUpdateInterestRateModel:
+		var proposalReq proposal.UpdateInterestRateModelReq
+		_ = json.Unmarshal(p.Content, &proposalReq)
+		return w.handleUpdateInterestRateModelEvent(ctx, p, proposalReq, t)
+
+	case core.ActionTypeProposalUpdateIsolation:
+		var proposalReq proposal.UpdateIsolationReq
+		_ = json.Unmarshal(p.Content, &proposalReq)
+		return w.handleUpdateIsolationEvent(ctx, p, proposalReq, t)
+
+	case core.ActionTypeProposalUpdateCategory:
+		var proposalReq proposal.UpdateCategoryReq
+		_ = json.Unmarshal(p.Content, &proposalReq)
+		return w.handleUpdateCategoryEvent(ctx, p, proposalReq, t)
+
+	case core.ActionTypeProposalSetMarketCategory:
+		var proposalReq proposal.MarketCategoryReq
+		_ = json.Unmarshal(p.Content, &proposalReq)
+		return w.handleSetMarketCategoryEvent(ctx, p, proposalReq, t)
+
+	case core.ActionTypeProposalUpdateRewardSpeed:
+		var proposalReq proposal.UpdateRewardSpeedReq
+		_ = json.Unmarshal(p.Content, &proposalReq)
+		return w.handleUpdateRewardSpeedEvent(ctx, p, proposalReq, t)
+
+	case core.ActionTypeProposalUpdateLiquidationMode:
+		var proposalReq proposal.UpdateLiquidationModeReq
+		_ = json.Unmarshal(p.Content, &proposalReq)
+		return w.handleUpdateLiquidationModeEvent(ctx, p, proposalReq, t)
+
+	case core.ActionTypeProposalUpdatePriceGuard:
+		var proposalReq proposal.UpdatePriceGuardReq
+		_ = json.Unmarshal(p.Content, &proposalReq)
+		return w.handleUpdatePriceGuardEvent(ctx, p, proposalReq, t)
+
+	case core.ActionTypeProposalConfirmPrice:
+		var proposalReq proposal.ConfirmPriceReq
+		_ = json.Unmarshal(p.Content, &proposalReq)
+		return w.handleConfirmPriceEvent(ctx, p, proposalReq, t)
+
+	case core.ActionTypeProposalUpdatePriceConsensus:
+		var proposalReq proposal.UpdatePriceConsensusReq
+		_ = json.Unmarshal(p.Content, &proposalReq)
+		return w.handleUpdatePriceConsensusEvent(ctx, p, proposalReq, t)
+
+	case core.ActionTypeProposalVeto:
+		var proposalReq proposal.VetoReq
+		_ = json.Unmarshal(p.Content, &proposalReq)
+		return w.handleVetoEvent(ctx, p, proposalReq, t)
+
+	case core.ActionTypeProposalAddMember:
+		var proposalReq proposal.AddMemberReq
+		_ = json.Unmarshal(p.Content, &proposalReq)
+		return w.handleAddMemberEvent(ctx, p, proposalReq, t)
+
+	case core.ActionTypeProposalRemoveMember:
+		var proposalReq proposal.RemoveMemberReq
+		_ = json.Unmarshal(p.Content, &proposalReq)
+		return w.handleRemoveMemberEvent(ctx, p, proposalReq, t)
+
+	case core.ActionTypeProposalUpdateThreshold:
+		var proposalReq proposal.UpdateThresholdReq
+		_ = json.Unmarshal(p.Content, &proposalReq)
+		return w.handleUpdateThresholdEvent(ctx, p, proposalReq, t)
+
+	case core.ActionTypeProposalBatch:
+		var proposalReq proposal.BatchReq
+		_ = json.Unmarshal(p.Content, &proposalReq)
+		return w.handleBatchEvent(ctx, p, proposalReq, t)
+
+	case core.ActionTypeProposalStartAuction:
+		var proposalReq proposal.StartAuctionReq
+		_ = json.Unmarshal(p.Content, &proposalReq)
+		return w.handleStartAuctionEvent(ctx, p, proposalReq, t)
+
 	case core.ActionTypeProposalWithdrawReserves:
 		var proposalReq proposal.WithdrawReq
 		_ = json.Unmarshal(p.Content, &proposalReq)
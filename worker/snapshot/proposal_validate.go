@@ -0,0 +1,446 @@
+package snapshot
+
+import (
+	"compound/core"
+	"compound/core/proposal"
+	"compound/internal/compound"
+	"compound/pkg/mtg"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/shopspring/decimal"
+)
+
+// proposalValidator validate the parameters of the proposal requests at the creation,
+// the markets and categories added by the earlier requests of the batch are taken as existing
+type proposalValidator struct {
+	payee      *Payee
+	symbols    map[string]bool
+	assets     map[string]bool
+	categories map[string]bool
+}
+
+// validateProposal validate the proposal request of the action, returns the reason if invalid
+func (w *Payee) validateProposal(ctx context.Context, action core.ActionType, content []byte) (string, error) {
+	v := proposalValidator{
+		payee:      w,
+		symbols:    map[string]bool{},
+		assets:     map[string]bool{},
+		categories: map[string]bool{},
+	}
+
+	return v.validate(ctx, action, content)
+}
+
+func (v *proposalValidator) validate(ctx context.Context, action core.ActionType, content []byte) (string, error) {
+	switch action {
+	case core.ActionTypeProposalAddMarket:
+		var req proposal.AddMarketReq
+		_ = json.Unmarshal(content, &req)
+		return v.validateAddMarket(ctx, req)
+	case core.ActionTypeProposalUpdateMarket:
+		var req proposal.UpdateMarketReq
+		_ = json.Unmarshal(content, &req)
+		return v.validateUpdateMarket(ctx, req)
+	case core.ActionTypeProposalUpdateMarketAdvance:
+		var req proposal.UpdateMarketAdvanceReq
+		_ = json.Unmarshal(content, &req)
+		return v.validateUpdateMarketAdvance(ctx, req)
+	case core.ActionTypeProposalUpdateInterestRateModel:
+		var req proposal.UpdateInterestRateModelReq
+		_ = json.Unmarshal(content, &req)
+		return v.validateUpdateInterestRateModel(ctx, req)
+	case core.ActionTypeProposalUpdateIsolation:
+		var req proposal.UpdateIsolationReq
+		_ = json.Unmarshal(content, &req)
+		if !core.IsolationMode(req.IsolationMode).IsValid() {
+			return fmt.Sprintf("invalid isolation mode %d", req.IsolationMode), nil
+		}
+		if req.DebtCeiling.LessThan(decimal.Zero) {
+			return fmt.Sprintf("debt ceiling %s negative", req.DebtCeiling), nil
+		}
+		return v.validateSymbol(ctx, req.Symbol)
+	case core.ActionTypeProposalUpdateCategory:
+		var req proposal.UpdateCategoryReq
+		_ = json.Unmarshal(content, &req)
+		if strings.TrimSpace(req.Name) == "" {
+			return "empty category name", nil
+		}
+		// the category collateral factor may exceed the market max, but must be less than 1
+		if req.CollateralFactor.LessThanOrEqual(decimal.Zero) || !compound.IsValidRatio(req.CollateralFactor) {
+			return fmt.Sprintf("category collateral factor %s out of (0, 1)", req.CollateralFactor), nil
+		}
+		if !compound.IsValidLiquidationIncentive(req.LiquidationIncentive) {
+			return fmt.Sprintf("liquidation incentive %s out of [%s, %s]", req.LiquidationIncentive, compound.LiquidationIncentiveMin, compound.LiquidationIncentiveMax), nil
+		}
+		v.categories[strings.ToUpper(req.Name)] = true
+		return "", nil
+	case core.ActionTypeProposalSetMarketCategory:
+		var req proposal.MarketCategoryReq
+		_ = json.Unmarshal(content, &req)
+		return v.validateMarketCategory(ctx, req)
+	case core.ActionTypeProposalUpdateRewardSpeed:
+		var req proposal.UpdateRewardSpeedReq
+		_ = json.Unmarshal(content, &req)
+		if req.SupplySpeed.LessThan(decimal.Zero) || req.BorrowSpeed.LessThan(decimal.Zero) {
+			return fmt.Sprintf("reward speed %s/%s negative", req.SupplySpeed, req.BorrowSpeed), nil
+		}
+		return v.validateSymbol(ctx, req.Symbol)
+	case core.ActionTypeProposalUpdateLiquidationMode:
+		var req proposal.UpdateLiquidationModeReq
+		_ = json.Unmarshal(content, &req)
+		mode := core.LiquidationMode(req.Mode)
+		if !mode.IsValid() {
+			return fmt.Sprintf("invalid liquidation mode %d", req.Mode), nil
+		}
+		if req.Window < 0 || (mode == core.LiquidationModeAuction && req.Window == 0) {
+			return fmt.Sprintf("invalid liquidation auction window %d", req.Window), nil
+		}
+		return v.validateSymbol(ctx, req.Symbol)
+	case core.ActionTypeProposalUpdatePriceGuard:
+		var req proposal.UpdatePriceGuardReq
+		_ = json.Unmarshal(content, &req)
+		if req.MaxChange.LessThan(decimal.Zero) {
+			return fmt.Sprintf("max price change %s negative", req.MaxChange), nil
+		}
+		return v.validateSymbol(ctx, req.Symbol)
+	case core.ActionTypeProposalConfirmPrice:
+		var req proposal.ConfirmPriceReq
+		_ = json.Unmarshal(content, &req)
+		return v.validateSymbol(ctx, req.Symbol)
+	case core.ActionTypeProposalUpdatePriceConsensus:
+		var req proposal.UpdatePriceConsensusReq
+		_ = json.Unmarshal(content, &req)
+		if !core.PriceConsensus(req.Consensus).IsValid() {
+			return fmt.Sprintf("invalid price consensus %d", req.Consensus), nil
+		}
+		if !compound.IsValidRatio(req.Tolerance) {
+			return fmt.Sprintf("price tolerance %s out of [0, 1)", req.Tolerance), nil
+		}
+		return v.validateSymbol(ctx, req.Symbol)
+	case core.ActionTypeProposalVeto:
+		var req proposal.VetoReq
+		_ = json.Unmarshal(content, &req)
+		return v.validateVeto(ctx, req)
+	case core.ActionTypeProposalAddMember:
+		var req proposal.AddMemberReq
+		_ = json.Unmarshal(content, &req)
+		if _, e := mtg.DecodePublicKey(req.VerifyKey); e != nil {
+			return "invalid verify key", nil
+		}
+		if req.Weight.LessThan(decimal.Zero) {
+			return fmt.Sprintf("member weight %s negative", req.Weight), nil
+		}
+		if v.isMember(req.ClientID) {
+			return fmt.Sprintf("member %s exists", req.ClientID), nil
+		}
+		return "", nil
+	case core.ActionTypeProposalRemoveMember:
+		var req proposal.RemoveMemberReq
+		_ = json.Unmarshal(content, &req)
+		if !v.isMember(req.ClientID) {
+			return fmt.Sprintf("member %s not found", req.ClientID), nil
+		}
+		if !core.IsValidThreshold(v.payee.threshold, len(v.payee.members)-1) {
+			return fmt.Sprintf("too few members left for the threshold %d", v.payee.threshold), nil
+		}
+		return "", nil
+	case core.ActionTypeProposalUpdateThreshold:
+		var req proposal.UpdateThresholdReq
+		_ = json.Unmarshal(content, &req)
+		if req.Threshold > 255 || !core.IsValidThreshold(uint8(req.Threshold), len(v.payee.members)) {
+			return fmt.Sprintf("threshold %d out of [1, %d)", req.Threshold, len(v.payee.members)), nil
+		}
+		return "", nil
+	case core.ActionTypeProposalBatch:
+		var req proposal.BatchReq
+		_ = json.Unmarshal(content, &req)
+		for idx, item := range req.Items {
+			reason, e := v.validate(ctx, item.Action, item.Content)
+			if e != nil || reason != "" {
+				return fmt.Sprintf("#%d %s: %s", idx+1, item.Action, reason), e
+			}
+		}
+		return "", nil
+	case core.ActionTypeProposalStartAuction:
+		var req proposal.StartAuctionReq
+		_ = json.Unmarshal(content, &req)
+		return v.validateStartAuction(ctx, req)
+	case core.ActionTypeProposalWithdrawReserves:
+		var req proposal.WithdrawReq
+		_ = json.Unmarshal(content, &req)
+		if !govalidator.IsUUID(req.Asset) {
+			return fmt.Sprintf("invalid asset %s", req.Asset), nil
+		}
+		if !govalidator.IsUUID(req.Opponent) {
+			return fmt.Sprintf("invalid opponent %s", req.Opponent), nil
+		}
+		if req.Amount.Truncate(8).LessThanOrEqual(decimal.Zero) {
+			return fmt.Sprintf("invalid amount %s", req.Amount), nil
+		}
+		return "", nil
+	case core.ActionTypeProposalCloseMarket, core.ActionTypeProposalOpenMarket:
+		var req proposal.MarketStatusReq
+		_ = json.Unmarshal(content, &req)
+		return v.validateAsset(ctx, req.AssetID)
+	case core.ActionTypeProposalAddScope, core.ActionTypeProposalRemoveScope:
+		var req proposal.ScopeReq
+		_ = json.Unmarshal(content, &req)
+		if !core.CheckScope(req.Scope) {
+			return fmt.Sprintf("invalid scope %s", req.Scope), nil
+		}
+		return "", nil
+	case core.ActionTypeProposalAddAllowList, core.ActionTypeProposalRemoveAllowList:
+		var req proposal.AllowListReq
+		_ = json.Unmarshal(content, &req)
+		if !govalidator.IsUUID(req.UserID) {
+			return fmt.Sprintf("invalid user %s", req.UserID), nil
+		}
+		if !core.CheckScope(req.Scope) {
+			return fmt.Sprintf("invalid scope %s", req.Scope), nil
+		}
+		return "", nil
+	}
+
+	return "", nil
+}
+
+func (v *proposalValidator) validateAddMarket(ctx context.Context, req proposal.AddMarketReq) (string, error) {
+	symbol := strings.ToUpper(req.Symbol)
+	if symbol == "" {
+		return "empty symbol", nil
+	}
+
+	if !govalidator.IsUUID(req.AssetID) || !govalidator.IsUUID(req.CTokenAssetID) || req.AssetID == req.CTokenAssetID {
+		return fmt.Sprintf("invalid asset %s or ctoken %s", req.AssetID, req.CTokenAssetID), nil
+	}
+
+	if rewardAsset := v.payee.system.RewardAsset; rewardAsset != "" && (req.AssetID == rewardAsset || req.CTokenAssetID == rewardAsset) {
+		return fmt.Sprintf("reward asset %s can not be listed", rewardAsset), nil
+	}
+
+	if v.symbols[symbol] {
+		return fmt.Sprintf("market %s exists", symbol), nil
+	}
+
+	if _, isRecordNotFound, e := v.payee.marketStore.FindBySymbol(ctx, symbol); !isRecordNotFound {
+		if e != nil {
+			return "", e
+		}
+		return fmt.Sprintf("market %s exists", symbol), nil
+	}
+
+	for _, asset := range []string{req.AssetID, req.CTokenAssetID} {
+		if v.assets[asset] {
+			return fmt.Sprintf("market of asset %s exists", asset), nil
+		}
+
+		if _, isRecordNotFound, e := v.payee.marketStore.Find(ctx, asset); !isRecordNotFound {
+			if e != nil {
+				return "", e
+			}
+			return fmt.Sprintf("market of asset %s exists", asset), nil
+		}
+
+		if _, isRecordNotFound, e := v.payee.marketStore.FindByCToken(ctx, asset); !isRecordNotFound {
+			if e != nil {
+				return "", e
+			}
+			return fmt.Sprintf("market of ctoken %s exists", asset), nil
+		}
+	}
+
+	v.symbols[symbol] = true
+	v.assets[req.AssetID] = true
+	v.assets[req.CTokenAssetID] = true
+
+	return "", nil
+}
+
+// validateUpdateMarket the zero values are left unchanged
+func (v *proposalValidator) validateUpdateMarket(ctx context.Context, req proposal.UpdateMarketReq) (string, error) {
+	if req.InitExchange.LessThan(decimal.Zero) {
+		return fmt.Sprintf("init exchange rate %s negative", req.InitExchange), nil
+	}
+
+	if !compound.IsValidRatio(req.ReserveFactor) {
+		return fmt.Sprintf("reserve factor %s out of [0, 1)", req.ReserveFactor), nil
+	}
+
+	if !req.LiquidationIncentive.IsZero() && !compound.IsValidLiquidationIncentive(req.LiquidationIncentive) {
+		return fmt.Sprintf("liquidation incentive %s out of [%s, %s]", req.LiquidationIncentive, compound.LiquidationIncentiveMin, compound.LiquidationIncentiveMax), nil
+	}
+
+	if !compound.IsValidCollateralFactor(req.CollateralFactor) {
+		return fmt.Sprintf("collateral factor %s out of [0, %s]", req.CollateralFactor, compound.CollateralFactorMax), nil
+	}
+
+	if !compound.IsValidRatio(req.BaseRate) {
+		return fmt.Sprintf("base rate %s out of [0, 1)", req.BaseRate), nil
+	}
+
+	return v.validateSymbol(ctx, req.Symbol)
+}
+
+// validateUpdateMarketAdvance the zero values of the close factor and the multiplier are left unchanged
+func (v *proposalValidator) validateUpdateMarketAdvance(ctx context.Context, req proposal.UpdateMarketAdvanceReq) (string, error) {
+	// the negative supply cap is left unchanged
+	if req.BorrowCap.LessThan(decimal.Zero) {
+		return fmt.Sprintf("borrow cap %s negative", req.BorrowCap), nil
+	}
+
+	if !req.CloseFactor.IsZero() && !compound.IsValidCloseFactor(req.CloseFactor) {
+		return fmt.Sprintf("close factor %s out of (%s, %s]", req.CloseFactor, compound.CloseFactorMin, compound.CloseFactorMax), nil
+	}
+
+	if !compound.IsValidRatio(req.Multiplier) || !compound.IsValidRatio(req.JumpMultiplier) {
+		return fmt.Sprintf("multiplier %s or jump multiplier %s out of [0, 1)", req.Multiplier, req.JumpMultiplier), nil
+	}
+
+	if !compound.IsValidKink(req.Kink) {
+		return fmt.Sprintf("kink %s out of [0, %s]", req.Kink, compound.KinkMax), nil
+	}
+
+	// the negative flash loan fee is left unchanged
+	if req.FlashLoanFee.GreaterThanOrEqual(decimal.Zero) && !compound.IsValidRatio(req.FlashLoanFee) {
+		return fmt.Sprintf("flash loan fee %s out of [0, 1)", req.FlashLoanFee), nil
+	}
+
+	if req.TwapWindow > core.MaxTwapWindow {
+		return fmt.Sprintf("twap window %d over %d", req.TwapWindow, core.MaxTwapWindow), nil
+	}
+
+	return v.validateSymbol(ctx, req.Symbol)
+}
+
+// validateUpdateInterestRateModel the zero values are left unchanged
+func (v *proposalValidator) validateUpdateInterestRateModel(ctx context.Context, req proposal.UpdateInterestRateModelReq) (string, error) {
+	if !core.InterestRateModelType(req.Model).IsValid() {
+		return fmt.Sprintf("invalid interest rate model %d", req.Model), nil
+	}
+
+	if !compound.IsValidRatio(req.BaseRate) || !compound.IsValidRatio(req.Multiplier) || !compound.IsValidRatio(req.JumpMultiplier) {
+		return fmt.Sprintf("base rate %s, multiplier %s or jump multiplier %s out of [0, 1)", req.BaseRate, req.Multiplier, req.JumpMultiplier), nil
+	}
+
+	if req.SecondJumpMultiplier.LessThan(decimal.Zero) {
+		return fmt.Sprintf("second jump multiplier %s negative", req.SecondJumpMultiplier), nil
+	}
+
+	if !compound.IsValidKink(req.Kink) || !compound.IsValidKink(req.SecondKink) {
+		return fmt.Sprintf("kink %s or second kink %s out of [0, %s]", req.Kink, req.SecondKink, compound.KinkMax), nil
+	}
+
+	if !req.SecondKink.IsZero() && !req.Kink.IsZero() && req.SecondKink.LessThanOrEqual(req.Kink) {
+		return fmt.Sprintf("second kink %s not above kink %s", req.SecondKink, req.Kink), nil
+	}
+
+	return v.validateSymbol(ctx, req.Symbol)
+}
+
+func (v *proposalValidator) validateMarketCategory(ctx context.Context, req proposal.MarketCategoryReq) (string, error) {
+	if name := strings.ToUpper(req.Category); name != "" && !v.categories[name] {
+		if _, isRecordNotFound, e := v.payee.categoryStore.Find(ctx, name); e != nil {
+			if isRecordNotFound {
+				return fmt.Sprintf("category %s not found", name), nil
+			}
+			return "", e
+		}
+	}
+
+	return v.validateSymbol(ctx, req.Symbol)
+}
+
+func (v *proposalValidator) validateStartAuction(ctx context.Context, req proposal.StartAuctionReq) (string, error) {
+	auctionType := core.AuctionType(req.Type)
+	if auctionType != core.AuctionTypeEnglish && auctionType != core.AuctionTypeDutch {
+		return fmt.Sprintf("invalid auction type %d", req.Type), nil
+	}
+
+	if req.Amount.Truncate(8).LessThanOrEqual(decimal.Zero) || req.Duration <= 0 || req.EndPrice.LessThanOrEqual(decimal.Zero) {
+		return fmt.Sprintf("invalid auction amount %s, duration %d or end price %s", req.Amount, req.Duration, req.EndPrice), nil
+	}
+
+	if auctionType == core.AuctionTypeDutch && req.StartPrice.LessThan(req.EndPrice) {
+		return fmt.Sprintf("dutch auction start price %s below end price %s", req.StartPrice, req.EndPrice), nil
+	}
+
+	if !govalidator.IsUUID(req.BidAsset) || !govalidator.IsUUID(req.Opponent) {
+		return fmt.Sprintf("invalid bid asset %s or opponent %s", req.BidAsset, req.Opponent), nil
+	}
+
+	return v.validateSymbol(ctx, req.Symbol)
+}
+
+// validateVeto only the proposals not closed or executed of the timelocked actions could be vetoed
+func (v *proposalValidator) validateVeto(ctx context.Context, req proposal.VetoReq) (string, error) {
+	target, isRecordNotFound, e := v.payee.proposalStore.Find(ctx, req.Proposal)
+	if e != nil {
+		if isRecordNotFound {
+			return fmt.Sprintf("proposal %s not found", req.Proposal), nil
+		}
+		return "", e
+	}
+
+	if target.IsClosed() || target.Status == core.ProposalStatusPassed {
+		return fmt.Sprintf("proposal %s not vetoable", req.Proposal), nil
+	}
+
+	if v.payee.timelock(target) <= 0 {
+		return fmt.Sprintf("proposal %s not timelocked", req.Proposal), nil
+	}
+
+	return "", nil
+}
+
+func (v *proposalValidator) validateSymbol(ctx context.Context, symbol string) (string, error) {
+	symbol = strings.ToUpper(symbol)
+	if symbol == "" {
+		return "empty symbol", nil
+	}
+
+	if v.symbols[symbol] {
+		return "", nil
+	}
+
+	if _, isRecordNotFound, e := v.payee.marketStore.FindBySymbol(ctx, symbol); e != nil {
+		if isRecordNotFound {
+			return fmt.Sprintf("market %s not found", symbol), nil
+		}
+		return "", e
+	}
+
+	return "", nil
+}
+
+func (v *proposalValidator) validateAsset(ctx context.Context, assetID string) (string, error) {
+	if !govalidator.IsUUID(assetID) {
+		return fmt.Sprintf("invalid asset %s", assetID), nil
+	}
+
+	if v.assets[assetID] {
+		return "", nil
+	}
+
+	if _, isRecordNotFound, e := v.payee.marketStore.Find(ctx, assetID); e != nil {
+		if isRecordNotFound {
+			return fmt.Sprintf("market of asset %s not found", assetID), nil
+		}
+		return "", e
+	}
+
+	return "", nil
+}
+
+func (v *proposalValidator) isMember(clientID string) bool {
+	for _, m := range v.payee.members {
+		if m.ClientID == clientID {
+			return true
+		}
+	}
+
+	return false
+}
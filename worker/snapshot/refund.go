@@ -27,6 +27,7 @@ func (w *Payee) handleRefundEvent(ctx context.Context, tx *db.DB, output *core.O
 	log.Infof("userID:%s,followID:%s, error_code:%d", userID, followID, errCode)
 
 	transfer := &core.Transfer{
+		CreatedAt: w.outputAt,
 		TraceID:   uuidutil.Modify(output.TraceID, "compound_refund"),
 		Opponents: []string{userID},
 		Threshold: 1,
@@ -0,0 +1,229 @@
+package snapshot
+
+import (
+	"compound/core"
+	"compound/core/proposal"
+	"context"
+	"strings"
+	"time"
+
+	"github.com/fox-one/pkg/logger"
+	"github.com/fox-one/pkg/store/db"
+	"github.com/shopspring/decimal"
+)
+
+// handle claim rewards event
+//
+// the rewards accrued in all the markets are paid in the reward asset out of the reward pool,
+// and the asset carrying the claim action is returned to the user
+func (w *Payee) handleClaimEvent(ctx context.Context, tx *db.DB, output *core.Output, userID, followID string, body []byte) error {
+	log := logger.FromContext(ctx).WithField("worker", "claim")
+
+	if w.system.RewardAsset == "" {
+		log.Warningln("reward asset not configured")
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeClaim, core.ErrNoRewards, "")
+	}
+
+	// the reward asset held by the wallet for a market belongs to the suppliers
+	listed, e := w.isMarketAsset(ctx, w.system.RewardAsset)
+	if e != nil {
+		return e
+	}
+
+	if listed {
+		log.Warningln("reward asset is a market asset:", w.system.RewardAsset)
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeClaim, core.ErrOperationForbidden, "")
+	}
+
+	markets, e := w.marketStore.All(ctx)
+	if e != nil {
+		log.WithError(e).Errorln("list markets error")
+		return e
+	}
+
+	var rewards []*core.Reward
+	for _, market := range markets {
+		if e = w.marketService.AccrueInterest(ctx, tx, market, output.CreatedAt); e != nil {
+			log.Errorln(e)
+			return e
+		}
+
+		reward, e := w.rewardService.Distribute(ctx, tx, market, userID)
+		if e != nil {
+			log.Errorln(e)
+			return e
+		}
+
+		rewards = append(rewards, reward)
+	}
+
+	// the dust less than 1e-8 is left to the next claim
+	claimed := decimal.Zero
+	for _, reward := range rewards {
+		claimed = claimed.Add(reward.Accrued.Truncate(8))
+	}
+
+	if claimed.LessThanOrEqual(decimal.Zero) {
+		log.Warningln("no rewards to claim")
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeClaim, core.ErrNoRewards, "")
+	}
+
+	pool, isRecordNotFound, e := w.rewardStore.FindPool(ctx, w.system.RewardAsset)
+	if e != nil && !isRecordNotFound {
+		log.WithError(e).Errorln("find reward pool error")
+		return e
+	}
+
+	// the rewards are kept accrued until the pool is funded again
+	if isRecordNotFound || pool.Balance.LessThan(claimed) {
+		log.Warningln("insufficient reward pool:", claimed)
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeClaim, core.ErrInsufficientRewardPool, "")
+	}
+
+	for _, reward := range rewards {
+		amount := reward.Accrued.Truncate(8)
+		if amount.LessThanOrEqual(decimal.Zero) {
+			continue
+		}
+
+		reward.Accrued = reward.Accrued.Sub(amount)
+		if e = w.rewardStore.Update(ctx, tx, reward); e != nil {
+			log.Errorln(e)
+			return e
+		}
+	}
+
+	pool.Balance = pool.Balance.Sub(claimed)
+	if e = w.rewardStore.UpdatePool(ctx, tx, pool); e != nil {
+		log.Errorln(e)
+		return e
+	}
+
+	log.Infoln("claim rewards, asset:", w.system.RewardAsset, ":amount:", claimed)
+
+	// add transaction
+	extra := core.NewTransactionExtra()
+	extra.Put(core.TransactionKeyAssetID, w.system.RewardAsset)
+	extra.Put(core.TransactionKeyAmount, claimed)
+	transaction := core.BuildTransactionFromOutput(ctx, userID, followID, core.ActionTypeClaim, output, &extra)
+	if e = w.transactionStore.Create(ctx, tx, transaction); e != nil {
+		log.WithError(e).Errorln("create transaction error")
+		return e
+	}
+
+	// transfer rewards
+	claimAction := core.TransferAction{
+		Source:   core.ActionTypeClaimTransfer,
+		FollowID: followID,
+	}
+	if e = w.transferOut(ctx, tx, userID, followID, output.TraceID, w.system.RewardAsset, claimed, &claimAction); e != nil {
+		return e
+	}
+
+	// return the asset carrying the action
+	refundAction := core.TransferAction{
+		Source:   core.ActionTypeRefundTransfer,
+		FollowID: followID,
+	}
+	return w.transferOut(ctx, tx, userID, followID, output.TraceID, output.AssetID, output.Amount, &refundAction)
+}
+
+// handleFundRewardsEvent add the reward asset carried by the member to the reward pool,
+// the other assets are returned to the member
+func (w *Payee) handleFundRewardsEvent(ctx context.Context, output *core.Output, member *core.Member, traceID string) error {
+	return w.transaction(ctx, func(tx *db.DB) error {
+		log := logger.FromContext(ctx).WithField("worker", "fund-rewards")
+
+		if w.system.RewardAsset == "" || output.AssetID != w.system.RewardAsset {
+			log.Warningln("not the reward asset:", output.AssetID)
+			return w.handleRefundEvent(ctx, tx, output, member.ClientID, traceID, core.ActionTypeProposalFundRewards, core.ErrInvalidArgument, "")
+		}
+
+		listed, e := w.isMarketAsset(ctx, output.AssetID)
+		if e != nil {
+			return e
+		}
+
+		if listed {
+			log.Warningln("reward asset is a market asset:", output.AssetID)
+			return w.handleRefundEvent(ctx, tx, output, member.ClientID, traceID, core.ActionTypeProposalFundRewards, core.ErrOperationForbidden, "")
+		}
+
+		pool, isRecordNotFound, e := w.rewardStore.FindPool(ctx, output.AssetID)
+		if e != nil && !isRecordNotFound {
+			log.WithError(e).Errorln("find reward pool error")
+			return e
+		}
+
+		if isRecordNotFound {
+			pool = &core.RewardPool{
+				AssetID: output.AssetID,
+				Balance: output.Amount,
+			}
+			e = w.rewardStore.SavePool(ctx, tx, pool)
+		} else {
+			pool.Balance = pool.Balance.Add(output.Amount)
+			e = w.rewardStore.UpdatePool(ctx, tx, pool)
+		}
+
+		if e != nil {
+			log.Errorln(e)
+			return e
+		}
+
+		log.Infoln("fund rewards, asset:", output.AssetID, ":amount:", output.Amount, ":balance:", pool.Balance)
+
+		extra := core.NewTransactionExtra()
+		extra.Put(core.TransactionKeyRewardPool, pool.Balance)
+		transaction := core.BuildTransactionFromOutput(ctx, member.ClientID, traceID, core.ActionTypeProposalFundRewards, output, &extra)
+		return w.transactionStore.Create(ctx, tx, transaction)
+	})
+}
+
+// isMarketAsset whether the asset is the underlying asset or the ctoken of a market
+func (w *Payee) isMarketAsset(ctx context.Context, assetID string) (bool, error) {
+	if _, isRecordNotFound, e := w.marketStore.Find(ctx, assetID); !isRecordNotFound {
+		return e == nil, e
+	}
+
+	if _, isRecordNotFound, e := w.marketStore.FindByCToken(ctx, assetID); !isRecordNotFound {
+		return e == nil, e
+	}
+
+	return false, nil
+}
+
+// handleUpdateRewardSpeedEvent update the supply and borrow reward speeds of the market
+func (w *Payee) handleUpdateRewardSpeedEvent(ctx context.Context, p *core.Proposal, req proposal.UpdateRewardSpeedReq, t time.Time) error {
+	return w.transaction(ctx, func(tx *db.DB) error {
+		log := logger.FromContext(ctx).WithField("worker", "update-reward-speed")
+
+		if req.SupplySpeed.LessThan(decimal.Zero) || req.BorrowSpeed.LessThan(decimal.Zero) {
+			log.Warningln("invalid reward speed:", req.SupplySpeed, req.BorrowSpeed)
+			return skipProposal(ctx)
+		}
+
+		market, isRecordNotFound, e := w.marketStore.FindBySymbol(ctx, strings.ToUpper(req.Symbol))
+		if e != nil {
+			if isRecordNotFound {
+				return skipProposal(ctx)
+			}
+
+			return e
+		}
+
+		// the reward indexes are accrued at the previous speeds
+		if e = w.marketService.AccrueInterest(ctx, tx, market, t); e != nil {
+			return e
+		}
+
+		market.SupplyRewardSpeed = req.SupplySpeed
+		market.BorrowRewardSpeed = req.BorrowSpeed
+		if e = w.marketStore.Update(ctx, tx, market); e != nil {
+			log.Errorln(e)
+			return e
+		}
+
+		return nil
+	})
+}
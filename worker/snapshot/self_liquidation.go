@@ -0,0 +1,219 @@
+package snapshot
+
+import (
+	"compound/core"
+	"compound/internal/compound"
+	"compound/pkg/mtg"
+	"context"
+	"errors"
+
+	"github.com/fox-one/pkg/logger"
+	"github.com/fox-one/pkg/store/db"
+	"github.com/gofrs/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// handle self liquidation event
+//
+// the borrower short of liquidity repays the borrow with the own pledged collateral, seized at the oracle price without the liquidation incentive,
+// the seized collateral goes to the reserves of the supply market and the repayment is paid from the reserves of the borrow market,
+// so the reserves of the borrow market are swapped for the collateral at the oracle price and bear its price risk.
+// like the liquidation, at most the close factor of the collateral is seized by each action
+func (w *Payee) handleSelfLiquidationEvent(ctx context.Context, tx *db.DB, output *core.Output, userID, followID string, body []byte) error {
+	log := logger.FromContext(ctx).WithField("worker", "self_liquidation")
+
+	var seizedAsset, repayAsset uuid.UUID
+	var repayAmount decimal.Decimal
+	if _, err := mtg.Scan(body, &seizedAsset, &repayAsset, &repayAmount); err != nil {
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeSelfLiquidate, core.ErrInvalidArgument, "")
+	}
+
+	log.Infof("seizedAsset:%s, repayAsset:%s, repayAmount:%s", seizedAsset.String(), repayAsset.String(), repayAmount)
+
+	if repayAmount.LessThanOrEqual(decimal.Zero) {
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeSelfLiquidate, core.ErrInvalidAmount, "")
+	}
+
+	// check market close status
+	if w.marketService.HasClosedMarkets(ctx) {
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeSelfLiquidate, core.ErrMarketClosed, "")
+	}
+
+	supplyMarket, isRecordNotFound, e := w.marketStore.Find(ctx, seizedAsset.String())
+	if isRecordNotFound {
+		log.Warningln("supply market not found")
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeSelfLiquidate, core.ErrMarketNotFound, "")
+	}
+	if e != nil {
+		log.WithError(e).Errorln("find supply market error")
+		return e
+	}
+
+	borrowMarket := supplyMarket
+	if repayAsset != seizedAsset {
+		borrowMarket, isRecordNotFound, e = w.marketStore.Find(ctx, repayAsset.String())
+		if isRecordNotFound {
+			log.Warningln("borrow market not found")
+			return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeSelfLiquidate, core.ErrMarketNotFound, "")
+		}
+		if e != nil {
+			log.WithError(e).Errorln("find borrow market error")
+			return e
+		}
+	}
+
+	// the prices of all the markets in the liquidity of the user should be fresh
+	stale, e := w.isPriceStale(ctx, userID, output.CreatedAt, supplyMarket, borrowMarket)
+	if e != nil {
+		log.Errorln(e)
+		return e
+	}
+
+	if stale {
+		log.Warningln("price stale")
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeSelfLiquidate, core.ErrPriceStale, "")
+	}
+
+	// accrue interest and distribute rewards before the collaterals and borrow changed
+	markets := []*core.Market{supplyMarket}
+	if borrowMarket != supplyMarket {
+		markets = append(markets, borrowMarket)
+	}
+	for _, market := range markets {
+		if e = w.marketService.AccrueInterest(ctx, tx, market, output.CreatedAt); e != nil {
+			log.Errorln(e)
+			return e
+		}
+
+		if _, e = w.rewardService.Distribute(ctx, tx, market, userID); e != nil {
+			log.Errorln(e)
+			return e
+		}
+	}
+
+	supply, isRecordNotFound, e := w.supplyStore.Find(ctx, userID, supplyMarket.CTokenAssetID)
+	if isRecordNotFound {
+		log.Warningln("supply not found")
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeSelfLiquidate, core.ErrSupplyNotFound, "")
+	}
+	if e != nil {
+		log.WithError(e).Errorln("find supply error")
+		return e
+	}
+
+	borrow, isRecordNotFound, e := w.borrowStore.Find(ctx, userID, borrowMarket.AssetID)
+	if isRecordNotFound {
+		log.Warningln("borrow not found")
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeSelfLiquidate, core.ErrBorrowNotFound, "")
+	}
+	if e != nil {
+		log.WithError(e).Errorln("find borrow error")
+		return e
+	}
+
+	// only the positions could be liquidated are allowed to repay with the reserves
+	if !w.accountService.SeizeTokenAllowed(ctx, supply, borrow, output.CreatedAt) {
+		log.Warningln("seize not allowed")
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeSelfLiquidate, core.ErrSeizeNotAllowed, "")
+	}
+
+	borrowPrice, e := w.priceService.GetCurrentUnderlyingPrice(ctx, borrowMarket)
+	if e != nil {
+		log.Errorln(e)
+		return e
+	}
+
+	supplyPrice, e := w.priceService.GetCurrentUnderlyingPrice(ctx, supplyMarket)
+	if e != nil {
+		log.Errorln(e)
+		return e
+	}
+
+	supplyExchangeRate, e := w.marketService.CurExchangeRate(ctx, supplyMarket)
+	if e != nil {
+		log.Errorln(e)
+		return e
+	}
+
+	borrowBalance, e := w.borrowService.BorrowBalance(ctx, borrow, borrowMarket)
+	if e != nil {
+		log.Errorln(e)
+		return e
+	}
+
+	// the surplus collateral is kept by the user
+	collateral := supply.Collaterals.Mul(supplyExchangeRate).Mul(supplyMarket.CloseFactor)
+	repaidAmount, seizedAmount := compound.SelfLiquidate(decimal.Min(repayAmount, borrowBalance), borrowPrice, collateral, supplyPrice)
+	seizedCTokens := decimal.Min(seizedAmount.Div(supplyExchangeRate).Truncate(16), supply.Collaterals)
+	if repaidAmount.LessThanOrEqual(decimal.Zero) || seizedCTokens.LessThanOrEqual(decimal.Zero) {
+		log.Errorln(errors.New("invalid self liquidation amount"))
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeSelfLiquidate, core.ErrInvalidAmount, "")
+	}
+
+	if repaidAmount.GreaterThan(borrowMarket.Reserves) {
+		log.Warningln("insufficient reserves")
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeSelfLiquidate, core.ErrInsufficientReserves, "")
+	}
+
+	// update supply
+	supply.Collaterals = supply.Collaterals.Sub(seizedCTokens).Truncate(16)
+	if e = w.supplyStore.Update(ctx, tx, supply); e != nil {
+		log.Errorln(e)
+		return e
+	}
+
+	// update borrow
+	newBorrowBalance := borrowBalance.Sub(repaidAmount).Truncate(16)
+	newIndex := borrowMarket.BorrowIndex
+	if newBorrowBalance.LessThanOrEqual(decimal.Zero) {
+		newBorrowBalance = decimal.Zero
+		newIndex = decimal.Zero
+	}
+	principal := borrow.Principal
+	borrow.Principal = newBorrowBalance
+	borrow.InterestIndex = newIndex.Truncate(16)
+	borrow.StableRateBlock = borrowMarket.BlockNumber
+	if e = w.borrowStore.Update(ctx, tx, borrow); e != nil {
+		log.Errorln(e)
+		return e
+	}
+
+	// the seized collateral stays in the pool as the reserves
+	supplyMarket.CTokens = supplyMarket.CTokens.Sub(seizedCTokens).Truncate(16)
+	supplyMarket.Reserves = supplyMarket.Reserves.Add(seizedAmount).Truncate(16)
+
+	// the repayment is paid from the reserves
+	borrowMarket.TotalBorrows = borrowMarket.TotalBorrows.Sub(repaidAmount).Truncate(16)
+	borrowMarket.Reserves = borrowMarket.Reserves.Sub(repaidAmount).Truncate(16)
+	if borrow.IsStable() {
+		borrowMarket.TotalStableBorrows, borrowMarket.AvgStableRate = compound.UpdateStableBorrows(borrowMarket.TotalStableBorrows, borrowMarket.AvgStableRate, borrowBalance, principal, borrow.StableRate, borrow.Principal, borrow.StableRate)
+	}
+
+	for _, market := range markets {
+		if e = w.marketStore.Update(ctx, tx, market); e != nil {
+			log.Errorln(e)
+			return e
+		}
+
+		if e = w.marketService.AccrueInterest(ctx, tx, market, output.CreatedAt); e != nil {
+			log.Errorln(e)
+			return e
+		}
+	}
+
+	// add transaction
+	extra := core.NewTransactionExtra()
+	extra.Put(core.TransactionKeyAssetID, supplyMarket.AssetID)
+	extra.Put(core.TransactionKeyAmount, seizedAmount)
+	extra.Put(core.TransactionKeyPrice, supplyPrice)
+	extra.Put(core.TransactionKeyRepayAssetID, borrowMarket.AssetID)
+	extra.Put(core.TransactionKeyRepayAmount, repaidAmount)
+	transaction := core.BuildTransactionFromOutput(ctx, userID, followID, core.ActionTypeSelfLiquidate, output, &extra)
+	if e = w.transactionStore.Create(ctx, tx, transaction); e != nil {
+		log.WithError(e).Errorln("create transaction error")
+		return e
+	}
+
+	// the debts left without any collaterals are written off
+	return w.handleBadDebts(ctx, tx, output, userID)
+}
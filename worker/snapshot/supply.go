@@ -37,6 +37,20 @@ func (w *Payee) handleSupplyEvent(ctx context.Context, tx *db.DB, output *core.O
 		return e
 	}
 
+	// check supply cap
+	if market.SupplyCap.GreaterThan(decimal.Zero) {
+		totalSupplies, e := w.marketService.CurTotalSupplies(ctx, market)
+		if e != nil {
+			log.Errorln(e)
+			return e
+		}
+
+		if totalSupplies.Add(supplyAmount).GreaterThan(market.SupplyCap) {
+			log.Warningln("supplies over cap")
+			return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeSupply, core.ErrSupplyOverCap, "")
+		}
+	}
+
 	exchangeRate, e := w.marketService.CurExchangeRate(ctx, market)
 	if e != nil {
 		log.Errorln(e)
@@ -42,12 +42,29 @@ func (w *Payee) handlePledgeEvent(ctx context.Context, tx *db.DB, output *core.O
 		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypePledge, core.ErrPledgeNotAllowed, "")
 	}
 
+	allowed, e := w.pledgeAllowedInIsolation(ctx, userID, market)
+	if e != nil {
+		log.Errorln(e)
+		return e
+	}
+
+	if !allowed {
+		log.Errorln(errors.New("isolation mode violated"))
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypePledge, core.ErrIsolationModeViolated, "")
+	}
+
 	//accrue interest
 	if e = w.marketService.AccrueInterest(ctx, tx, market, output.CreatedAt); e != nil {
 		log.Errorln(e)
 		return e
 	}
 
+	// distribute rewards before the collaterals changed
+	if _, e = w.rewardService.Distribute(ctx, tx, market, userID); e != nil {
+		log.Errorln(e)
+		return e
+	}
+
 	supply, isRecordNotFound, e := w.supplyStore.Find(ctx, userID, ctokenAssetID)
 	if e != nil {
 		if isRecordNotFound {
@@ -30,6 +30,18 @@ func (w *Payee) handleRedeemEvent(ctx context.Context, tx *db.DB, output *core.O
 		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeRedeem, core.ErrMarketClosed, "")
 	}
 
+	// the prices of all the markets in the liquidity of the user should be fresh
+	stale, e := w.isPriceStale(ctx, userID, output.CreatedAt, market)
+	if e != nil {
+		log.Errorln(e)
+		return e
+	}
+
+	if stale {
+		log.Warningln("price stale")
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeRedeem, core.ErrPriceStale, "")
+	}
+
 	//accrue interest
 	if e = w.marketService.AccrueInterest(ctx, tx, market, output.CreatedAt); e != nil {
 		log.Errorln(e)
@@ -69,6 +81,12 @@ func (w *Payee) handleRedeemEvent(ctx context.Context, tx *db.DB, output *core.O
 		return e
 	}
 
+	// redeem raises the utilization rate as well
+	if e = w.rebalanceStableBorrows(ctx, tx, market); e != nil {
+		log.Errorln(e)
+		return e
+	}
+
 	// add transaction
 	extra := core.NewTransactionExtra()
 	extra.Put(core.TransactionKeyAssetID, market.AssetID)
@@ -40,6 +40,18 @@ func (w *Payee) handleUnpledgeEvent(ctx context.Context, tx *db.DB, output *core
 		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeUnpledge, core.ErrMarketClosed, "")
 	}
 
+	// the prices of all the markets in the liquidity of the user should be fresh
+	stale, e := w.isPriceStale(ctx, userID, output.CreatedAt, market)
+	if e != nil {
+		log.Errorln(e)
+		return e
+	}
+
+	if stale {
+		log.Warningln("price stale")
+		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeUnpledge, core.ErrPriceStale, "")
+	}
+
 	supply, isRecordNotFound, e := w.supplyStore.Find(ctx, userID, market.CTokenAssetID)
 	if isRecordNotFound {
 		log.Warningln("supply not found")
@@ -56,6 +68,12 @@ func (w *Payee) handleUnpledgeEvent(ctx context.Context, tx *db.DB, output *core
 		return e
 	}
 
+	// distribute rewards before the collaterals changed
+	if _, e = w.rewardService.Distribute(ctx, tx, market, userID); e != nil {
+		log.Errorln(e)
+		return e
+	}
+
 	if unpledgedAmount.GreaterThan(supply.Collaterals) {
 		log.Errorln(errors.New("insufficient collaterals"))
 		return w.handleRefundEvent(ctx, tx, output, userID, followID, core.ActionTypeUnpledge, core.ErrInsufficientCollaterals, "")
@@ -97,6 +115,11 @@ func (w *Payee) handleUnpledgeEvent(ctx context.Context, tx *db.DB, output *core
 		return e
 	}
 
+	// the debts left without any collaterals are written off
+	if e = w.handleBadDebts(ctx, tx, output, userID); e != nil {
+		return e
+	}
+
 	// add transaction
 	extra := core.NewTransactionExtra()
 	extra.Put(core.TransactionKeyCTokenAssetID, ctokenAssetID)
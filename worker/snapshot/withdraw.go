@@ -15,6 +15,7 @@ func (w *Payee) handleWithdrawEvent(ctx context.Context, p *core.Proposal, req p
 	amount := req.Amount.Truncate(8)
 
 	transfer := &core.Transfer{
+		CreatedAt: w.outputAt,
 		TraceID:   p.TraceID,
 		AssetID:   req.Asset,
 		Amount:    amount,
@@ -22,7 +23,7 @@ func (w *Payee) handleWithdrawEvent(ctx context.Context, p *core.Proposal, req p
 		Opponents: []string{req.Opponent},
 	}
 
-	return w.db.Tx(func(tx *db.DB) error {
+	return w.transaction(ctx, func(tx *db.DB) error {
 		if err := w.walletStore.CreateTransfers(ctx, w.db, []*core.Transfer{transfer}); err != nil {
 			log.WithError(err).Errorln("wallets.CreateTransfers")
 			return err
@@ -70,7 +70,10 @@ func (w *Syncer) onWork(ctx context.Context) error {
 			return err
 		}
 		for _, u := range batch {
-			offset = u.UpdatedAt
+			// the backfilled outputs of the new member sets are older than the offset
+			if u.UpdatedAt.After(offset) {
+				offset = u.UpdatedAt
+			}
 
 			p, ok := positions[u.TraceID]
 			if ok {